@@ -1,11 +1,14 @@
 package notion
 
 import (
+	"bytes"
 	"encoding/json"
 	"time"
 )
 
-// Block represents content on the Notion platform.
+// Block represents content on the Notion platform. Decoded blocks are pointers
+// to one of the concrete block types in this file, e.g. `*ParagraphBlock`, and
+// can be inspected using a type switch.
 // See: https://developers.notion.com/reference/block
 type Block interface {
 	ID() string
@@ -18,62 +21,22 @@ type Block interface {
 	Archived() bool
 }
 
-type BlockDTO struct {
-	BaseBlock
-	Type BlockType `json:"type,omitempty"`
-
-	Paragraph        *ParagraphBlock        `json:"paragraph,omitempty"`
-	Heading1         *Heading1Block         `json:"heading_1,omitempty"`
-	Heading2         *Heading2Block         `json:"heading_2,omitempty"`
-	Heading3         *Heading3Block         `json:"heading_3,omitempty"`
-	BulletedListItem *BulletedListItemBlock `json:"bulleted_list_item,omitempty"`
-	NumberedListItem *NumberedListItemBlock `json:"numbered_list_item,omitempty"`
-	ToDo             *ToDoBlock             `json:"to_do,omitempty"`
-	Toggle           *ToggleBlock           `json:"toggle,omitempty"`
-	ChildPage        *ChildPageBlock        `json:"child_page,omitempty"`
-	ChildDatabase    *ChildDatabaseBlock    `json:"child_database,omitempty"`
-	Callout          *CalloutBlock          `json:"callout,omitempty"`
-	Quote            *QuoteBlock            `json:"quote,omitempty"`
-	Code             *CodeBlock             `json:"code,omitempty"`
-	Embed            *EmbedBlock            `json:"embed,omitempty"`
-	Image            *ImageBlock            `json:"image,omitempty"`
-	Audio            *AudioBlock            `json:"audio,omitempty"`
-	Video            *VideoBlock            `json:"video,omitempty"`
-	File             *FileBlock             `json:"file,omitempty"`
-	PDF              *PDFBlock              `json:"pdf,omitempty"`
-	Bookmark         *BookmarkBlock         `json:"bookmark,omitempty"`
-	Equation         *EquationBlock         `json:"equation,omitempty"`
-	Divider          *DividerBlock          `json:"divider,omitempty"`
-	TableOfContents  *TableOfContentsBlock  `json:"table_of_contents,omitempty"`
-	Breadcrumb       *BreadcrumbBlock       `json:"breadcrumb,omitempty"`
-	ColumnList       *ColumnListBlock       `json:"column_list,omitempty"`
-	Column           *ColumnBlock           `json:"column,omitempty"`
-	Table            *TableBlock            `json:"table,omitempty"`
-	TableRow         *TableRowBlock         `json:"table_row,omitempty"`
-	LinkPreview      *LinkPreviewBlock      `json:"link_preview,omitempty"`
-	LinkToPage       *LinkToPageBlock       `json:"link_to_page,omitempty"`
-	SyncedBlock      *SyncedBlock           `json:"synced_block,omitempty"`
-	Template         *TemplateBlock         `json:"template,omitempty"`
-}
-
-// func (b BlockDTO) MarshalJSON() ([]byte, error) {
-// 	return json.Marshal(&b)
-// }
-
 type BlockUser struct {
 	Object string `json:"object"`
 	ID     string `json:"id"`
 }
 
+// BaseBlock contains the fields that are common to all block types. It is set
+// when blocks are decoded from API responses, and is never encoded to JSON.
 type BaseBlock struct {
-	BID             string    `json:"id,omitempty"`
-	BParent         Parent    `json:"parent,omitempty"`
-	BCreatedTime    time.Time `json:"created_time,omitempty"`
-	BCreatedBy      BlockUser `json:"created_by,omitempty"`
-	BLastEditedTime time.Time `json:"last_edited_time,omitempty"`
-	BLastEditedBy   BlockUser `json:"last_edited_by,omitempty"`
-	BHasChildren    bool      `json:"has_children,omitempty"`
-	BArchived       bool      `json:"archived,omitempty"`
+	BID             string    `json:"-"`
+	BParent         Parent    `json:"-"`
+	BCreatedTime    time.Time `json:"-"`
+	BCreatedBy      BlockUser `json:"-"`
+	BLastEditedTime time.Time `json:"-"`
+	BLastEditedBy   BlockUser `json:"-"`
+	BHasChildren    bool      `json:"-"`
+	BArchived       bool      `json:"-"`
 }
 
 // ID returns the identifier (UUIDv4) for the block.
@@ -110,167 +73,656 @@ func (b BaseBlock) Parent() Parent {
 }
 
 type ParagraphBlock struct {
-	RichText []RichText `json:"rich_text"`
-	Children []Block    `json:"children,omitempty"`
-	Color    Color      `json:"color,omitempty"`
-}
-
-type BulletedListItemBlock struct {
-	RichText []RichText `json:"rich_text"`
-	Children []Block    `json:"children,omitempty"`
-	Color    Color      `json:"color,omitempty"`
-}
-
-type NumberedListItemBlock struct {
-	RichText []RichText `json:"rich_text"`
-	Children []Block    `json:"children,omitempty"`
-	Color    Color      `json:"color,omitempty"`
-}
+	BaseBlock
 
-type QuoteBlock struct {
 	RichText []RichText `json:"rich_text"`
 	Children []Block    `json:"children,omitempty"`
 	Color    Color      `json:"color,omitempty"`
 }
 
-type ToggleBlock struct {
-	RichText []RichText `json:"rich_text"`
-	Children []Block    `json:"children,omitempty"`
-	Color    Color      `json:"color,omitempty"`
-}
+// MarshalJSON implements json.Marshaler.
+func (b ParagraphBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias ParagraphBlock
+		dto        struct {
+			Paragraph blockAlias `json:"paragraph"`
+		}
+	)
 
-type TemplateBlock struct {
-	RichText []RichText `json:"rich_text"`
-	Children []Block    `json:"children,omitempty"`
+	return json.Marshal(dto{
+		Paragraph: blockAlias(b),
+	})
 }
 
 type Heading1Block struct {
+	BaseBlock
+
 	RichText     []RichText `json:"rich_text"`
 	Children     []Block    `json:"children,omitempty"`
 	Color        Color      `json:"color,omitempty"`
 	IsToggleable bool       `json:"is_toggleable"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b Heading1Block) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias Heading1Block
+		dto        struct {
+			Heading1 blockAlias `json:"heading_1"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Heading1: blockAlias(b),
+	})
+}
+
 type Heading2Block struct {
+	BaseBlock
+
 	RichText     []RichText `json:"rich_text"`
 	Children     []Block    `json:"children,omitempty"`
 	Color        Color      `json:"color,omitempty"`
 	IsToggleable bool       `json:"is_toggleable"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b Heading2Block) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias Heading2Block
+		dto        struct {
+			Heading2 blockAlias `json:"heading_2"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Heading2: blockAlias(b),
+	})
+}
+
 type Heading3Block struct {
+	BaseBlock
+
 	RichText     []RichText `json:"rich_text"`
 	Children     []Block    `json:"children,omitempty"`
 	Color        Color      `json:"color,omitempty"`
 	IsToggleable bool       `json:"is_toggleable"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b Heading3Block) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias Heading3Block
+		dto        struct {
+			Heading3 blockAlias `json:"heading_3"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Heading3: blockAlias(b),
+	})
+}
+
+type BulletedListItemBlock struct {
+	BaseBlock
+
+	RichText []RichText `json:"rich_text"`
+	Children []Block    `json:"children,omitempty"`
+	Color    Color      `json:"color,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b BulletedListItemBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias BulletedListItemBlock
+		dto        struct {
+			BulletedListItem blockAlias `json:"bulleted_list_item"`
+		}
+	)
+
+	return json.Marshal(dto{
+		BulletedListItem: blockAlias(b),
+	})
+}
+
+type NumberedListItemBlock struct {
+	BaseBlock
+
+	RichText []RichText `json:"rich_text"`
+	Children []Block    `json:"children,omitempty"`
+	Color    Color      `json:"color,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b NumberedListItemBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias NumberedListItemBlock
+		dto        struct {
+			NumberedListItem blockAlias `json:"numbered_list_item"`
+		}
+	)
+
+	return json.Marshal(dto{
+		NumberedListItem: blockAlias(b),
+	})
+}
+
 type ToDoBlock struct {
+	BaseBlock
+
 	RichText []RichText `json:"rich_text"`
 	Children []Block    `json:"children,omitempty"`
 	Checked  *bool      `json:"checked,omitempty"`
 	Color    Color      `json:"color,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b ToDoBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias ToDoBlock
+		dto        struct {
+			ToDo blockAlias `json:"to_do"`
+		}
+	)
+
+	return json.Marshal(dto{
+		ToDo: blockAlias(b),
+	})
+}
+
+type ToggleBlock struct {
+	BaseBlock
+
+	RichText []RichText `json:"rich_text"`
+	Children []Block    `json:"children,omitempty"`
+	Color    Color      `json:"color,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b ToggleBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias ToggleBlock
+		dto        struct {
+			Toggle blockAlias `json:"toggle"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Toggle: blockAlias(b),
+	})
+}
+
 type ChildPageBlock struct {
+	BaseBlock
+
 	Title string `json:"title"`
+
+	// Children is only set by FetchBlockTree, and is never encoded to JSON.
+	Children []Block `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b ChildPageBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias ChildPageBlock
+		dto        struct {
+			ChildPage blockAlias `json:"child_page"`
+		}
+	)
+
+	return json.Marshal(dto{
+		ChildPage: blockAlias(b),
+	})
 }
 
 type ChildDatabaseBlock struct {
+	BaseBlock
+
 	Title string `json:"title"`
+
+	// Children is only set by FetchBlockTree, and is never encoded to JSON.
+	Children []Block `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b ChildDatabaseBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias ChildDatabaseBlock
+		dto        struct {
+			ChildDatabase blockAlias `json:"child_database"`
+		}
+	)
+
+	return json.Marshal(dto{
+		ChildDatabase: blockAlias(b),
+	})
 }
 
 type CalloutBlock struct {
+	BaseBlock
+
 	RichText []RichText `json:"rich_text"`
 	Children []Block    `json:"children,omitempty"`
 	Icon     *Icon      `json:"icon,omitempty"`
 	Color    Color      `json:"color,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b CalloutBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias CalloutBlock
+		dto        struct {
+			Callout blockAlias `json:"callout"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Callout: blockAlias(b),
+	})
+}
+
+type QuoteBlock struct {
+	BaseBlock
+
+	RichText []RichText `json:"rich_text"`
+	Children []Block    `json:"children,omitempty"`
+	Color    Color      `json:"color,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b QuoteBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias QuoteBlock
+		dto        struct {
+			Quote blockAlias `json:"quote"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Quote: blockAlias(b),
+	})
+}
+
 type CodeBlock struct {
+	BaseBlock
+
 	RichText []RichText `json:"rich_text"`
 	Children []Block    `json:"children,omitempty"`
 	Caption  []RichText `json:"caption,omitempty"`
 	Language *string    `json:"language,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b CodeBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias CodeBlock
+		dto        struct {
+			Code blockAlias `json:"code"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Code: blockAlias(b),
+	})
+}
+
 type EmbedBlock struct {
+	BaseBlock
+
 	URL string `json:"url"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b EmbedBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias EmbedBlock
+		dto        struct {
+			Embed blockAlias `json:"embed"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Embed: blockAlias(b),
+	})
+}
+
 type ImageBlock struct {
+	BaseBlock
+
 	Type     FileType      `json:"type"`
 	File     *FileFile     `json:"file,omitempty"`
 	External *FileExternal `json:"external,omitempty"`
 	Caption  []RichText    `json:"caption,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b ImageBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias ImageBlock
+		dto        struct {
+			Image blockAlias `json:"image"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Image: blockAlias(b),
+	})
+}
+
 type AudioBlock struct {
+	BaseBlock
+
 	Type     FileType      `json:"type"`
 	File     *FileFile     `json:"file,omitempty"`
 	External *FileExternal `json:"external,omitempty"`
 	Caption  []RichText    `json:"caption,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b AudioBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias AudioBlock
+		dto        struct {
+			Audio blockAlias `json:"audio"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Audio: blockAlias(b),
+	})
+}
+
 type VideoBlock struct {
+	BaseBlock
+
 	Type     FileType      `json:"type"`
 	File     *FileFile     `json:"file,omitempty"`
 	External *FileExternal `json:"external,omitempty"`
 	Caption  []RichText    `json:"caption,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b VideoBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias VideoBlock
+		dto        struct {
+			Video blockAlias `json:"video"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Video: blockAlias(b),
+	})
+}
+
 type FileBlock struct {
+	BaseBlock
+
 	Type     FileType      `json:"type"`
 	File     *FileFile     `json:"file,omitempty"`
 	External *FileExternal `json:"external,omitempty"`
 	Caption  []RichText    `json:"caption,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b FileBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias FileBlock
+		dto        struct {
+			File blockAlias `json:"file"`
+		}
+	)
+
+	return json.Marshal(dto{
+		File: blockAlias(b),
+	})
+}
+
 type PDFBlock struct {
+	BaseBlock
+
 	Type     FileType      `json:"type"`
 	File     *FileFile     `json:"file,omitempty"`
 	External *FileExternal `json:"external,omitempty"`
 	Caption  []RichText    `json:"caption,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b PDFBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias PDFBlock
+		dto        struct {
+			PDF blockAlias `json:"pdf"`
+		}
+	)
+
+	return json.Marshal(dto{
+		PDF: blockAlias(b),
+	})
+}
+
 type BookmarkBlock struct {
+	BaseBlock
+
 	URL     string     `json:"url"`
 	Caption []RichText `json:"caption,omitempty"`
 }
 
-type EquationBlock struct {
-	Expression string `json:"expression"`
+// MarshalJSON implements json.Marshaler.
+func (b BookmarkBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias BookmarkBlock
+		dto        struct {
+			Bookmark blockAlias `json:"bookmark"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Bookmark: blockAlias(b),
+	})
+}
+
+type EquationBlock struct {
+	BaseBlock
+
+	Expression string `json:"expression"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b EquationBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias EquationBlock
+		dto        struct {
+			Equation blockAlias `json:"equation"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Equation: blockAlias(b),
+	})
+}
+
+type DividerBlock struct {
+	BaseBlock
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b DividerBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias DividerBlock
+		dto        struct {
+			Divider blockAlias `json:"divider"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Divider: blockAlias(b),
+	})
+}
+
+type TableOfContentsBlock struct {
+	BaseBlock
+
+	Color Color `json:"color,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b TableOfContentsBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias TableOfContentsBlock
+		dto        struct {
+			TableOfContents blockAlias `json:"table_of_contents"`
+		}
+	)
+
+	return json.Marshal(dto{
+		TableOfContents: blockAlias(b),
+	})
+}
+
+type BreadcrumbBlock struct {
+	BaseBlock
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b BreadcrumbBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias BreadcrumbBlock
+		dto        struct {
+			Breadcrumb blockAlias `json:"breadcrumb"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Breadcrumb: blockAlias(b),
+	})
 }
 
 type ColumnListBlock struct {
+	BaseBlock
+
 	Children []ColumnBlock `json:"children,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b ColumnListBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias ColumnListBlock
+		dto        struct {
+			ColumnList blockAlias `json:"column_list"`
+		}
+	)
+
+	return json.Marshal(dto{
+		ColumnList: blockAlias(b),
+	})
+}
+
 type ColumnBlock struct {
+	BaseBlock
+
 	Children []Block `json:"children,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b ColumnBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias ColumnBlock
+		dto        struct {
+			Column blockAlias `json:"column"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Column: blockAlias(b),
+	})
+}
+
 type TableBlock struct {
+	BaseBlock
+
 	TableWidth      int     `json:"table_width"`
 	HasColumnHeader bool    `json:"has_column_header"`
 	HasRowHeader    bool    `json:"has_row_header"`
 	Children        []Block `json:"children,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b TableBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias TableBlock
+		dto        struct {
+			Table blockAlias `json:"table"`
+		}
+	)
+
+	return json.Marshal(dto{
+		Table: blockAlias(b),
+	})
+}
+
 type TableRowBlock struct {
+	BaseBlock
+
 	Cells [][]RichText `json:"cells"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b TableRowBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias TableRowBlock
+		dto        struct {
+			TableRow blockAlias `json:"table_row"`
+		}
+	)
+
+	return json.Marshal(dto{
+		TableRow: blockAlias(b),
+	})
+}
+
 type LinkPreviewBlock struct {
+	BaseBlock
+
 	URL string `json:"url"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b LinkPreviewBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias LinkPreviewBlock
+		dto        struct {
+			LinkPreview blockAlias `json:"link_preview"`
+		}
+	)
+
+	return json.Marshal(dto{
+		LinkPreview: blockAlias(b),
+	})
+}
+
 type LinkToPageBlock struct {
+	BaseBlock
+
 	Type       LinkToPageType `json:"type"`
 	PageID     string         `json:"page_id,omitempty"`
 	DatabaseID string         `json:"database_id,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (b LinkToPageBlock) MarshalJSON() ([]byte, error) {
+	type (
+		blockAlias LinkToPageBlock
+		dto        struct {
+			LinkToPage blockAlias `json:"link_to_page"`
+		}
+	)
+
+	return json.Marshal(dto{
+		LinkToPage: blockAlias(b),
+	})
+}
+
 type LinkToPageType string
 
 const (
@@ -308,62 +760,33 @@ type SyncedFromType string
 
 const SyncedFromTypeBlockID SyncedFromType = "block_id"
 
-type DividerBlock struct {
-	BaseBlock
-}
-
-// MarshalJSON implements json.Marshaler.
-func (b DividerBlock) MarshalJSON() ([]byte, error) {
-	type (
-		blockAlias DividerBlock
-		dto        struct {
-			Divider blockAlias `json:"divider"`
-		}
-	)
-
-	return json.Marshal(dto{
-		Divider: blockAlias(b),
-	})
-}
-
-type TableOfContentsBlock struct {
+type TemplateBlock struct {
 	BaseBlock
 
-	Color Color `json:"color,omitempty"`
+	RichText []RichText `json:"rich_text"`
+	Children []Block    `json:"children,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler.
-func (b TableOfContentsBlock) MarshalJSON() ([]byte, error) {
+func (b TemplateBlock) MarshalJSON() ([]byte, error) {
 	type (
-		blockAlias TableOfContentsBlock
+		blockAlias TemplateBlock
 		dto        struct {
-			TableOfContents blockAlias `json:"table_of_contents"`
+			Template blockAlias `json:"template"`
 		}
 	)
 
 	return json.Marshal(dto{
-		TableOfContents: blockAlias(b),
+		Template: blockAlias(b),
 	})
 }
 
-type BreadcrumbBlock struct {
+// UnsupportedBlock is used for block types that are not supported by the
+// Notion API, or by this package.
+type UnsupportedBlock struct {
 	BaseBlock
 }
 
-// MarshalJSON implements json.Marshaler.
-func (b BreadcrumbBlock) MarshalJSON() ([]byte, error) {
-	type (
-		blockAlias BreadcrumbBlock
-		dto        struct {
-			Breadcrumb blockAlias `json:"breadcrumb"`
-		}
-	)
-
-	return json.Marshal(dto{
-		Breadcrumb: blockAlias(b),
-	})
-}
-
 type BlockType string
 
 const (
@@ -402,6 +825,41 @@ const (
 	BlockTypeUnsupported      BlockType = "unsupported"
 )
 
+var knownBlockTypes = map[BlockType]bool{
+	BlockTypeParagraph:        true,
+	BlockTypeHeading1:         true,
+	BlockTypeHeading2:         true,
+	BlockTypeHeading3:         true,
+	BlockTypeBulletedListItem: true,
+	BlockTypeNumberedListItem: true,
+	BlockTypeToDo:             true,
+	BlockTypeToggle:           true,
+	BlockTypeChildPage:        true,
+	BlockTypeChildDatabase:    true,
+	BlockTypeCallout:          true,
+	BlockTypeQuote:            true,
+	BlockTypeCode:             true,
+	BlockTypeEmbed:            true,
+	BlockTypeImage:            true,
+	BlockTypeAudio:            true,
+	BlockTypeVideo:            true,
+	BlockTypeFile:             true,
+	BlockTypePDF:              true,
+	BlockTypeBookmark:         true,
+	BlockTypeEquation:         true,
+	BlockTypeDivider:          true,
+	BlockTypeTableOfContents:  true,
+	BlockTypeBreadCrumb:       true,
+	BlockTypeColumnList:       true,
+	BlockTypeColumn:           true,
+	BlockTypeTable:            true,
+	BlockTypeTableRow:         true,
+	BlockTypeLinkPreview:      true,
+	BlockTypeLinkToPage:       true,
+	BlockTypeSyncedBlock:      true,
+	BlockTypeTemplate:         true,
+}
+
 type PaginationQuery struct {
 	StartCursor string
 	PageSize    int
@@ -432,12 +890,595 @@ func (resp *BlockChildrenResponse) UnmarshalJSON(b []byte) error {
 	resp.Results = make([]Block, len(dto.Results))
 
 	for i, blockDTO := range dto.Results {
-		resp.Results[i] = blockDTO
+		resp.Results[i] = blockDTO.Block()
 	}
 
 	return nil
 }
 
-func (dto BlockDTO) Block() Block {
+// BlockDTO is the JSON representation of a block, as used in API responses.
+// Use its Block method to get the concrete block value.
+type BlockDTO struct {
+	ID             string     `json:"id,omitempty"`
+	Parent         *Parent    `json:"parent,omitempty"`
+	CreatedTime    *time.Time `json:"created_time,omitempty"`
+	CreatedBy      *BlockUser `json:"created_by,omitempty"`
+	LastEditedTime *time.Time `json:"last_edited_time,omitempty"`
+	LastEditedBy   *BlockUser `json:"last_edited_by,omitempty"`
+	HasChildren    bool       `json:"has_children,omitempty"`
+	Archived       bool       `json:"archived,omitempty"`
+	Type           BlockType  `json:"type,omitempty"`
+
+	Paragraph        *ParagraphBlock        `json:"paragraph,omitempty"`
+	Heading1         *Heading1Block         `json:"heading_1,omitempty"`
+	Heading2         *Heading2Block         `json:"heading_2,omitempty"`
+	Heading3         *Heading3Block         `json:"heading_3,omitempty"`
+	BulletedListItem *BulletedListItemBlock `json:"bulleted_list_item,omitempty"`
+	NumberedListItem *NumberedListItemBlock `json:"numbered_list_item,omitempty"`
+	ToDo             *ToDoBlock             `json:"to_do,omitempty"`
+	Toggle           *ToggleBlock           `json:"toggle,omitempty"`
+	ChildPage        *ChildPageBlock        `json:"child_page,omitempty"`
+	ChildDatabase    *ChildDatabaseBlock    `json:"child_database,omitempty"`
+	Callout          *CalloutBlock          `json:"callout,omitempty"`
+	Quote            *QuoteBlock            `json:"quote,omitempty"`
+	Code             *CodeBlock             `json:"code,omitempty"`
+	Embed            *EmbedBlock            `json:"embed,omitempty"`
+	Image            *ImageBlock            `json:"image,omitempty"`
+	Audio            *AudioBlock            `json:"audio,omitempty"`
+	Video            *VideoBlock            `json:"video,omitempty"`
+	File             *FileBlock             `json:"file,omitempty"`
+	PDF              *PDFBlock              `json:"pdf,omitempty"`
+	Bookmark         *BookmarkBlock         `json:"bookmark,omitempty"`
+	Equation         *EquationBlock         `json:"equation,omitempty"`
+	Divider          *DividerBlock          `json:"divider,omitempty"`
+	TableOfContents  *TableOfContentsBlock  `json:"table_of_contents,omitempty"`
+	Breadcrumb       *BreadcrumbBlock       `json:"breadcrumb,omitempty"`
+	ColumnList       *ColumnListBlock       `json:"column_list,omitempty"`
+	Column           *ColumnBlock           `json:"column,omitempty"`
+	Table            *TableBlock            `json:"table,omitempty"`
+	TableRow         *TableRowBlock         `json:"table_row,omitempty"`
+	LinkPreview      *LinkPreviewBlock      `json:"link_preview,omitempty"`
+	LinkToPage       *LinkToPageBlock       `json:"link_to_page,omitempty"`
+	SyncedBlock      *SyncedBlock           `json:"synced_block,omitempty"`
+	Template         *TemplateBlock         `json:"template,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Nested block children, as found
+// in request bodies, are decoded recursively. When the `type` field is absent,
+// it's derived from the block type specific field.
+func (dto *BlockDTO) UnmarshalJSON(b []byte) error {
+	type blockDTOAlias BlockDTO
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	// The `children` fields of block types are interface values, which can't
+	// be decoded directly, so they are decoded separately.
+	var children []Block
+
+	for key, value := range raw {
+		if !knownBlockTypes[BlockType(key)] || !bytes.Contains(value, []byte(`"children"`)) {
+			continue
+		}
+
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return err
+		}
+		rawChildren, ok := fields["children"]
+		if !ok {
+			continue
+		}
+
+		var childDTOs []BlockDTO
+		if err := json.Unmarshal(rawChildren, &childDTOs); err != nil {
+			return err
+		}
+		children = make([]Block, len(childDTOs))
+		for i, childDTO := range childDTOs {
+			children[i] = childDTO.Block()
+		}
+
+		delete(fields, "children")
+		value, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		raw[key] = value
+
+		if b, err = json.Marshal(raw); err != nil {
+			return err
+		}
+	}
+
+	var alias blockDTOAlias
+	if err := json.Unmarshal(b, &alias); err != nil {
+		return err
+	}
+	*dto = BlockDTO(alias)
+
+	if dto.Type == "" {
+		for key := range raw {
+			if knownBlockTypes[BlockType(key)] {
+				dto.Type = BlockType(key)
+				break
+			}
+		}
+	}
+
+	if children != nil {
+		block := dto.Block()
+		setBlockChildren(block, children)
+		*dto = NewBlockDTO(block)
+	}
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. Block metadata is encoded alongside
+// the block type specific field, like in API responses.
+func (dto BlockDTO) MarshalJSON() ([]byte, error) {
+	type metadataDTO struct {
+		Object         string     `json:"object,omitempty"`
+		ID             string     `json:"id,omitempty"`
+		Parent         *Parent    `json:"parent,omitempty"`
+		CreatedTime    *time.Time `json:"created_time,omitempty"`
+		CreatedBy      *BlockUser `json:"created_by,omitempty"`
+		LastEditedTime *time.Time `json:"last_edited_time,omitempty"`
+		LastEditedBy   *BlockUser `json:"last_edited_by,omitempty"`
+		HasChildren    bool       `json:"has_children"`
+		Archived       bool       `json:"archived"`
+		Type           BlockType  `json:"type,omitempty"`
+	}
+
+	metadata := metadataDTO{
+		ID:             dto.ID,
+		Parent:         dto.Parent,
+		CreatedTime:    dto.CreatedTime,
+		CreatedBy:      dto.CreatedBy,
+		LastEditedTime: dto.LastEditedTime,
+		LastEditedBy:   dto.LastEditedBy,
+		HasChildren:    dto.HasChildren,
+		Archived:       dto.Archived,
+		Type:           dto.Type,
+	}
+	if dto.ID != "" {
+		metadata.Object = "block"
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	blockJSON, err := json.Marshal(dto.Block())
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge both JSON objects.
+	blockJSON = bytes.TrimSpace(blockJSON)
+	if len(blockJSON) <= 2 {
+		return metadataJSON, nil
+	}
+
+	merged := append(metadataJSON[:len(metadataJSON)-1], ',')
+	return append(merged, blockJSON[1:]...), nil
+}
+
+// NewBlockDTO returns the JSON representation of a block, including its
+// metadata.
+func NewBlockDTO(block Block) BlockDTO {
+	dto := BlockDTO{
+		ID:          block.ID(),
+		HasChildren: block.HasChildren(),
+		Archived:    block.Archived(),
+	}
+
+	if parent := block.Parent(); parent != (Parent{}) {
+		dto.Parent = &parent
+	}
+	if t := block.CreatedTime(); !t.IsZero() {
+		dto.CreatedTime = &t
+	}
+	if user := block.CreatedBy(); user != (BlockUser{}) {
+		dto.CreatedBy = &user
+	}
+	if t := block.LastEditedTime(); !t.IsZero() {
+		dto.LastEditedTime = &t
+	}
+	if user := block.LastEditedBy(); user != (BlockUser{}) {
+		dto.LastEditedBy = &user
+	}
+
+	switch b := block.(type) {
+	case *ParagraphBlock:
+		dto.Type, dto.Paragraph = BlockTypeParagraph, b
+	case ParagraphBlock:
+		dto.Type, dto.Paragraph = BlockTypeParagraph, &b
+	case *Heading1Block:
+		dto.Type, dto.Heading1 = BlockTypeHeading1, b
+	case Heading1Block:
+		dto.Type, dto.Heading1 = BlockTypeHeading1, &b
+	case *Heading2Block:
+		dto.Type, dto.Heading2 = BlockTypeHeading2, b
+	case Heading2Block:
+		dto.Type, dto.Heading2 = BlockTypeHeading2, &b
+	case *Heading3Block:
+		dto.Type, dto.Heading3 = BlockTypeHeading3, b
+	case Heading3Block:
+		dto.Type, dto.Heading3 = BlockTypeHeading3, &b
+	case *BulletedListItemBlock:
+		dto.Type, dto.BulletedListItem = BlockTypeBulletedListItem, b
+	case BulletedListItemBlock:
+		dto.Type, dto.BulletedListItem = BlockTypeBulletedListItem, &b
+	case *NumberedListItemBlock:
+		dto.Type, dto.NumberedListItem = BlockTypeNumberedListItem, b
+	case NumberedListItemBlock:
+		dto.Type, dto.NumberedListItem = BlockTypeNumberedListItem, &b
+	case *ToDoBlock:
+		dto.Type, dto.ToDo = BlockTypeToDo, b
+	case ToDoBlock:
+		dto.Type, dto.ToDo = BlockTypeToDo, &b
+	case *ToggleBlock:
+		dto.Type, dto.Toggle = BlockTypeToggle, b
+	case ToggleBlock:
+		dto.Type, dto.Toggle = BlockTypeToggle, &b
+	case *ChildPageBlock:
+		dto.Type, dto.ChildPage = BlockTypeChildPage, b
+	case ChildPageBlock:
+		dto.Type, dto.ChildPage = BlockTypeChildPage, &b
+	case *ChildDatabaseBlock:
+		dto.Type, dto.ChildDatabase = BlockTypeChildDatabase, b
+	case ChildDatabaseBlock:
+		dto.Type, dto.ChildDatabase = BlockTypeChildDatabase, &b
+	case *CalloutBlock:
+		dto.Type, dto.Callout = BlockTypeCallout, b
+	case CalloutBlock:
+		dto.Type, dto.Callout = BlockTypeCallout, &b
+	case *QuoteBlock:
+		dto.Type, dto.Quote = BlockTypeQuote, b
+	case QuoteBlock:
+		dto.Type, dto.Quote = BlockTypeQuote, &b
+	case *CodeBlock:
+		dto.Type, dto.Code = BlockTypeCode, b
+	case CodeBlock:
+		dto.Type, dto.Code = BlockTypeCode, &b
+	case *EmbedBlock:
+		dto.Type, dto.Embed = BlockTypeEmbed, b
+	case EmbedBlock:
+		dto.Type, dto.Embed = BlockTypeEmbed, &b
+	case *ImageBlock:
+		dto.Type, dto.Image = BlockTypeImage, b
+	case ImageBlock:
+		dto.Type, dto.Image = BlockTypeImage, &b
+	case *AudioBlock:
+		dto.Type, dto.Audio = BlockTypeAudio, b
+	case AudioBlock:
+		dto.Type, dto.Audio = BlockTypeAudio, &b
+	case *VideoBlock:
+		dto.Type, dto.Video = BlockTypeVideo, b
+	case VideoBlock:
+		dto.Type, dto.Video = BlockTypeVideo, &b
+	case *FileBlock:
+		dto.Type, dto.File = BlockTypeFile, b
+	case FileBlock:
+		dto.Type, dto.File = BlockTypeFile, &b
+	case *PDFBlock:
+		dto.Type, dto.PDF = BlockTypePDF, b
+	case PDFBlock:
+		dto.Type, dto.PDF = BlockTypePDF, &b
+	case *BookmarkBlock:
+		dto.Type, dto.Bookmark = BlockTypeBookmark, b
+	case BookmarkBlock:
+		dto.Type, dto.Bookmark = BlockTypeBookmark, &b
+	case *EquationBlock:
+		dto.Type, dto.Equation = BlockTypeEquation, b
+	case EquationBlock:
+		dto.Type, dto.Equation = BlockTypeEquation, &b
+	case *DividerBlock:
+		dto.Type, dto.Divider = BlockTypeDivider, b
+	case DividerBlock:
+		dto.Type, dto.Divider = BlockTypeDivider, &b
+	case *TableOfContentsBlock:
+		dto.Type, dto.TableOfContents = BlockTypeTableOfContents, b
+	case TableOfContentsBlock:
+		dto.Type, dto.TableOfContents = BlockTypeTableOfContents, &b
+	case *BreadcrumbBlock:
+		dto.Type, dto.Breadcrumb = BlockTypeBreadCrumb, b
+	case BreadcrumbBlock:
+		dto.Type, dto.Breadcrumb = BlockTypeBreadCrumb, &b
+	case *ColumnListBlock:
+		dto.Type, dto.ColumnList = BlockTypeColumnList, b
+	case ColumnListBlock:
+		dto.Type, dto.ColumnList = BlockTypeColumnList, &b
+	case *ColumnBlock:
+		dto.Type, dto.Column = BlockTypeColumn, b
+	case ColumnBlock:
+		dto.Type, dto.Column = BlockTypeColumn, &b
+	case *TableBlock:
+		dto.Type, dto.Table = BlockTypeTable, b
+	case TableBlock:
+		dto.Type, dto.Table = BlockTypeTable, &b
+	case *TableRowBlock:
+		dto.Type, dto.TableRow = BlockTypeTableRow, b
+	case TableRowBlock:
+		dto.Type, dto.TableRow = BlockTypeTableRow, &b
+	case *LinkPreviewBlock:
+		dto.Type, dto.LinkPreview = BlockTypeLinkPreview, b
+	case LinkPreviewBlock:
+		dto.Type, dto.LinkPreview = BlockTypeLinkPreview, &b
+	case *LinkToPageBlock:
+		dto.Type, dto.LinkToPage = BlockTypeLinkToPage, b
+	case LinkToPageBlock:
+		dto.Type, dto.LinkToPage = BlockTypeLinkToPage, &b
+	case *SyncedBlock:
+		dto.Type, dto.SyncedBlock = BlockTypeSyncedBlock, b
+	case SyncedBlock:
+		dto.Type, dto.SyncedBlock = BlockTypeSyncedBlock, &b
+	case *TemplateBlock:
+		dto.Type, dto.Template = BlockTypeTemplate, b
+	case TemplateBlock:
+		dto.Type, dto.Template = BlockTypeTemplate, &b
+	default:
+		dto.Type = BlockTypeUnsupported
+	}
+
 	return dto
 }
+
+// Block returns the concrete block value, e.g. `*ParagraphBlock`, with its
+// metadata set. Unknown block types are returned as `*UnsupportedBlock`.
+func (dto BlockDTO) Block() Block {
+	base := BaseBlock{
+		BID:          dto.ID,
+		BHasChildren: dto.HasChildren,
+		BArchived:    dto.Archived,
+	}
+	if dto.Parent != nil {
+		base.BParent = *dto.Parent
+	}
+	if dto.CreatedTime != nil {
+		base.BCreatedTime = *dto.CreatedTime
+	}
+	if dto.CreatedBy != nil {
+		base.BCreatedBy = *dto.CreatedBy
+	}
+	if dto.LastEditedTime != nil {
+		base.BLastEditedTime = *dto.LastEditedTime
+	}
+	if dto.LastEditedBy != nil {
+		base.BLastEditedBy = *dto.LastEditedBy
+	}
+
+	switch dto.Type {
+	case BlockTypeParagraph:
+		block := &ParagraphBlock{}
+		if dto.Paragraph != nil {
+			*block = *dto.Paragraph
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeHeading1:
+		block := &Heading1Block{}
+		if dto.Heading1 != nil {
+			*block = *dto.Heading1
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeHeading2:
+		block := &Heading2Block{}
+		if dto.Heading2 != nil {
+			*block = *dto.Heading2
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeHeading3:
+		block := &Heading3Block{}
+		if dto.Heading3 != nil {
+			*block = *dto.Heading3
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeBulletedListItem:
+		block := &BulletedListItemBlock{}
+		if dto.BulletedListItem != nil {
+			*block = *dto.BulletedListItem
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeNumberedListItem:
+		block := &NumberedListItemBlock{}
+		if dto.NumberedListItem != nil {
+			*block = *dto.NumberedListItem
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeToDo:
+		block := &ToDoBlock{}
+		if dto.ToDo != nil {
+			*block = *dto.ToDo
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeToggle:
+		block := &ToggleBlock{}
+		if dto.Toggle != nil {
+			*block = *dto.Toggle
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeChildPage:
+		block := &ChildPageBlock{}
+		if dto.ChildPage != nil {
+			*block = *dto.ChildPage
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeChildDatabase:
+		block := &ChildDatabaseBlock{}
+		if dto.ChildDatabase != nil {
+			*block = *dto.ChildDatabase
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeCallout:
+		block := &CalloutBlock{}
+		if dto.Callout != nil {
+			*block = *dto.Callout
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeQuote:
+		block := &QuoteBlock{}
+		if dto.Quote != nil {
+			*block = *dto.Quote
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeCode:
+		block := &CodeBlock{}
+		if dto.Code != nil {
+			*block = *dto.Code
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeEmbed:
+		block := &EmbedBlock{}
+		if dto.Embed != nil {
+			*block = *dto.Embed
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeImage:
+		block := &ImageBlock{}
+		if dto.Image != nil {
+			*block = *dto.Image
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeAudio:
+		block := &AudioBlock{}
+		if dto.Audio != nil {
+			*block = *dto.Audio
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeVideo:
+		block := &VideoBlock{}
+		if dto.Video != nil {
+			*block = *dto.Video
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeFile:
+		block := &FileBlock{}
+		if dto.File != nil {
+			*block = *dto.File
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypePDF:
+		block := &PDFBlock{}
+		if dto.PDF != nil {
+			*block = *dto.PDF
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeBookmark:
+		block := &BookmarkBlock{}
+		if dto.Bookmark != nil {
+			*block = *dto.Bookmark
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeEquation:
+		block := &EquationBlock{}
+		if dto.Equation != nil {
+			*block = *dto.Equation
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeDivider:
+		block := &DividerBlock{}
+		if dto.Divider != nil {
+			*block = *dto.Divider
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeTableOfContents:
+		block := &TableOfContentsBlock{}
+		if dto.TableOfContents != nil {
+			*block = *dto.TableOfContents
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeBreadCrumb:
+		block := &BreadcrumbBlock{}
+		if dto.Breadcrumb != nil {
+			*block = *dto.Breadcrumb
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeColumnList:
+		block := &ColumnListBlock{}
+		if dto.ColumnList != nil {
+			*block = *dto.ColumnList
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeColumn:
+		block := &ColumnBlock{}
+		if dto.Column != nil {
+			*block = *dto.Column
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeTable:
+		block := &TableBlock{}
+		if dto.Table != nil {
+			*block = *dto.Table
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeTableRow:
+		block := &TableRowBlock{}
+		if dto.TableRow != nil {
+			*block = *dto.TableRow
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeLinkPreview:
+		block := &LinkPreviewBlock{}
+		if dto.LinkPreview != nil {
+			*block = *dto.LinkPreview
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeLinkToPage:
+		block := &LinkToPageBlock{}
+		if dto.LinkToPage != nil {
+			*block = *dto.LinkToPage
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeSyncedBlock:
+		block := &SyncedBlock{}
+		if dto.SyncedBlock != nil {
+			*block = *dto.SyncedBlock
+		}
+		block.BaseBlock = base
+		return block
+	case BlockTypeTemplate:
+		block := &TemplateBlock{}
+		if dto.Template != nil {
+			*block = *dto.Template
+		}
+		block.BaseBlock = base
+		return block
+	default:
+		return &UnsupportedBlock{BaseBlock: base}
+	}
+}
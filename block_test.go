@@ -0,0 +1,208 @@
+package notion_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestBlockDTOBlock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		json     string
+		expBlock notion.Block
+	}{
+		{
+			name: "to do block",
+			json: `{
+				"object": "block",
+				"id": "ae9c9a31-1c1e-4ae2-a5ee-c539a2d43113",
+				"type": "to_do",
+				"to_do": {
+					"rich_text": [{"type": "text", "text": {"content": "Buy milk"}, "plain_text": "Buy milk"}],
+					"checked": true
+				}
+			}`,
+			expBlock: &notion.ToDoBlock{
+				RichText: []notion.RichText{
+					{
+						Type:      notion.RichTextTypeText,
+						Text:      &notion.Text{Content: "Buy milk"},
+						PlainText: "Buy milk",
+					},
+				},
+				Checked: notion.BoolPtr(true),
+			},
+		},
+		{
+			name: "code block",
+			json: `{
+				"object": "block",
+				"id": "ae9c9a31-1c1e-4ae2-a5ee-c539a2d43113",
+				"type": "code",
+				"code": {
+					"rich_text": [{"type": "text", "text": {"content": "fmt.Println()"}}],
+					"language": "go"
+				}
+			}`,
+			expBlock: &notion.CodeBlock{
+				RichText: []notion.RichText{
+					{
+						Type: notion.RichTextTypeText,
+						Text: &notion.Text{Content: "fmt.Println()"},
+					},
+				},
+				Language: notion.StringPtr("go"),
+			},
+		},
+		{
+			name: "nested children without type field",
+			json: `{
+				"bulleted_list_item": {
+					"rich_text": [{"text": {"content": "Parent"}}],
+					"children": [
+						{
+							"bulleted_list_item": {
+								"rich_text": [{"text": {"content": "Child"}}]
+							}
+						}
+					]
+				}
+			}`,
+			expBlock: &notion.BulletedListItemBlock{
+				RichText: []notion.RichText{{Text: &notion.Text{Content: "Parent"}}},
+				Children: []notion.Block{
+					&notion.BulletedListItemBlock{
+						RichText: []notion.RichText{{Text: &notion.Text{Content: "Child"}}},
+					},
+				},
+			},
+		},
+		{
+			name: "column list",
+			json: `{
+				"column_list": {
+					"children": [
+						{"column": {"children": [{"divider": {}}]}}
+					]
+				}
+			}`,
+			expBlock: &notion.ColumnListBlock{
+				Children: []notion.ColumnBlock{
+					{Children: []notion.Block{&notion.DividerBlock{}}},
+				},
+			},
+		},
+		{
+			name: "unknown block type",
+			json: `{
+				"object": "block",
+				"id": "ae9c9a31-1c1e-4ae2-a5ee-c539a2d43113",
+				"type": "foobar",
+				"foobar": {}
+			}`,
+			expBlock: &notion.UnsupportedBlock{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var dto notion.BlockDTO
+			if err := json.Unmarshal([]byte(tt.json), &dto); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.expBlock, dto.Block(), cmpopts.IgnoreTypes(notion.BaseBlock{})); diff != "" {
+				t.Fatalf("block not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestBlockRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	respJSON := `{
+		"object": "block",
+		"id": "ae9c9a31-1c1e-4ae2-a5ee-c539a2d43113",
+		"parent": {"type": "page_id", "page_id": "59833787-2cf9-4fdf-8782-e53db20768a5"},
+		"created_time": "2021-05-14T09:15:00Z",
+		"last_edited_time": "2021-05-14T09:15:00Z",
+		"has_children": false,
+		"archived": false,
+		"type": "heading_2",
+		"heading_2": {
+			"rich_text": [{"type": "text", "text": {"content": "Foobar"}}],
+			"color": "red",
+			"is_toggleable": false
+		}
+	}`
+
+	var dto notion.BlockDTO
+	if err := json.Unmarshal([]byte(respJSON), &dto); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	block := dto.Block()
+	if block.ID() != "ae9c9a31-1c1e-4ae2-a5ee-c539a2d43113" {
+		t.Fatalf("id not equal (got: %v)", block.ID())
+	}
+
+	// Encoding the decoded block yields a request body for updating it.
+	reqJSON, err := json.Marshal(block)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expReqJSON := `{"heading_2":{"rich_text":[{"type":"text","text":{"content":"Foobar"}}],"color":"red","is_toggleable":false}}`
+	if diff := cmp.Diff(expReqJSON, string(reqJSON)); diff != "" {
+		t.Fatalf("encoded block not equal (-exp, +got):\n%v", diff)
+	}
+
+	// Encoding a DTO yields the same JSON as the API response.
+	dtoJSON, err := json.Marshal(notion.NewBlockDTO(block))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var exp, got map[string]interface{}
+	if err := json.Unmarshal([]byte(respJSON), &exp); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(dtoJSON, &got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Fatalf("encoded block DTO not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestBlockDTOBlockReturnsCopy(t *testing.T) {
+	t.Parallel()
+
+	block := &notion.ParagraphBlock{
+		RichText: []notion.RichText{{Text: &notion.Text{Content: "Foobar"}}},
+	}
+	dto := notion.NewBlockDTO(block)
+	dto.ID = "ae9c9a31-1c1e-4ae2-a5ee-c539a2d43113"
+
+	if got := dto.Block().ID(); got != dto.ID {
+		t.Fatalf("id not equal (expected: %v, got: %v)", dto.ID, got)
+	}
+	if _, err := json.Marshal(dto); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The block the DTO was created from must not be modified.
+	if got := block.ID(); got != "" {
+		t.Fatalf("expected block id to be empty, got: %v", got)
+	}
+}
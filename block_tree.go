@@ -0,0 +1,187 @@
+package notion
+
+import (
+	"context"
+	"sync"
+)
+
+// BlockTreeOptions are used for fetching a tree of blocks.
+type BlockTreeOptions struct {
+	// MaxDepth limits the depth of the fetched tree, where the children of the
+	// root block have depth 1. Zero means no limit.
+	MaxDepth int
+
+	// Concurrency is the maximum number of concurrent requests. Defaults to 1.
+	Concurrency int
+
+	// PageSize is used for every block children request. Zero means the API
+	// default is used.
+	PageSize int
+
+	// SkipChildPages and SkipChildDatabases prevent fetching the contents of
+	// `child_page` and `child_database` blocks, respectively.
+	SkipChildPages     bool
+	SkipChildDatabases bool
+}
+
+// FetchBlockTree returns the children of a block (or page), recursively. For
+// every block that has children, its Children field is filled in. Siblings
+// are fetched concurrently, following pagination cursors.
+func (c *Client) FetchBlockTree(ctx context.Context, blockID string, opts *BlockTreeOptions) ([]Block, error) {
+	f := blockTreeFetcher{client: c}
+	if opts != nil {
+		f.opts = *opts
+	}
+
+	concurrency := f.opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	f.sem = make(chan struct{}, concurrency)
+
+	return f.fetch(ctx, blockID, 1)
+}
+
+type blockTreeFetcher struct {
+	client *Client
+	opts   BlockTreeOptions
+	sem    chan struct{}
+}
+
+func (f *blockTreeFetcher) fetch(ctx context.Context, blockID string, depth int) ([]Block, error) {
+	children, err := f.fetchChildren(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.opts.MaxDepth > 0 && depth >= f.opts.MaxDepth {
+		return children, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for _, child := range children {
+		if !f.shouldDescend(child) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(child Block) {
+			defer wg.Done()
+
+			grandChildren, err := f.fetch(ctx, child.ID(), depth+1)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+
+			setBlockChildren(child, grandChildren)
+		}(child)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return children, nil
+}
+
+// fetchChildren fetches all children of a block, holding a semaphore slot
+// while requests are made.
+func (f *blockTreeFetcher) fetchChildren(ctx context.Context, blockID string) ([]Block, error) {
+	select {
+	case f.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-f.sem }()
+
+	var query *PaginationQuery
+	if f.opts.PageSize > 0 {
+		query = &PaginationQuery{PageSize: f.opts.PageSize}
+	}
+
+	children, err := f.client.FindBlockChildrenByIDAll(ctx, blockID, query)
+	if err != nil {
+		return nil, err
+	}
+	if children == nil {
+		children = []Block{}
+	}
+
+	return children, nil
+}
+
+func (f *blockTreeFetcher) shouldDescend(block Block) bool {
+	if !block.HasChildren() {
+		return false
+	}
+
+	switch block.(type) {
+	case *ChildPageBlock:
+		return !f.opts.SkipChildPages
+	case *ChildDatabaseBlock:
+		return !f.opts.SkipChildDatabases
+	}
+
+	return true
+}
+
+// setBlockChildren sets the Children field of block, if its type has one.
+func setBlockChildren(block Block, children []Block) {
+	switch b := block.(type) {
+	case *ParagraphBlock:
+		b.Children = children
+	case *Heading1Block:
+		b.Children = children
+	case *Heading2Block:
+		b.Children = children
+	case *Heading3Block:
+		b.Children = children
+	case *BulletedListItemBlock:
+		b.Children = children
+	case *NumberedListItemBlock:
+		b.Children = children
+	case *ToDoBlock:
+		b.Children = children
+	case *ToggleBlock:
+		b.Children = children
+	case *ChildPageBlock:
+		b.Children = children
+	case *ChildDatabaseBlock:
+		b.Children = children
+	case *CalloutBlock:
+		b.Children = children
+	case *QuoteBlock:
+		b.Children = children
+	case *CodeBlock:
+		b.Children = children
+	case *ColumnBlock:
+		b.Children = children
+	case *TableBlock:
+		b.Children = children
+	case *SyncedBlock:
+		b.Children = children
+	case *TemplateBlock:
+		b.Children = children
+	case *ColumnListBlock:
+		b.Children = make([]ColumnBlock, 0, len(children))
+		for _, child := range children {
+			if column, ok := child.(*ColumnBlock); ok {
+				b.Children = append(b.Children, *column)
+			}
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package notion_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// blockTreeTransport serves block children from a map of parent block ID to
+// child blocks (encoded as JSON objects).
+type blockTreeTransport struct {
+	t        *testing.T
+	children map[string][]string
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	requested   []string
+}
+
+func (tr *blockTreeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	blockID := parts[len(parts)-2]
+
+	tr.mu.Lock()
+	tr.inFlight++
+	if tr.inFlight > tr.maxInFlight {
+		tr.maxInFlight = tr.inFlight
+	}
+	tr.requested = append(tr.requested, blockID)
+	tr.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	tr.mu.Lock()
+	tr.inFlight--
+	tr.mu.Unlock()
+
+	children, ok := tr.children[blockID]
+	if !ok {
+		tr.t.Errorf("unexpected request for children of block %q", blockID)
+	}
+
+	// Serve each child on a separate page to exercise pagination.
+	cursor := r.URL.Query().Get("start_cursor")
+	idx := 0
+	if cursor != "" {
+		fmt.Sscanf(cursor, "%d", &idx)
+	}
+
+	results := "[]"
+	nextCursor := "null"
+	if idx < len(children) {
+		results = "[" + children[idx] + "]"
+	}
+	if idx+1 < len(children) {
+		nextCursor = fmt.Sprintf(`"%d"`, idx+1)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     http.StatusText(http.StatusOK),
+		Body: ioutil.NopCloser(strings.NewReader(fmt.Sprintf(
+			`{"object": "list", "results": %v, "next_cursor": %v, "has_more": %v}`,
+			results, nextCursor, nextCursor != "null",
+		))),
+	}, nil
+}
+
+func paragraphJSON(id, content string, hasChildren bool) string {
+	return fmt.Sprintf(`{
+		"object": "block",
+		"id": %q,
+		"has_children": %v,
+		"type": "paragraph",
+		"paragraph": {"rich_text": [{"type": "text", "text": {"content": %q}, "plain_text": %q}]}
+	}`, id, hasChildren, content, content)
+}
+
+func newBlockTreeTransport(t *testing.T) *blockTreeTransport {
+	return &blockTreeTransport{
+		t: t,
+		children: map[string][]string{
+			"root": {
+				paragraphJSON("a", "A", true),
+				paragraphJSON("b", "B", true),
+				`{"object": "block", "id": "page", "has_children": true, "type": "child_page", "child_page": {"title": "Sub page"}}`,
+				paragraphJSON("c", "C", false),
+			},
+			"a":    {paragraphJSON("a1", "A1", true), paragraphJSON("a2", "A2", false)},
+			"a1":   {paragraphJSON("a1x", "A1x", false)},
+			"b":    {paragraphJSON("b1", "B1", false)},
+			"page": {paragraphJSON("p1", "P1", false)},
+		},
+	}
+}
+
+func TestFetchBlockTree(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fetches all descendants", func(t *testing.T) {
+		t.Parallel()
+
+		tr := newBlockTreeTransport(t)
+		client := notion.NewClient("secret-api-key", notion.WithHTTPClient(&http.Client{Transport: tr}))
+
+		blocks, err := client.FetchBlockTree(context.Background(), "root", &notion.BlockTreeOptions{Concurrency: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(blocks) != 4 {
+			t.Fatalf("expected 4 blocks, got %v", len(blocks))
+		}
+
+		a := blocks[0].(*notion.ParagraphBlock)
+		if len(a.Children) != 2 {
+			t.Fatalf("expected 2 children of block a, got %v", len(a.Children))
+		}
+		a1 := a.Children[0].(*notion.ParagraphBlock)
+		if len(a1.Children) != 1 || a1.Children[0].ID() != "a1x" {
+			t.Fatalf("unexpected children of block a1: %+v", a1.Children)
+		}
+
+		page := blocks[2].(*notion.ChildPageBlock)
+		if len(page.Children) != 1 || page.Children[0].ID() != "p1" {
+			t.Fatalf("unexpected children of child page: %+v", page.Children)
+		}
+
+		if tr.maxInFlight > 2 {
+			t.Fatalf("expected at most 2 concurrent requests, got %v", tr.maxInFlight)
+		}
+	})
+
+	t.Run("respects max depth and skips child pages", func(t *testing.T) {
+		t.Parallel()
+
+		tr := newBlockTreeTransport(t)
+		client := notion.NewClient("secret-api-key", notion.WithHTTPClient(&http.Client{Transport: tr}))
+
+		blocks, err := client.FetchBlockTree(context.Background(), "root", &notion.BlockTreeOptions{
+			MaxDepth:       2,
+			Concurrency:    4,
+			SkipChildPages: true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		a1 := blocks[0].(*notion.ParagraphBlock).Children[0].(*notion.ParagraphBlock)
+		if a1.Children != nil {
+			t.Fatalf("expected children of block a1 to not be fetched, got %+v", a1.Children)
+		}
+
+		page := blocks[2].(*notion.ChildPageBlock)
+		if page.Children != nil {
+			t.Fatalf("expected children of child page to not be fetched, got %+v", page.Children)
+		}
+
+		for _, id := range tr.requested {
+			if id == "a1" || id == "page" {
+				t.Fatalf("unexpected request for children of block %q", id)
+			}
+		}
+	})
+}
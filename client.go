@@ -10,18 +10,27 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 const (
-	baseURL       = "https://api.notion.com/v1"
-	apiVersion    = "2022-06-28"
+	// DefaultBaseURL is the base URL of the Notion API.
+	DefaultBaseURL = "https://api.notion.com/v1"
+	// DefaultAPIVersion is the `Notion-Version` sent with requests by default.
+	DefaultAPIVersion = "2022-06-28"
+
 	clientVersion = "0.0.0"
 )
 
 // Client is used for HTTP requests to the Notion API.
 type Client struct {
-	apiKey     string
-	httpClient *http.Client
+	tokenSource TokenSource
+	baseURL     string
+	apiVersion  string
+	httpClient  *http.Client
+	retryPolicy *RetryPolicy
+	rateLimiter *RateLimiter
+	middleware  []Middleware
 }
 
 // ClientOption is used to override default client behavior.
@@ -30,8 +39,10 @@ type ClientOption func(*Client)
 // NewClient returns a new Client.
 func NewClient(apiKey string, opts ...ClientOption) *Client {
 	c := &Client{
-		apiKey:     apiKey,
-		httpClient: http.DefaultClient,
+		tokenSource: StaticTokenSource(apiKey),
+		baseURL:     DefaultBaseURL,
+		apiVersion:  DefaultAPIVersion,
+		httpClient:  http.DefaultClient,
 	}
 
 	for _, opt := range opts {
@@ -48,14 +59,46 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithBaseURL overrides the default base URL of the Notion API, e.g. to use a
+// local stand-in server or a proxy. The URL should include the version path
+// prefix (`/v1`), if any.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithAPIVersion overrides the default `Notion-Version` header value.
+// See: https://developers.notion.com/reference/versioning
+func WithAPIVersion(version string) ClientOption {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// BaseURL returns the base URL used for requests to the Notion API.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// APIVersion returns the `Notion-Version` header value sent with requests.
+func (c *Client) APIVersion() string {
+	return c.apiVersion
+}
+
 func (c *Client) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequestWithContext(ctx, method, baseURL+url, body)
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", c.apiKey))
-	req.Header.Set("Notion-Version", apiVersion)
+	token, err := c.tokenSource.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	req.Header.Set("Notion-Version", c.apiVersion)
 	req.Header.Set("User-Agent", "go-notion/"+clientVersion)
 
 	if body != nil {
@@ -73,7 +116,7 @@ func (c *Client) FindDatabaseByID(ctx context.Context, id string) (db Database,
 		return Database{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "FindDatabaseByID", id)
 	if err != nil {
 		return Database{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -108,7 +151,7 @@ func (c *Client) QueryDatabase(ctx context.Context, id string, query *DatabaseQu
 		return DatabaseQueryResponse{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "QueryDatabase", id)
 	if err != nil {
 		return DatabaseQueryResponse{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -145,7 +188,7 @@ func (c *Client) CreateDatabase(ctx context.Context, params CreateDatabaseParams
 		return Database{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "CreateDatabase", "")
 	if err != nil {
 		return Database{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -182,7 +225,7 @@ func (c *Client) UpdateDatabase(ctx context.Context, databaseID string, params U
 		return Database{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "UpdateDatabase", databaseID)
 	if err != nil {
 		return Database{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -208,7 +251,7 @@ func (c *Client) FindPageByID(ctx context.Context, id string) (page Page, err er
 		return Page{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "FindPageByID", id)
 	if err != nil {
 		return Page{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -245,7 +288,7 @@ func (c *Client) CreatePage(ctx context.Context, params CreatePageParams) (page
 		return Page{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "CreatePage", "")
 	if err != nil {
 		return Page{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -282,7 +325,7 @@ func (c *Client) UpdatePage(ctx context.Context, pageID string, params UpdatePag
 		return Page{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "UpdatePage", pageID)
 	if err != nil {
 		return Page{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -319,7 +362,7 @@ func (c *Client) FindBlockChildrenByID(ctx context.Context, blockID string, quer
 		req.URL.RawQuery = q.Encode()
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "FindBlockChildrenByID", blockID)
 	if err != nil {
 		return BlockChildrenResponse{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -356,7 +399,7 @@ func (c *Client) FindPagePropertyByID(ctx context.Context, pageID, propID string
 		req.URL.RawQuery = q.Encode()
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "FindPagePropertyByID", pageID)
 	if err != nil {
 		return PagePropResponse{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -394,7 +437,7 @@ func (c *Client) AppendBlockChildren(ctx context.Context, blockID string, childr
 		return BlockChildrenResponse{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "AppendBlockChildren", blockID)
 	if err != nil {
 		return BlockChildrenResponse{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -420,7 +463,7 @@ func (c *Client) FindBlockByID(ctx context.Context, blockID string) (Block, erro
 		return nil, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "FindBlockByID", blockID)
 	if err != nil {
 		return nil, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -455,7 +498,7 @@ func (c *Client) UpdateBlock(ctx context.Context, blockID string, block Block) (
 		return nil, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "UpdateBlock", blockID)
 	if err != nil {
 		return nil, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -483,7 +526,7 @@ func (c *Client) DeleteBlock(ctx context.Context, blockID string) (Block, error)
 		return nil, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "DeleteBlock", blockID)
 	if err != nil {
 		return nil, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -511,7 +554,7 @@ func (c *Client) FindUserByID(ctx context.Context, id string) (user User, err er
 		return User{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "FindUserByID", id)
 	if err != nil {
 		return User{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -537,7 +580,7 @@ func (c *Client) FindCurrentUser(ctx context.Context) (user User, err error) {
 		return User{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "FindCurrentUser", "")
 	if err != nil {
 		return User{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -574,7 +617,7 @@ func (c *Client) ListUsers(ctx context.Context, query *PaginationQuery) (result
 		req.URL.RawQuery = q.Encode()
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "ListUsers", "")
 	if err != nil {
 		return ListUsersResponse{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -610,7 +653,7 @@ func (c *Client) Search(ctx context.Context, opts *SearchOpts) (result SearchRes
 		return SearchResponse{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "Search", "")
 	if err != nil {
 		return SearchResponse{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -647,7 +690,7 @@ func (c *Client) CreateComment(ctx context.Context, params CreateCommentParams)
 		return Comment{}, fmt.Errorf("notion: invalid request: %w", err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "CreateComment", "")
 	if err != nil {
 		return Comment{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -691,7 +734,7 @@ func (c *Client) FindCommentsByBlockID(
 	}
 	req.URL.RawQuery = q.Encode()
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req, "FindCommentsByBlockID", query.BlockID)
 	if err != nil {
 		return FindCommentsResponse{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
 	}
@@ -78,6 +78,57 @@ func TestNewClient(t *testing.T) {
 			t.Errorf("option func called with incorrect *Client value (expected: %+v, got: %+v)", exp, got)
 		}
 	})
+
+	t.Run("uses default base URL and API version", func(t *testing.T) {
+		t.Parallel()
+
+		client := notion.NewClient("secret-api-key")
+
+		if exp, got := notion.DefaultBaseURL, client.BaseURL(); exp != got {
+			t.Errorf("base URL not equal (expected: %v, got: %v)", exp, got)
+		}
+		if exp, got := notion.DefaultAPIVersion, client.APIVersion(); exp != got {
+			t.Errorf("API version not equal (expected: %v, got: %v)", exp, got)
+		}
+	})
+
+	t.Run("uses base URL and API version options", func(t *testing.T) {
+		t.Parallel()
+
+		var reqURL, reqVersion string
+
+		httpClient := &http.Client{
+			Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+				reqURL = r.URL.String()
+				reqVersion = r.Header.Get("Notion-Version")
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     http.StatusText(http.StatusOK),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"object": "user", "id": "foo"}`)),
+				}, nil
+			}},
+		}
+		client := notion.NewClient("secret-api-key",
+			notion.WithHTTPClient(httpClient),
+			notion.WithBaseURL("http://127.0.0.1:8080/notion/v1/"),
+			notion.WithAPIVersion("2099-01-01"),
+		)
+
+		if _, err := client.FindCurrentUser(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if exp := "http://127.0.0.1:8080/notion/v1/users/me"; exp != reqURL {
+			t.Errorf("request URL not equal (expected: %v, got: %v)", exp, reqURL)
+		}
+		if exp := "2099-01-01"; exp != reqVersion {
+			t.Errorf("Notion-Version header not equal (expected: %v, got: %v)", exp, reqVersion)
+		}
+		if exp, got := "2099-01-01", client.APIVersion(); exp != got {
+			t.Errorf("API version not equal (expected: %v, got: %v)", exp, got)
+		}
+	})
 }
 
 func TestBlockJson(t *testing.T) {
@@ -182,19 +233,15 @@ func TestBlockJson(t *testing.T) {
 		t.Fatal("Unmarshal failed: err: ", err.Error())
 	}
 	for _, b := range blocks.Results {
-		block, ok := b.(notion.BlockDTO)
+		block, ok := b.(*notion.ImageBlock)
 		if !ok {
-			t.Fatal("type error")
-			return
+			t.Fatalf("type error (expected: *notion.ImageBlock, got: %T)", b)
 		}
-		if block.Image != nil {
-			str, err := json.Marshal(block)
-			if err != nil {
-				t.Fatal("marshal error: ", err.Error())
-				return
-			}
-			fmt.Println(string(str))
+		str, err := json.Marshal(block)
+		if err != nil {
+			t.Fatal("marshal error: ", err.Error())
 		}
+		fmt.Println(string(str))
 	}
 }
 
@@ -1927,13 +1974,11 @@ func TestCreatePage(t *testing.T) {
 					},
 				},
 				Children: []notion.Block{
-					notion.BlockDTO{
-						Paragraph: &notion.ParagraphBlock{
-							RichText: []notion.RichText{
-								{
-									Text: &notion.Text{
-										Content: "Lorem ipsum dolor sit amet.",
-									},
+					notion.ParagraphBlock{
+						RichText: []notion.RichText{
+							{
+								Text: &notion.Text{
+									Content: "Lorem ipsum dolor sit amet.",
 								},
 							},
 						},
@@ -2102,13 +2147,11 @@ func TestCreatePage(t *testing.T) {
 					},
 				},
 				Children: []notion.Block{
-					notion.BlockDTO{
-						Paragraph: &notion.ParagraphBlock{
-							RichText: []notion.RichText{
-								{
-									Text: &notion.Text{
-										Content: "Lorem ipsum dolor sit amet.",
-									},
+					notion.ParagraphBlock{
+						RichText: []notion.RichText{
+							{
+								Text: &notion.Text{
+									Content: "Lorem ipsum dolor sit amet.",
 								},
 							},
 						},
@@ -3211,19 +3254,17 @@ func TestFindBlockChildrenById(t *testing.T) {
 			},
 			expResponse: notion.BlockChildrenResponse{
 				Results: []notion.Block{
-					notion.BlockDTO{
-						Paragraph: &notion.ParagraphBlock{
-							RichText: []notion.RichText{
-								{
-									Type: notion.RichTextTypeText,
-									Text: &notion.Text{
-										Content: "Lorem ipsum dolor sit amet.",
-									},
-									Annotations: &notion.Annotations{
-										Color: notion.ColorDefault,
-									},
-									PlainText: "Lorem ipsum dolor sit amet.",
+					&notion.ParagraphBlock{
+						RichText: []notion.RichText{
+							{
+								Type: notion.RichTextTypeText,
+								Text: &notion.Text{
+									Content: "Lorem ipsum dolor sit amet.",
+								},
+								Annotations: &notion.Annotations{
+									Color: notion.ColorDefault,
 								},
+								PlainText: "Lorem ipsum dolor sit amet.",
 							},
 						},
 					},
@@ -3325,7 +3366,7 @@ func TestFindBlockChildrenById(t *testing.T) {
 				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
 			}
 
-			if diff := cmp.Diff(tt.expResponse, resp, cmpopts.IgnoreUnexported(notion.ParagraphBlock{})); diff != "" {
+			if diff := cmp.Diff(tt.expResponse, resp, cmpopts.IgnoreTypes(notion.BaseBlock{})); diff != "" {
 				t.Fatalf("response not equal (-exp, +got):\n%v", diff)
 			}
 
@@ -3382,13 +3423,11 @@ func TestAppendBlockChildren(t *testing.T) {
 		{
 			name: "successful response",
 			children: []notion.Block{
-				notion.BlockDTO{
-					Paragraph: &notion.ParagraphBlock{
-						RichText: []notion.RichText{
-							{
-								Text: &notion.Text{
-									Content: "Lorem ipsum dolor sit amet.",
-								},
+				notion.ParagraphBlock{
+					RichText: []notion.RichText{
+						{
+							Text: &notion.Text{
+								Content: "Lorem ipsum dolor sit amet.",
 							},
 						},
 					},
@@ -3452,21 +3491,20 @@ func TestAppendBlockChildren(t *testing.T) {
 			},
 			expResponse: notion.BlockChildrenResponse{
 				Results: []notion.Block{
-					notion.BlockDTO{
-						Paragraph: &notion.ParagraphBlock{
-							RichText: []notion.RichText{
-								{
-									Type: notion.RichTextTypeText,
-									Text: &notion.Text{
-										Content: "Lorem ipsum dolor sit amet.",
-									},
-									Annotations: &notion.Annotations{
-										Color: notion.ColorDefault,
-									},
-									PlainText: "Lorem ipsum dolor sit amet.",
+					&notion.ParagraphBlock{
+						RichText: []notion.RichText{
+							{
+								Type: notion.RichTextTypeText,
+								Text: &notion.Text{
+									Content: "Lorem ipsum dolor sit amet.",
+								},
+								Annotations: &notion.Annotations{
+									Color: notion.ColorDefault,
 								},
+								PlainText: "Lorem ipsum dolor sit amet.",
 							},
-						}},
+						},
+					},
 				},
 				HasMore:    true,
 				NextCursor: notion.StringPtr("A^hd"),
@@ -3485,13 +3523,11 @@ func TestAppendBlockChildren(t *testing.T) {
 		{
 			name: "error response",
 			children: []notion.Block{
-				notion.BlockDTO{
-					Paragraph: &notion.ParagraphBlock{
-						RichText: []notion.RichText{
-							{
-								Text: &notion.Text{
-									Content: "Lorem ipsum dolor sit amet.",
-								},
+				notion.ParagraphBlock{
+					RichText: []notion.RichText{
+						{
+							Text: &notion.Text{
+								Content: "Lorem ipsum dolor sit amet.",
 							},
 						},
 					},
@@ -3576,7 +3612,7 @@ func TestAppendBlockChildren(t *testing.T) {
 				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
 			}
 
-			if diff := cmp.Diff(tt.expResponse, resp, cmpopts.IgnoreUnexported(notion.ParagraphBlock{})); diff != "" {
+			if diff := cmp.Diff(tt.expResponse, resp, cmpopts.IgnoreTypes(notion.BaseBlock{})); diff != "" {
 				t.Fatalf("response not equal (-exp, +got):\n%v", diff)
 			}
 
@@ -4330,10 +4366,9 @@ func TestFindBlockByID(t *testing.T) {
 				)
 			},
 			respStatusCode: http.StatusOK,
-			expBlock: &notion.BlockDTO{
-				ChildPage: &notion.ChildPageBlock{
-					Title: "test title",
-				}},
+			expBlock: &notion.ChildPageBlock{
+				Title: "test title",
+			},
 
 			expID: "048e165e-352d-4119-8128-e46c3527d95c",
 			expParent: notion.Parent{
@@ -4342,11 +4377,13 @@ func TestFindBlockByID(t *testing.T) {
 			},
 			expCreatedTime: mustParseTime(time.RFC3339, "2021-10-02T06:09:00Z"),
 			expCreatedBy: notion.BlockUser{
-				ID: "71e95936-2737-4e11-b03d-f174f6f13087",
+				Object: "user",
+				ID:     "71e95936-2737-4e11-b03d-f174f6f13087",
 			},
 			expLastEditedTime: mustParseTime(time.RFC3339, "2021-10-02T06:31:00Z"),
 			expLastEditedBy: notion.BlockUser{
-				ID: "5ba97cc9-e5e0-4363-b33a-1d80a635577f",
+				Object: "user",
+				ID:     "5ba97cc9-e5e0-4363-b33a-1d80a635577f",
 			},
 			expHasChildren: true,
 			expArchived:    false,
@@ -4397,7 +4434,7 @@ func TestFindBlockByID(t *testing.T) {
 				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
 			}
 
-			if diff := cmp.Diff(tt.expBlock, block, cmpopts.IgnoreUnexported(notion.ChildPageBlock{})); diff != "" {
+			if diff := cmp.Diff(tt.expBlock, block, cmpopts.IgnoreTypes(notion.BaseBlock{})); diff != "" {
 				t.Fatalf("user not equal (-exp, +got):\n%v", diff)
 			}
 
@@ -4457,13 +4494,11 @@ func TestUpdateBlock(t *testing.T) {
 	}{
 		{
 			name: "successful response",
-			block: notion.BlockDTO{
-				Paragraph: &notion.ParagraphBlock{
-					RichText: []notion.RichText{
-						{
-							Text: &notion.Text{
-								Content: "Foobar",
-							},
+			block: notion.ParagraphBlock{
+				RichText: []notion.RichText{
+					{
+						Text: &notion.Text{
+							Content: "Foobar",
 						},
 					},
 				},
@@ -4514,18 +4549,16 @@ func TestUpdateBlock(t *testing.T) {
 					},
 				},
 			},
-			expResponse: &notion.BlockDTO{
-				Paragraph: &notion.ParagraphBlock{
-					RichText: []notion.RichText{
-						{
-							Type: notion.RichTextTypeText,
-							Text: &notion.Text{
-								Content: "Foobar",
-							},
-							PlainText: "Foobar",
-							Annotations: &notion.Annotations{
-								Color: notion.ColorDefault,
-							},
+			expResponse: &notion.ParagraphBlock{
+				RichText: []notion.RichText{
+					{
+						Type: notion.RichTextTypeText,
+						Text: &notion.Text{
+							Content: "Foobar",
+						},
+						PlainText: "Foobar",
+						Annotations: &notion.Annotations{
+							Color: notion.ColorDefault,
 						},
 					},
 				},
@@ -4539,13 +4572,11 @@ func TestUpdateBlock(t *testing.T) {
 		},
 		{
 			name: "error response",
-			block: &notion.BlockDTO{
-				Paragraph: &notion.ParagraphBlock{
-					RichText: []notion.RichText{
-						{
-							Text: &notion.Text{
-								Content: "Foobar",
-							},
+			block: &notion.ParagraphBlock{
+				RichText: []notion.RichText{
+					{
+						Text: &notion.Text{
+							Content: "Foobar",
 						},
 					},
 				},
@@ -4626,7 +4657,7 @@ func TestUpdateBlock(t *testing.T) {
 				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
 			}
 
-			if diff := cmp.Diff(tt.expResponse, updatedBlock, cmpopts.IgnoreUnexported(notion.ParagraphBlock{})); diff != "" {
+			if diff := cmp.Diff(tt.expResponse, updatedBlock, cmpopts.IgnoreTypes(notion.BaseBlock{})); diff != "" {
 				t.Fatalf("response not equal (-exp, +got):\n%v", diff)
 			}
 
@@ -4707,18 +4738,16 @@ func TestDeleteBlock(t *testing.T) {
 				)
 			},
 			respStatusCode: http.StatusOK,
-			expResponse: &notion.BlockDTO{
-				Paragraph: &notion.ParagraphBlock{
-					RichText: []notion.RichText{
-						{
-							Type: notion.RichTextTypeText,
-							Text: &notion.Text{
-								Content: "Foobar",
-							},
-							PlainText: "Foobar",
-							Annotations: &notion.Annotations{
-								Color: notion.ColorDefault,
-							},
+			expResponse: &notion.ParagraphBlock{
+				RichText: []notion.RichText{
+					{
+						Type: notion.RichTextTypeText,
+						Text: &notion.Text{
+							Content: "Foobar",
+						},
+						PlainText: "Foobar",
+						Annotations: &notion.Annotations{
+							Color: notion.ColorDefault,
 						},
 					},
 				},
@@ -4775,7 +4804,7 @@ func TestDeleteBlock(t *testing.T) {
 				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
 			}
 
-			if diff := cmp.Diff(tt.expResponse, deletedBlock, cmpopts.IgnoreUnexported(notion.ParagraphBlock{})); diff != "" {
+			if diff := cmp.Diff(tt.expResponse, deletedBlock, cmpopts.IgnoreTypes(notion.BaseBlock{})); diff != "" {
 				t.Fatalf("response not equal (-exp, +got):\n%v", diff)
 			}
 
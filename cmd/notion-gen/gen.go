@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// config configures code generation for a database.
+type config struct {
+	pkg      string
+	typeName string
+}
+
+// field is a struct field generated for a database property.
+type field struct {
+	name    string
+	goType  string
+	prop    string
+	propTyp notion.DatabasePropertyType
+	options []notion.SelectOptions
+
+	// filterMethod is the method of filter.Property used for conditions on
+	// the property, and filterType the type of condition it returns.
+	filterMethod string
+	filterType   string
+}
+
+// propertyTypes maps property types to Go field types, and filter methods and
+// condition types. Field types are supported by notion.UnmarshalProperties
+// and notion.MarshalProperties.
+var propertyTypes = map[notion.DatabasePropertyType]struct {
+	goType       string
+	filterMethod string
+	filterType   string
+}{
+	notion.DBPropTypeTitle:          {"string", "Title", "TextCondition"},
+	notion.DBPropTypeRichText:       {"string", "RichText", "TextCondition"},
+	notion.DBPropTypeURL:            {"string", "URL", "TextCondition"},
+	notion.DBPropTypeEmail:          {"string", "Email", "TextCondition"},
+	notion.DBPropTypePhoneNumber:    {"string", "PhoneNumber", "TextCondition"},
+	notion.DBPropTypeNumber:         {"*float64", "Number", "NumberCondition"},
+	notion.DBPropTypeCheckbox:       {"bool", "Checkbox", "CheckboxCondition"},
+	notion.DBPropTypeSelect:         {"string", "Select", "SelectCondition"},
+	notion.DBPropTypeStatus:         {"string", "Status", "SelectCondition"},
+	notion.DBPropTypeMultiSelect:    {"[]string", "MultiSelect", "ContainsCondition"},
+	notion.DBPropTypeDate:           {"*notion.Date", "Date", "DateCondition"},
+	notion.DBPropTypePeople:         {"[]string", "People", "ContainsCondition"},
+	notion.DBPropTypeRelation:       {"[]string", "Relation", "ContainsCondition"},
+	notion.DBPropTypeFiles:          {"[]notion.File", "Files", "FilesCondition"},
+	notion.DBPropTypeFormula:        {"notion.DatabasePageProperty", "Formula", "FormulaCondition"},
+	notion.DBPropTypeRollup:         {"notion.DatabasePageProperty", "Rollup", "RollupCondition"},
+	notion.DBPropTypeCreatedTime:    {"time.Time", "CreatedTime", "DateCondition"},
+	notion.DBPropTypeLastEditedTime: {"time.Time", "LastEditedTime", "DateCondition"},
+	notion.DBPropTypeCreatedBy:      {"notion.DatabasePageProperty", "CreatedBy", "ContainsCondition"},
+	notion.DBPropTypeLastEditedBy:   {"notion.DatabasePageProperty", "LastEditedBy", "ContainsCondition"},
+}
+
+// generate returns the Go source code for a database.
+func generate(db notion.Database, cfg config) ([]byte, error) {
+	typeName := cfg.typeName
+	if typeName == "" {
+		typeName = exportedIdentifier(notion.PlainText(db.Title), "Page")
+	}
+	if !isExported(typeName) {
+		return nil, fmt.Errorf("invalid type name %q", typeName)
+	}
+
+	fields, err := databaseFields(db.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by notion-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %v\n\n", cfg.pkg)
+
+	imports := []string{`"github.com/cryptowizard0/go-notion/filter"`}
+	for _, f := range fields {
+		if strings.Contains(f.goType, "notion.") {
+			imports = append(imports, `"github.com/cryptowizard0/go-notion"`)
+			break
+		}
+	}
+	for _, f := range fields {
+		if f.goType == "time.Time" {
+			imports = append(imports, `"time"`)
+			break
+		}
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		// Standard library packages go first.
+		iStd, jStd := !strings.Contains(imports[i], "."), !strings.Contains(imports[j], ".")
+		if iStd != jStd {
+			return iStd
+		}
+		return imports[i] < imports[j]
+	})
+	fmt.Fprintf(&buf, "import (\n")
+	for i, imp := range imports {
+		if i > 0 && !strings.Contains(imports[i-1], ".") && strings.Contains(imp, ".") {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "%v\n", imp)
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintf(&buf, "// %vDatabaseID is the ID of the %q database.\n", typeName, notion.PlainText(db.Title))
+	fmt.Fprintf(&buf, "const %vDatabaseID = %q\n\n", typeName, db.ID)
+
+	fmt.Fprintf(&buf, "// %v is a page of the %q database. Use notion.UnmarshalProperties and\n", typeName, notion.PlainText(db.Title))
+	fmt.Fprintf(&buf, "// notion.MarshalProperties to convert it from and to page properties.\n")
+	fmt.Fprintf(&buf, "type %v struct {\n", typeName)
+	for _, f := range fields {
+		fmt.Fprintf(&buf, "%v %v `notion:%q`\n", f.name, f.goType, f.prop+","+string(f.propTyp))
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	for _, f := range fields {
+		if len(f.options) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\n// Options of the %q %v property.\n", f.prop, strings.ReplaceAll(string(f.propTyp), "_", "-"))
+		fmt.Fprintf(&buf, "const (\n")
+		names := make(map[string]bool)
+		for i, opt := range f.options {
+			ident := identifier(opt.Name)
+			if ident == "" {
+				ident = "Option" + strconv.Itoa(i+1)
+			}
+			name := uniqueName(typeName+f.name+ident, names)
+			fmt.Fprintf(&buf, "%v = %q\n", name, opt.Name)
+		}
+		fmt.Fprintf(&buf, ")\n")
+	}
+
+	for _, f := range fields {
+		fmt.Fprintf(&buf, "\n// %v%vFilter returns a filter condition on the %q property.\n", typeName, f.name, f.prop)
+		fmt.Fprintf(&buf, "func %v%vFilter() filter.%v {\n", typeName, f.name, f.filterType)
+		fmt.Fprintf(&buf, "return filter.Prop(%q).%v()\n", f.prop, f.filterMethod)
+		fmt.Fprintf(&buf, "}\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// databaseFields returns the struct fields for database properties. The title
+// property goes first, followed by the other properties sorted by name.
+func databaseFields(props notion.DatabaseProperties) ([]field, error) {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		iTitle, jTitle := props[names[i]].Type == notion.DBPropTypeTitle, props[names[j]].Type == notion.DBPropTypeTitle
+		if iTitle != jTitle {
+			return iTitle
+		}
+		return names[i] < names[j]
+	})
+
+	fieldNames := make(map[string]bool)
+	fields := make([]field, 0, len(names))
+
+	for _, name := range names {
+		prop := props[name]
+
+		mapping, ok := propertyTypes[prop.Type]
+		if !ok {
+			return nil, fmt.Errorf("property %q has unsupported type %q", name, prop.Type)
+		}
+
+		f := field{
+			name:         uniqueName(exportedIdentifier(name, "Prop"), fieldNames),
+			goType:       mapping.goType,
+			prop:         name,
+			propTyp:      prop.Type,
+			filterMethod: mapping.filterMethod,
+			filterType:   mapping.filterType,
+		}
+		switch {
+		case prop.Select != nil:
+			f.options = prop.Select.Options
+		case prop.MultiSelect != nil:
+			f.options = prop.MultiSelect.Options
+		case prop.Status != nil:
+			f.options = prop.Status.Options
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// identifier returns the words of s as an upper camel case identifier, e.g.
+// "DueDate" for "Due date". Other characters are dropped, so it may start with
+// a digit, or be empty.
+func identifier(s string) string {
+	var sb strings.Builder
+
+	upper := true
+	for _, r := range s {
+		// Keep identifiers ASCII, so they're easy to type.
+		if r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// exportedIdentifier returns identifier(s), prefixed with "X" if it starts
+// with a digit, or fallback if it's empty.
+func exportedIdentifier(s, fallback string) string {
+	ident := identifier(s)
+	if ident == "" {
+		return fallback
+	}
+	if unicode.IsDigit(rune(ident[0])) {
+		return "X" + ident
+	}
+	return ident
+}
+
+// uniqueName returns name, or name with a numeric suffix if it's already in
+// names, and adds the result to names.
+func uniqueName(name string, names map[string]bool) string {
+	unique := name
+	for i := 2; names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	names[unique] = true
+
+	return unique
+}
+
+func isExported(name string) bool {
+	for i, r := range name {
+		if i == 0 && !unicode.IsUpper(r) {
+			return false
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/google/go-cmp/cmp"
+)
+
+const tasksSchema = `{
+	"object": "database",
+	"id": "668d797c-76fa-4934-9b05-ad288df2d136",
+	"title": [{"type": "text", "text": {"content": "Tasks"}, "plain_text": "Tasks"}],
+	"properties": {
+		"Name": {"id": "title", "type": "title", "title": {}},
+		"Due date": {"id": "a", "type": "date", "date": {}},
+		"Estimate": {"id": "b", "type": "number", "number": {"format": "number"}},
+		"Status": {
+			"id": "c",
+			"type": "status",
+			"status": {
+				"options": [
+					{"id": "1", "name": "Not started", "color": "default"},
+					{"id": "2", "name": "In progress", "color": "blue"},
+					{"id": "3", "name": "Done", "color": "green"}
+				],
+				"groups": []
+			}
+		},
+		"Tags": {
+			"id": "d",
+			"type": "multi_select",
+			"multi_select": {
+				"options": [
+					{"id": "4", "name": "2024", "color": "red"},
+					{"id": "5", "name": "🔥", "color": "red"}
+				]
+			}
+		},
+		"Created": {"id": "e", "type": "created_time", "created_time": {}},
+		"Attachments": {"id": "g", "type": "files", "files": {}},
+		"Score": {"id": "f", "type": "formula", "formula": {"expression": "1"}}
+	}
+}`
+
+const tasksSource = "// Code generated by notion-gen. DO NOT EDIT.\n" + `
+package tasks
+
+import (
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/filter"
+)
+
+// TaskDatabaseID is the ID of the "Tasks" database.
+const TaskDatabaseID = "668d797c-76fa-4934-9b05-ad288df2d136"
+
+// Task is a page of the "Tasks" database. Use notion.UnmarshalProperties and
+// notion.MarshalProperties to convert it from and to page properties.
+type Task struct {
+	Name        string                      ` + "`" + `notion:"Name,title"` + "`" + `
+	Attachments []notion.File               ` + "`" + `notion:"Attachments,files"` + "`" + `
+	Created     time.Time                   ` + "`" + `notion:"Created,created_time"` + "`" + `
+	DueDate     *notion.Date                ` + "`" + `notion:"Due date,date"` + "`" + `
+	Estimate    *float64                    ` + "`" + `notion:"Estimate,number"` + "`" + `
+	Score       notion.DatabasePageProperty ` + "`" + `notion:"Score,formula"` + "`" + `
+	Status      string                      ` + "`" + `notion:"Status,status"` + "`" + `
+	Tags        []string                    ` + "`" + `notion:"Tags,multi_select"` + "`" + `
+}
+
+// Options of the "Status" status property.
+const (
+	TaskStatusNotStarted = "Not started"
+	TaskStatusInProgress = "In progress"
+	TaskStatusDone       = "Done"
+)
+
+// Options of the "Tags" multi-select property.
+const (
+	TaskTags2024    = "2024"
+	TaskTagsOption2 = "🔥"
+)
+
+// TaskNameFilter returns a filter condition on the "Name" property.
+func TaskNameFilter() filter.TextCondition {
+	return filter.Prop("Name").Title()
+}
+
+// TaskAttachmentsFilter returns a filter condition on the "Attachments" property.
+func TaskAttachmentsFilter() filter.FilesCondition {
+	return filter.Prop("Attachments").Files()
+}
+
+// TaskCreatedFilter returns a filter condition on the "Created" property.
+func TaskCreatedFilter() filter.DateCondition {
+	return filter.Prop("Created").CreatedTime()
+}
+
+// TaskDueDateFilter returns a filter condition on the "Due date" property.
+func TaskDueDateFilter() filter.DateCondition {
+	return filter.Prop("Due date").Date()
+}
+
+// TaskEstimateFilter returns a filter condition on the "Estimate" property.
+func TaskEstimateFilter() filter.NumberCondition {
+	return filter.Prop("Estimate").Number()
+}
+
+// TaskScoreFilter returns a filter condition on the "Score" property.
+func TaskScoreFilter() filter.FormulaCondition {
+	return filter.Prop("Score").Formula()
+}
+
+// TaskStatusFilter returns a filter condition on the "Status" property.
+func TaskStatusFilter() filter.SelectCondition {
+	return filter.Prop("Status").Status()
+}
+
+// TaskTagsFilter returns a filter condition on the "Tags" property.
+func TaskTagsFilter() filter.ContainsCondition {
+	return filter.Prop("Tags").MultiSelect()
+}
+`
+
+const minimalSource = "// Code generated by notion-gen. DO NOT EDIT.\n" + `
+package main
+
+import (
+	"github.com/cryptowizard0/go-notion/filter"
+)
+
+// X2024ReadingListDatabaseID is the ID of the "2024 reading list" database.
+const X2024ReadingListDatabaseID = "db-id"
+
+// X2024ReadingList is a page of the "2024 reading list" database. Use notion.UnmarshalProperties and
+// notion.MarshalProperties to convert it from and to page properties.
+type X2024ReadingList struct {
+	Title  string ` + "`" + `notion:"Title,title"` + "`" + `
+	Title2 string ` + "`" + `notion:"title,rich_text"` + "`" + `
+}
+
+// X2024ReadingListTitleFilter returns a filter condition on the "Title" property.
+func X2024ReadingListTitleFilter() filter.TextCondition {
+	return filter.Prop("Title").Title()
+}
+
+// X2024ReadingListTitle2Filter returns a filter condition on the "title" property.
+func X2024ReadingListTitle2Filter() filter.TextCondition {
+	return filter.Prop("title").RichText()
+}
+`
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	var tasks notion.Database
+	if err := json.Unmarshal([]byte(tasksSchema), &tasks); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		db     notion.Database
+		cfg    config
+		expSrc string
+		expErr error
+	}{
+		{
+			name:   "tasks database",
+			db:     tasks,
+			cfg:    config{pkg: "tasks", typeName: "Task"},
+			expSrc: tasksSource,
+		},
+		{
+			name: "type name from title and duplicate field names",
+			db: notion.Database{
+				ID:    "db-id",
+				Title: []notion.RichText{{PlainText: "2024 reading list"}},
+				Properties: notion.DatabaseProperties{
+					"Title": {Type: notion.DBPropTypeTitle},
+					"title": {Type: notion.DBPropTypeRichText},
+				},
+			},
+			cfg:    config{pkg: "main"},
+			expSrc: minimalSource,
+		},
+		{
+			name:   "invalid type name",
+			db:     tasks,
+			cfg:    config{pkg: "tasks", typeName: "task"},
+			expErr: errors.New(`invalid type name "task"`),
+		},
+		{
+			name: "unsupported property type",
+			db: notion.Database{
+				Title: []notion.RichText{{PlainText: "Tasks"}},
+				Properties: notion.DatabaseProperties{
+					"Button": {Type: "button"},
+				},
+			},
+			cfg:    config{pkg: "tasks"},
+			expErr: errors.New(`property "Button" has unsupported type "button"`),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			src, err := generate(tt.db, tt.cfg)
+
+			if tt.expErr != nil {
+				if err == nil || err.Error() != tt.expErr.Error() {
+					t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.expSrc, string(src)); diff != "" {
+				t.Fatalf("source not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
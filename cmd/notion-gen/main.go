@@ -0,0 +1,120 @@
+// Command notion-gen generates Go types for a Notion database.
+//
+// It reads the database schema from the API, or from a JSON file with a saved
+// database object, and emits:
+//
+//   - A struct with a field per property, tagged for use with
+//     notion.UnmarshalProperties and notion.MarshalProperties.
+//   - Constants for the option names of select, multi-select and status
+//     properties.
+//   - Functions that return filter conditions for each property.
+//
+// Usage:
+//
+//	NOTION_API_KEY=secret_... go run ./cmd/notion-gen -database <id> -package tasks -o tasks_gen.go
+//	go run ./cmd/notion-gen -schema database.json -package tasks -type Task
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+func main() {
+	var (
+		databaseID string
+		schemaFile string
+		baseURL    string
+		output     string
+		cfg        config
+	)
+	flag.StringVar(&databaseID, "database", "", "ID of the database to fetch the schema of. Requires `NOTION_API_KEY`.")
+	flag.StringVar(&schemaFile, "schema", "", "JSON file with a saved database object, used instead of -database.")
+	flag.StringVar(&baseURL, "base-url", "", "Base URL of the Notion API.")
+	flag.StringVar(&cfg.pkg, "package", "main", "Package name of the generated code.")
+	flag.StringVar(&cfg.typeName, "type", "", "Name of the generated struct type. Defaults to the database title.")
+	flag.StringVar(&output, "o", "", "Output file. Defaults to stdout.")
+	flag.Parse()
+
+	if err := run(databaseID, schemaFile, baseURL, output, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "notion-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(databaseID, schemaFile, baseURL, output string, cfg config) error {
+	var (
+		db  notion.Database
+		err error
+	)
+
+	switch {
+	case schemaFile != "" && databaseID != "":
+		return errors.New("-database and -schema are mutually exclusive")
+	case schemaFile != "":
+		db, err = readSchema(schemaFile)
+	case databaseID != "":
+		db, err = fetchSchema(databaseID, baseURL)
+	default:
+		return errors.New("one of -database or -schema is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(db, cfg)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+
+	return os.WriteFile(output, src, 0o644)
+}
+
+func readSchema(name string) (notion.Database, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return notion.Database{}, err
+	}
+
+	var db notion.Database
+	if err := json.Unmarshal(b, &db); err != nil {
+		return notion.Database{}, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	return db, nil
+}
+
+func fetchSchema(databaseID, baseURL string) (notion.Database, error) {
+	apiKey := os.Getenv("NOTION_API_KEY")
+	if apiKey == "" {
+		return notion.Database{}, errors.New("NOTION_API_KEY is not set")
+	}
+
+	opts := []notion.ClientOption{}
+	if baseURL != "" {
+		opts = append(opts, notion.WithBaseURL(baseURL))
+	}
+	client := notion.NewClient(apiKey, opts...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	db, err := client.FindDatabaseByID(ctx, databaseID)
+	if err != nil {
+		return notion.Database{}, fmt.Errorf("failed to find database: %w", err)
+	}
+
+	return db, nil
+}
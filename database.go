@@ -58,6 +58,9 @@ type (
 		RollupPropID     string         `json:"rollup_property_id,omitempty"`
 		Function         RollupFunction `json:"function,omitempty"`
 	}
+	UniqueIDMetadata struct {
+		Prefix *string `json:"prefix"`
+	}
 )
 
 type DualPropertyRelation struct {
@@ -137,8 +140,10 @@ type File struct {
 }
 
 type DatabaseProperty struct {
-	ID   string               `json:"id,omitempty"`
-	Type DatabasePropertyType `json:"type"`
+	ID string `json:"id,omitempty"`
+	// Type is omitted when updating a property without changing its type,
+	// e.g. to rename it.
+	Type DatabasePropertyType `json:"type,omitempty"`
 	Name string               `json:"name,omitempty"`
 
 	Title          *EmptyMetadata `json:"title,omitempty"`
@@ -162,6 +167,7 @@ type DatabaseProperty struct {
 	Relation    *RelationMetadata `json:"relation,omitempty"`
 	Rollup      *RollupMetadata   `json:"rollup,omitempty"`
 	Status      *StatusMetadata   `json:"status,omitempty"`
+	UniqueID    *UniqueIDMetadata `json:"unique_id,omitempty"`
 }
 
 // DatabaseQuery is used for quering a database.
@@ -215,6 +221,9 @@ type DatabaseQueryPropertyFilter struct {
 
 	CreatedBy    *PeopleDatabaseQueryFilter `json:"created_by,omitempty"`
 	LastEditedBy *PeopleDatabaseQueryFilter `json:"last_edited_by,omitempty"`
+
+	// UniqueID filters on the number of a unique ID, without its prefix.
+	UniqueID *NumberDatabaseQueryFilter `json:"unique_id,omitempty"`
 }
 
 type Timestamp string
@@ -370,6 +379,7 @@ const (
 	DBPropTypeCreatedBy      DatabasePropertyType = "created_by"
 	DBPropTypeLastEditedTime DatabasePropertyType = "last_edited_time"
 	DBPropTypeLastEditedBy   DatabasePropertyType = "last_edited_by"
+	DBPropTypeUniqueID       DatabasePropertyType = "unique_id"
 
 	// Used for paginated property values.
 	// See: https://developers.notion.com/reference/property-item-object#paginated-property-values
@@ -0,0 +1,424 @@
+// Package dbexport exports the pages of a Notion database in tabular formats.
+//
+// Each page is a row, and each database property is a column. Property values
+// are flattened to plain values: rich text to plain text, people to names (or
+// emails), relations to page IDs, dates to ISO 8601 dates or intervals, and
+// formulas and rollups to their result. Rows are streamed as they're queried:
+//
+//	n, err := dbexport.Export(ctx, client, databaseID, os.Stdout, dbexport.Options{
+//		Format: dbexport.FormatCSV,
+//	})
+package dbexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// Format is an export format.
+type Format string
+
+const (
+	// FormatCSV writes a header with the column names, followed by a record
+	// per page. Multiple values, e.g. of multi-select properties, are joined
+	// with ", ".
+	FormatCSV Format = "csv"
+
+	// FormatJSONLines writes a JSON object per page, with a field per column,
+	// in column order. Numbers and checkboxes are JSON numbers and booleans,
+	// multiple values are arrays, and empty values are null.
+	FormatJSONLines Format = "jsonl"
+)
+
+// PeopleFormat is how users are flattened, e.g. of people properties.
+type PeopleFormat string
+
+const (
+	PeopleFormatName  PeopleFormat = "name"
+	PeopleFormatEmail PeopleFormat = "email"
+)
+
+// Options configures an export.
+type Options struct {
+	// Format defaults to FormatCSV.
+	Format Format
+
+	// Query is used to filter and sort pages. Its cursor and page size are
+	// managed by Export.
+	Query *notion.DatabaseQuery
+
+	// People defaults to PeopleFormatName. Users without a name or email,
+	// e.g. bots or users that aren't shared with the integration, are
+	// flattened to their ID.
+	People PeopleFormat
+
+	// PageIDColumn is the name of an extra first column with page IDs. It's
+	// omitted if empty. It can't be the name of a database property.
+	PageIDColumn string
+}
+
+// Export queries all pages of a database, and writes them to w in the format
+// of opts. It returns the number of exported pages.
+func Export(ctx context.Context, client *notion.Client, databaseID string, w io.Writer, opts Options) (int, error) {
+	db, err := client.FindDatabaseByID(ctx, databaseID)
+	if err != nil {
+		return 0, fmt.Errorf("dbexport: failed to find database: %w", err)
+	}
+
+	if _, ok := db.Properties[opts.PageIDColumn]; ok {
+		return 0, fmt.Errorf("dbexport: page ID column %q has the name of a database property", opts.PageIDColumn)
+	}
+
+	columns := Columns(db.Properties)
+	header := columns
+	if opts.PageIDColumn != "" {
+		header = append([]string{opts.PageIDColumn}, columns...)
+	}
+
+	rw, err := newRowWriter(w, opts.Format, header)
+	if err != nil {
+		return 0, err
+	}
+
+	var query notion.DatabaseQuery
+	if opts.Query != nil {
+		query = *opts.Query
+	}
+	query.StartCursor = ""
+	query.PageSize = 100
+
+	var n int
+
+	it := client.QueryDatabaseIter(databaseID, &query)
+	for it.Next(ctx) {
+		page := it.Value()
+		props, _ := page.Properties.(notion.DatabasePageProperties)
+
+		values := make([]interface{}, 0, len(header))
+		if opts.PageIDColumn != "" {
+			values = append(values, page.ID)
+		}
+		for _, name := range columns {
+			values = append(values, Flatten(props[name], opts.People))
+		}
+
+		if err := rw.writeRow(values); err != nil {
+			return n, fmt.Errorf("dbexport: failed to write row: %w", err)
+		}
+		n++
+	}
+	if err := it.Err(); err != nil {
+		return n, fmt.Errorf("dbexport: failed to query database: %w", err)
+	}
+
+	if err := rw.flush(); err != nil {
+		return n, fmt.Errorf("dbexport: failed to write rows: %w", err)
+	}
+
+	return n, nil
+}
+
+// Columns returns the names of database properties in export order: the title
+// property first, followed by the other properties sorted by name.
+func Columns(props notion.DatabaseProperties) []string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		iTitle, jTitle := props[names[i]].Type == notion.DBPropTypeTitle, props[names[j]].Type == notion.DBPropTypeTitle
+		if iTitle != jTitle {
+			return iTitle
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Flatten returns the value of a page property as a string, float64, bool or
+// []string. Empty values are nil, except for properties with multiple values,
+// which are empty slices, and checkboxes.
+func Flatten(prop notion.DatabasePageProperty, people PeopleFormat) interface{} {
+	switch prop.Type {
+	case notion.DBPropTypeTitle:
+		return textValue(prop.Title)
+	case notion.DBPropTypeRichText:
+		return textValue(prop.RichText)
+	case notion.DBPropTypeNumber:
+		return floatValue(prop.Number)
+	case notion.DBPropTypeSelect:
+		return optionValue(prop.Select)
+	case notion.DBPropTypeStatus:
+		return optionValue(prop.Status)
+	case notion.DBPropTypeMultiSelect:
+		values := make([]string, len(prop.MultiSelect))
+		for i, opt := range prop.MultiSelect {
+			values[i] = opt.Name
+		}
+		return values
+	case notion.DBPropTypeDate:
+		return dateValue(prop.Date)
+	case notion.DBPropTypePeople:
+		values := make([]string, len(prop.People))
+		for i, user := range prop.People {
+			values[i] = userValue(user, people)
+		}
+		return values
+	case notion.DBPropTypeFiles:
+		values := make([]string, len(prop.Files))
+		for i, file := range prop.Files {
+			values[i] = fileValue(file)
+		}
+		return values
+	case notion.DBPropTypeCheckbox:
+		if prop.Checkbox == nil {
+			return false
+		}
+		return *prop.Checkbox
+	case notion.DBPropTypeURL:
+		return stringValue(prop.URL)
+	case notion.DBPropTypeEmail:
+		return stringValue(prop.Email)
+	case notion.DBPropTypePhoneNumber:
+		return stringValue(prop.PhoneNumber)
+	case notion.DBPropTypeRelation:
+		values := make([]string, len(prop.Relation))
+		for i, rel := range prop.Relation {
+			values[i] = rel.ID
+		}
+		return values
+	case notion.DBPropTypeFormula:
+		if prop.Formula == nil {
+			return nil
+		}
+		return resultValue(prop.Formula.Value(), people)
+	case notion.DBPropTypeRollup:
+		if prop.Rollup == nil {
+			return nil
+		}
+		return resultValue(prop.Rollup.Value(), people)
+	case notion.DBPropTypeCreatedTime:
+		return timeValue(prop.CreatedTime)
+	case notion.DBPropTypeLastEditedTime:
+		return timeValue(prop.LastEditedTime)
+	case notion.DBPropTypeCreatedBy:
+		if prop.CreatedBy == nil {
+			return nil
+		}
+		return userValue(*prop.CreatedBy, people)
+	case notion.DBPropTypeLastEditedBy:
+		if prop.LastEditedBy == nil {
+			return nil
+		}
+		return userValue(*prop.LastEditedBy, people)
+	}
+
+	return nil
+}
+
+// resultValue flattens the value of a formula or rollup result.
+func resultValue(v interface{}, people PeopleFormat) interface{} {
+	switch v := v.(type) {
+	case *string:
+		return stringValue(v)
+	case *float64:
+		return floatValue(v)
+	case *bool:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *notion.Date:
+		return dateValue(v)
+	case []notion.DatabasePageProperty:
+		// Rollups of arrays are flattened to the strings of their items.
+		var values []string
+		for _, item := range v {
+			values = append(values, formatValue(Flatten(item, people))...)
+		}
+		if values == nil {
+			values = []string{}
+		}
+		return values
+	}
+	return nil
+}
+
+func textValue(richText []notion.RichText) interface{} {
+	s := notion.PlainText(richText)
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func stringValue(s *string) interface{} {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return *s
+}
+
+func floatValue(f *float64) interface{} {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
+
+func optionValue(opt *notion.SelectOptions) interface{} {
+	if opt == nil {
+		return nil
+	}
+	return opt.Name
+}
+
+// dateValue returns a date as an ISO 8601 date, date-time, or interval of two
+// dates or date-times separated by "/".
+func dateValue(date *notion.Date) interface{} {
+	if date == nil {
+		return nil
+	}
+	s := dateTimeValue(date.Start)
+	if date.End != nil {
+		s += "/" + dateTimeValue(*date.End)
+	}
+	return s
+}
+
+func dateTimeValue(dt notion.DateTime) string {
+	if dt.HasTime() {
+		return dt.Format(time.RFC3339)
+	}
+	return dt.Format("2006-01-02")
+}
+
+func timeValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
+
+func userValue(user notion.User, people PeopleFormat) string {
+	if people == PeopleFormatEmail && user.Person != nil && user.Person.Email != "" {
+		return user.Person.Email
+	}
+	if people != PeopleFormatEmail && user.Name != "" {
+		return user.Name
+	}
+	return user.ID
+}
+
+func fileValue(file notion.File) string {
+	switch {
+	case file.Name != "":
+		return file.Name
+	case file.External != nil:
+		return file.External.URL
+	case file.File != nil:
+		return file.File.URL
+	}
+	return ""
+}
+
+// formatValue formats a flattened value as strings, for CSV records and
+// rollup arrays.
+func formatValue(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(v)}
+	case []string:
+		return v
+	}
+	return nil
+}
+
+// rowWriter writes rows in an export format.
+type rowWriter interface {
+	writeRow(values []interface{}) error
+	flush() error
+}
+
+func newRowWriter(w io.Writer, format Format, header []string) (rowWriter, error) {
+	switch format {
+	case FormatCSV, "":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return nil, fmt.Errorf("dbexport: failed to write header: %w", err)
+		}
+		return &csvWriter{w: cw}, nil
+	case FormatJSONLines:
+		return &jsonLinesWriter{w: w, columns: header}, nil
+	}
+
+	return nil, fmt.Errorf("dbexport: unsupported format %q", format)
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (cw *csvWriter) writeRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = strings.Join(formatValue(v), ", ")
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *csvWriter) flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+type jsonLinesWriter struct {
+	w       io.Writer
+	columns []string
+	buf     bytes.Buffer
+}
+
+// writeRow writes a JSON object with fields in column order, which isn't
+// possible by encoding a map.
+func (jw *jsonLinesWriter) writeRow(values []interface{}) error {
+	jw.buf.Reset()
+	jw.buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			jw.buf.WriteByte(',')
+		}
+		key, err := json.Marshal(jw.columns[i])
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		jw.buf.Write(key)
+		jw.buf.WriteByte(':')
+		jw.buf.Write(value)
+	}
+	jw.buf.WriteString("}\n")
+
+	_, err := jw.w.Write(jw.buf.Bytes())
+	return err
+}
+
+func (jw *jsonLinesWriter) flush() error {
+	return nil
+}
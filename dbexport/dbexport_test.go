@@ -0,0 +1,241 @@
+package dbexport_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/dbexport"
+	"github.com/cryptowizard0/go-notion/notiontest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func richText(s string) []notion.RichText {
+	return []notion.RichText{{Text: &notion.Text{Content: s}}}
+}
+
+func mustParseDateTime(value string) notion.DateTime {
+	dt, err := notion.ParseDateTime(value)
+	if err != nil {
+		panic(err)
+	}
+	return dt
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client := srv.Client()
+	root := srv.AddPage("Root")
+
+	db, err := client.CreateDatabase(ctx, notion.CreateDatabaseParams{
+		ParentPageID: root.ID,
+		Title:        richText("Tasks"),
+		Properties: notion.DatabaseProperties{
+			"Name":     {Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+			"Notes":    {Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}},
+			"Estimate": {Type: notion.DBPropTypeNumber, Number: &notion.NumberMetadata{}},
+			"Done":     {Type: notion.DBPropTypeCheckbox, Checkbox: &notion.EmptyMetadata{}},
+			"Due":      {Type: notion.DBPropTypeDate, Date: &notion.EmptyMetadata{}},
+			"Tags":     {Type: notion.DBPropTypeMultiSelect, MultiSelect: &notion.SelectMetadata{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	end := mustParseDateTime("2021-05-20")
+
+	var pageIDs []string
+	for _, props := range []notion.DatabasePageProperties{
+		{
+			"Name":     {Title: richText("Write docs")},
+			"Notes":    {RichText: richText(`Say "hi", twice`)},
+			"Estimate": {Number: notion.Float64Ptr(1.5)},
+			"Done":     {Checkbox: notion.BoolPtr(true)},
+			"Due": {Date: &notion.Date{
+				Start: mustParseDateTime("2021-05-18"),
+				End:   &end,
+			}},
+			"Tags": {MultiSelect: []notion.SelectOptions{{Name: "docs"}, {Name: "easy"}}},
+		},
+		{
+			"Name": {Title: richText("Fix bug")},
+		},
+	} {
+		page, err := client.CreatePage(ctx, notion.CreatePageParams{
+			ParentType:             notion.ParentTypeDatabase,
+			ParentID:               db.ID,
+			DatabasePageProperties: &props,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pageIDs = append(pageIDs, page.ID)
+	}
+
+	tests := []struct {
+		name   string
+		opts   dbexport.Options
+		expOut string
+		expErr string
+	}{
+		{
+			name: "csv",
+			opts: dbexport.Options{Format: dbexport.FormatCSV},
+			expOut: "Name,Done,Due,Estimate,Notes,Tags\n" +
+				`Write docs,true,2021-05-18/2021-05-20,1.5,"Say ""hi"", twice","docs, easy"` + "\n" +
+				"Fix bug,false,,,,\n",
+		},
+		{
+			name: "json lines with page IDs",
+			opts: dbexport.Options{Format: dbexport.FormatJSONLines, PageIDColumn: "id"},
+			expOut: `{"id":"` + pageIDs[0] + `","Name":"Write docs","Done":true,"Due":"2021-05-18/2021-05-20",` +
+				`"Estimate":1.5,"Notes":"Say \"hi\", twice","Tags":["docs","easy"]}` + "\n" +
+				`{"id":"` + pageIDs[1] + `","Name":"Fix bug","Done":false,"Due":null,` +
+				`"Estimate":null,"Notes":null,"Tags":[]}` + "\n",
+		},
+		{
+			name:   "page ID column with property name",
+			opts:   dbexport.Options{Format: dbexport.FormatCSV, PageIDColumn: "Name"},
+			expErr: `dbexport: page ID column "Name" has the name of a database property`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			n, err := dbexport.Export(ctx, client, db.ID, &buf, tt.opts)
+			if tt.expErr != "" {
+				if err == nil || err.Error() != tt.expErr {
+					t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != 2 {
+				t.Fatalf("expected 2 rows, got: %v", n)
+			}
+
+			if diff := cmp.Diff(tt.expOut, buf.String()); diff != "" {
+				t.Fatalf("output not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	user := notion.User{
+		BaseUser: notion.BaseUser{ID: "user-id"},
+		Name:     "Jane Doe",
+		Person:   &notion.Person{Email: "jane@example.com"},
+	}
+
+	tests := []struct {
+		name   string
+		prop   notion.DatabasePageProperty
+		people dbexport.PeopleFormat
+		exp    interface{}
+	}{
+		{
+			name: "people as names",
+			prop: notion.DatabasePageProperty{
+				Type:   notion.DBPropTypePeople,
+				People: []notion.User{user, {BaseUser: notion.BaseUser{ID: "bot-id"}}},
+			},
+			exp: []string{"Jane Doe", "bot-id"},
+		},
+		{
+			name: "people as emails",
+			prop: notion.DatabasePageProperty{
+				Type:   notion.DBPropTypePeople,
+				People: []notion.User{user},
+			},
+			people: dbexport.PeopleFormatEmail,
+			exp:    []string{"jane@example.com"},
+		},
+		{
+			name: "relation",
+			prop: notion.DatabasePageProperty{
+				Type:     notion.DBPropTypeRelation,
+				Relation: []notion.Relation{{ID: "page-1"}, {ID: "page-2"}},
+			},
+			exp: []string{"page-1", "page-2"},
+		},
+		{
+			name: "date with time",
+			prop: notion.DatabasePageProperty{
+				Type: notion.DBPropTypeDate,
+				Date: &notion.Date{Start: mustParseDateTime("2021-05-18T12:30:00.000Z")},
+			},
+			exp: "2021-05-18T12:30:00Z",
+		},
+		{
+			name: "number formula",
+			prop: notion.DatabasePageProperty{
+				Type: notion.DBPropTypeFormula,
+				Formula: &notion.FormulaResult{
+					Type:   notion.FormulaResultTypeNumber,
+					Number: notion.Float64Ptr(42),
+				},
+			},
+			exp: float64(42),
+		},
+		{
+			name: "empty string formula",
+			prop: notion.DatabasePageProperty{
+				Type:    notion.DBPropTypeFormula,
+				Formula: &notion.FormulaResult{Type: notion.FormulaResultTypeString},
+			},
+			exp: nil,
+		},
+		{
+			name: "array rollup",
+			prop: notion.DatabasePageProperty{
+				Type: notion.DBPropTypeRollup,
+				Rollup: &notion.RollupResult{
+					Type: notion.RollupResultTypeArray,
+					Array: []notion.DatabasePageProperty{
+						{Type: notion.DBPropTypeTitle, Title: richText("Write docs")},
+						{Type: notion.DBPropTypeMultiSelect, MultiSelect: []notion.SelectOptions{{Name: "a"}, {Name: "b"}}},
+						{Type: notion.DBPropTypeNumber, Number: notion.Float64Ptr(3)},
+					},
+				},
+			},
+			exp: []string{"Write docs", "a", "b", "3"},
+		},
+		{
+			name: "created by",
+			prop: notion.DatabasePageProperty{
+				Type:      notion.DBPropTypeCreatedBy,
+				CreatedBy: &user,
+			},
+			exp: "Jane Doe",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := dbexport.Flatten(tt.prop, tt.people)
+
+			if diff := cmp.Diff(tt.exp, got); diff != "" {
+				t.Fatalf("value not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
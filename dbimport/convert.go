@@ -0,0 +1,160 @@
+package dbimport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// column is a CSV column that's imported into a database property.
+type column struct {
+	index    int
+	property string
+	propType notion.DatabasePropertyType
+}
+
+// mapColumns maps the header of a table to the properties of a database.
+// Headers are mapped with mapping, or else to the property with the same name,
+// ignoring case. Headers mapped to "" are skipped.
+func mapColumns(header []string, props notion.DatabaseProperties, mapping map[string]string) ([]column, error) {
+	lower := make(map[string]string, len(props))
+	for name := range props {
+		lower[strings.ToLower(name)] = name
+	}
+
+	var (
+		columns  []column
+		unknown  []string
+		assigned = make(map[string]string)
+	)
+
+	for i, h := range header {
+		name, ok := mapping[h]
+		if ok && name == "" {
+			continue
+		}
+		if !ok {
+			name = h
+		}
+
+		prop, ok := props[name]
+		if !ok {
+			if actual, found := lower[strings.ToLower(name)]; found {
+				name, prop, ok = actual, props[actual], true
+			}
+		}
+		if !ok {
+			unknown = append(unknown, h)
+			continue
+		}
+
+		if !isImportable(prop.Type) {
+			return nil, fmt.Errorf("dbimport: column %q maps to property %q of unsupported type %v", h, name, prop.Type)
+		}
+		if other, ok := assigned[name]; ok {
+			return nil, fmt.Errorf("dbimport: columns %q and %q both map to property %q", other, h, name)
+		}
+		assigned[name] = h
+
+		columns = append(columns, column{index: i, property: name, propType: prop.Type})
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("dbimport: columns without matching property: %v", strings.Join(unknown, ", "))
+	}
+
+	return columns, nil
+}
+
+func isImportable(propType notion.DatabasePropertyType) bool {
+	switch propType {
+	case notion.DBPropTypeTitle, notion.DBPropTypeRichText, notion.DBPropTypeNumber,
+		notion.DBPropTypeCheckbox, notion.DBPropTypeDate, notion.DBPropTypeSelect,
+		notion.DBPropTypeMultiSelect, notion.DBPropTypeStatus, notion.DBPropTypeURL,
+		notion.DBPropTypeEmail, notion.DBPropTypePhoneNumber, notion.DBPropTypeRelation,
+		notion.DBPropTypePeople:
+		return true
+	}
+	return false
+}
+
+// rowProperties converts a CSV record to page properties. Empty values are
+// omitted, except for the title.
+func rowProperties(record []string, columns []column, sep string) (notion.DatabasePageProperties, error) {
+	props := make(notion.DatabasePageProperties, len(columns))
+
+	for _, col := range columns {
+		var value string
+		if col.index < len(record) {
+			value = strings.TrimSpace(record[col.index])
+		}
+		if value == "" && col.propType != notion.DBPropTypeTitle {
+			continue
+		}
+
+		prop, err := propertyValue(col.propType, value, sep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of column %q: %w", col.property, err)
+		}
+		props[col.property] = prop
+	}
+
+	return props, nil
+}
+
+// propertyValue converts a CSV value to a page property value.
+func propertyValue(propType notion.DatabasePropertyType, value, sep string) (notion.DatabasePageProperty, error) {
+	prop := notion.DatabasePageProperty{Type: propType}
+
+	switch propType {
+	case notion.DBPropTypeTitle:
+		prop.Title = notion.TextRichText(value)
+	case notion.DBPropTypeRichText:
+		prop.RichText = notion.TextRichText(value)
+	case notion.DBPropTypeNumber:
+		n, err := parseNumber(value)
+		if err != nil {
+			return notion.DatabasePageProperty{}, fmt.Errorf("%q is not a number", value)
+		}
+		prop.Number = &n
+	case notion.DBPropTypeCheckbox:
+		b, ok := parseBool(value)
+		if !ok {
+			return notion.DatabasePageProperty{}, fmt.Errorf("%q is not a boolean", value)
+		}
+		prop.Checkbox = &b
+	case notion.DBPropTypeDate:
+		dt, err := parseDate(value)
+		if err != nil {
+			return notion.DatabasePageProperty{}, fmt.Errorf("%q is not a date", value)
+		}
+		prop.Date = &notion.Date{Start: dt}
+	case notion.DBPropTypeSelect:
+		prop.Select = &notion.SelectOptions{Name: value}
+	case notion.DBPropTypeStatus:
+		prop.Status = &notion.SelectOptions{Name: value}
+	case notion.DBPropTypeMultiSelect:
+		for _, name := range splitList(value, sep) {
+			prop.MultiSelect = append(prop.MultiSelect, notion.SelectOptions{Name: name})
+		}
+	case notion.DBPropTypeURL:
+		prop.URL = &value
+	case notion.DBPropTypeEmail:
+		prop.Email = &value
+	case notion.DBPropTypePhoneNumber:
+		prop.PhoneNumber = &value
+	case notion.DBPropTypeRelation:
+		for _, id := range splitList(value, sep) {
+			prop.Relation = append(prop.Relation, notion.Relation{ID: id})
+		}
+	case notion.DBPropTypePeople:
+		for _, id := range splitList(value, sep) {
+			prop.People = append(prop.People, notion.User{BaseUser: notion.BaseUser{ID: id}})
+		}
+	default:
+		return notion.DatabasePageProperty{}, fmt.Errorf("unsupported property type %v", propType)
+	}
+
+	return prop, nil
+}
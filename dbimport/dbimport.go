@@ -0,0 +1,330 @@
+// Package dbimport imports CSV files into Notion databases.
+//
+// Rows are imported into an existing database, with columns mapped to its
+// properties by name, or into a new database with a schema inferred from the
+// columns:
+//
+//	table, err := dbimport.ReadCSV(f)
+//	if err != nil {
+//		// Handle error...
+//	}
+//
+//	report, err := dbimport.Import(ctx, client, table, dbimport.Options{
+//		ParentPageID: parentPageID,
+//		Title:        "Customers",
+//		ProgressFile: "customers.progress",
+//	})
+//
+// With a progress file, an import that failed or was interrupted can be run
+// again with the same CSV file and options: the database created by the first
+// run is reused, and rows that were already imported are skipped.
+package dbimport
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+const (
+	// DefaultConcurrency is the number of pages created in parallel when
+	// Options.Concurrency isn't set.
+	DefaultConcurrency = 3
+
+	// DefaultSeparator separates the values of multi-select, relation and
+	// people columns when Options.Separator isn't set.
+	DefaultSeparator = ","
+)
+
+// Table is a parsed CSV file.
+type Table struct {
+	Header []string
+
+	// Rows are the records following the header. Row numbers, e.g. in a
+	// Report, start at 1 for the first row.
+	Rows [][]string
+}
+
+// ReadCSV reads a CSV file with a header. A leading byte order mark is
+// removed, and records may have fewer or more fields than the header.
+func ReadCSV(r io.Reader) (*Table, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("dbimport: CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dbimport: failed to read CSV header: %w", err)
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("dbimport: failed to read CSV rows: %w", err)
+	}
+
+	return &Table{Header: header, Rows: rows}, nil
+}
+
+// Options configures an import.
+type Options struct {
+	// DatabaseID is the ID of the database to import into. If it's empty, a
+	// database is created in ParentPageID, with Title and a schema inferred
+	// with InferSchema.
+	DatabaseID   string
+	ParentPageID string
+	Title        string
+
+	// TitleColumn is the column of the title property of a new database.
+	// Defaults to the first column.
+	TitleColumn string
+
+	// Columns maps CSV headers to property names, if they differ. Columns
+	// that aren't mapped are imported into the property with the same name,
+	// ignoring case. Columns mapped to "" are skipped. Import returns an error
+	// if any other column has no matching property.
+	Columns map[string]string
+
+	// Separator separates the values of multi-select, relation (page IDs) and
+	// people (user IDs) columns. Defaults to DefaultSeparator.
+	Separator string
+
+	// Concurrency is the number of pages created in parallel. Defaults to
+	// DefaultConcurrency.
+	Concurrency int
+
+	// RateLimiter paces page creation, in addition to any rate limit of the
+	// client.
+	RateLimiter *notion.RateLimiter
+
+	// ProgressFile is the path of a file where the database and created pages
+	// are recorded, so a failed import can be resumed. It's created if it
+	// doesn't exist.
+	ProgressFile string
+}
+
+// Report is the result of an import.
+type Report struct {
+	DatabaseID string
+
+	// Created is the number of pages created by this run, and Skipped the
+	// number of rows that were imported by a previous run.
+	Created int
+	Skipped int
+
+	// Failures are the rows that failed to import, ordered by row number.
+	Failures []Failure
+
+	// ProgressErr is the first error that occurred while recording a created
+	// page in the progress file. Such rows are counted as created, but are
+	// imported again if the import is resumed.
+	ProgressErr error
+}
+
+// Failure is a row that failed to import.
+type Failure struct {
+	Row int
+	Err error
+}
+
+// Error implements `error`.
+func (f Failure) Error() string {
+	return fmt.Sprintf("dbimport: failed to import row %v: %v", f.Row, f.Err)
+}
+
+// Unwrap returns the underlying error.
+func (f Failure) Unwrap() error {
+	return f.Err
+}
+
+// Err returns an error that joins all failures and the progress error, or nil
+// if there are none.
+func (r *Report) Err() error {
+	if len(r.Failures) == 0 && r.ProgressErr == nil {
+		return nil
+	}
+
+	return &FailuresError{Failures: r.Failures, ProgressErr: r.ProgressErr}
+}
+
+// FailuresError is returned by Report.Err if any row failed, or if the
+// progress file couldn't be written.
+type FailuresError struct {
+	Failures    []Failure
+	ProgressErr error
+}
+
+// Error implements `error`.
+func (err *FailuresError) Error() string {
+	msg := fmt.Sprintf("dbimport: %v row(s) failed", len(err.Failures))
+	if len(err.Failures) > 0 {
+		msg += "; first error: " + err.Failures[0].Error()
+	}
+	if err.ProgressErr != nil {
+		msg += "; " + err.ProgressErr.Error()
+	}
+	return msg
+}
+
+// Import creates a page for each row of table. It returns an error if the
+// database can't be found or created, or if the columns can't be mapped to its
+// properties. If ctx is done, its error is returned along with the report of
+// the rows imported so far. Rows with invalid values, or that fail to be
+// created, are reported in the Report.
+func Import(ctx context.Context, client *notion.Client, table *Table, opts Options) (*Report, error) {
+	if opts.Separator == "" {
+		opts.Separator = DefaultSeparator
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = DefaultConcurrency
+	}
+
+	prog, err := openProgress(opts.ProgressFile)
+	if err != nil {
+		return nil, fmt.Errorf("dbimport: failed to open progress file: %w", err)
+	}
+	defer prog.close()
+
+	db, err := importDatabase(ctx, client, table, opts, prog)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := mapColumns(table.Header, db.Properties, opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{DatabaseID: db.ID}
+	errs := make([]error, len(table.Rows))
+	pageIDs := make([]string, len(table.Rows))
+	progressErrs := make([]error, len(table.Rows))
+
+	var wg sync.WaitGroup
+	rows := make(chan int)
+
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rows {
+				pageIDs[i], errs[i] = importRow(ctx, client, db.ID, table.Rows[i], columns, opts)
+				if errs[i] == nil {
+					progressErrs[i] = prog.markDone(i+1, pageIDs[i])
+				}
+			}
+		}()
+	}
+
+loop:
+	for i := range table.Rows {
+		if prog.done(i + 1) {
+			report.Skipped++
+			continue
+		}
+		select {
+		case rows <- i:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(rows)
+	wg.Wait()
+
+	for i, err := range errs {
+		switch {
+		case err != nil:
+			report.Failures = append(report.Failures, Failure{Row: i + 1, Err: err})
+		case pageIDs[i] != "":
+			report.Created++
+		}
+		if progressErrs[i] != nil && report.ProgressErr == nil {
+			report.ProgressErr = fmt.Errorf("dbimport: failed to write progress file: %w", progressErrs[i])
+		}
+	}
+
+	return report, ctx.Err()
+}
+
+// importDatabase returns the database to import into. It's created if
+// there's no database ID in the options or progress file.
+func importDatabase(ctx context.Context, client *notion.Client, table *Table, opts Options, prog *progress) (notion.Database, error) {
+	databaseID := opts.DatabaseID
+	switch {
+	case databaseID != "" && prog.databaseID != "" && prog.databaseID != databaseID:
+		return notion.Database{}, fmt.Errorf("dbimport: progress file is for database %v", prog.databaseID)
+	case databaseID == "":
+		databaseID = prog.databaseID
+	}
+
+	if databaseID != "" {
+		db, err := client.FindDatabaseByID(ctx, databaseID)
+		if err != nil {
+			return notion.Database{}, fmt.Errorf("dbimport: failed to find database: %w", err)
+		}
+		if prog.databaseID == "" {
+			if err := prog.setDatabase(db.ID); err != nil {
+				return notion.Database{}, fmt.Errorf("dbimport: failed to write progress file: %w", err)
+			}
+		}
+		return db, nil
+	}
+
+	if opts.ParentPageID == "" {
+		return notion.Database{}, errors.New("dbimport: database ID or parent page ID is required")
+	}
+
+	db, err := client.CreateDatabase(ctx, notion.CreateDatabaseParams{
+		ParentPageID: opts.ParentPageID,
+		Title:        []notion.RichText{{Text: &notion.Text{Content: opts.Title}}},
+		Properties:   InferSchema(table, opts.TitleColumn, opts.Separator),
+	})
+	if err != nil {
+		return notion.Database{}, fmt.Errorf("dbimport: failed to create database: %w", err)
+	}
+	if err := prog.setDatabase(db.ID); err != nil {
+		return notion.Database{}, fmt.Errorf("dbimport: failed to write progress file: %w", err)
+	}
+
+	return db, nil
+}
+
+// importRow creates the page for a row, and returns its ID.
+func importRow(
+	ctx context.Context,
+	client *notion.Client,
+	databaseID string,
+	record []string,
+	columns []column,
+	opts Options,
+) (string, error) {
+	props, err := rowProperties(record, columns, opts.Separator)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.RateLimiter != nil {
+		if err := opts.RateLimiter.Wait(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	page, err := client.CreatePage(ctx, notion.CreatePageParams{
+		ParentType:             notion.ParentTypeDatabase,
+		ParentID:               databaseID,
+		DatabasePageProperties: &props,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return page.ID, nil
+}
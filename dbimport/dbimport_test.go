@@ -0,0 +1,254 @@
+package dbimport_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/dbimport"
+	"github.com/cryptowizard0/go-notion/notiontest"
+	"github.com/google/go-cmp/cmp"
+)
+
+const customersCSV = "\ufeffName,Email,Signed up,Plan,Tags,Active,Seats,Website,Notes\n" +
+	"Acme,ops@acme.test,2021-05-18,Pro,\"b2b, enterprise\",yes,25,https://acme.test,\n" +
+	"Globex,it@globex.test,2021-06-01,Free,b2b,no,3,https://globex.test,\"Asked for a demo, twice\"\n" +
+	"Initech,admin@initech.test,2021-07-12T09:30:00Z,Pro,,true,,,Churn risk\n"
+
+func TestInferSchema(t *testing.T) {
+	t.Parallel()
+
+	table, err := dbimport.ReadCSV(strings.NewReader(customersCSV))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := dbimport.InferSchema(table, "", "")
+
+	exp := notion.DatabaseProperties{
+		"Name":      {Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+		"Email":     {Type: notion.DBPropTypeEmail, Email: &notion.EmptyMetadata{}},
+		"Signed up": {Type: notion.DBPropTypeDate, Date: &notion.EmptyMetadata{}},
+		"Plan": {Type: notion.DBPropTypeSelect, Select: &notion.SelectMetadata{
+			Options: []notion.SelectOptions{{Name: "Pro"}, {Name: "Free"}},
+		}},
+		"Tags": {Type: notion.DBPropTypeMultiSelect, MultiSelect: &notion.SelectMetadata{
+			Options: []notion.SelectOptions{{Name: "b2b"}, {Name: "enterprise"}},
+		}},
+		"Active":  {Type: notion.DBPropTypeCheckbox, Checkbox: &notion.EmptyMetadata{}},
+		"Seats":   {Type: notion.DBPropTypeNumber, Number: &notion.NumberMetadata{Format: notion.NumberFormatNumber}},
+		"Website": {Type: notion.DBPropTypeURL, URL: &notion.EmptyMetadata{}},
+		"Notes":   {Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}},
+	}
+
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Fatalf("schema not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+
+	var (
+		mu         sync.Mutex
+		operations []string
+		failGlobex = true
+	)
+	client := srv.Client(notion.WithMiddleware(func(next notion.Doer) notion.Doer {
+		return notion.DoerFunc(func(req *notion.Request) (*notion.Response, error) {
+			mu.Lock()
+			operations = append(operations, req.Operation)
+			fail := failGlobex
+			mu.Unlock()
+
+			if fail && req.Operation == "CreatePage" {
+				body, _ := req.HTTPRequest.GetBody()
+				b := new(strings.Builder)
+				_, _ = io.Copy(b, body)
+				if strings.Contains(b.String(), "Globex") {
+					return nil, errors.New("connection reset")
+				}
+			}
+			return next.Do(req)
+		})
+	}))
+
+	root := srv.AddPage("Root")
+	progressFile := filepath.Join(t.TempDir(), "customers.progress")
+
+	table, err := dbimport.ReadCSV(strings.NewReader(customersCSV))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	opts := dbimport.Options{
+		ParentPageID: root.ID,
+		Title:        "Customers",
+		ProgressFile: progressFile,
+	}
+
+	report, err := dbimport.Import(ctx, client, table, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Created != 2 || report.Skipped != 0 || len(report.Failures) != 1 || report.Failures[0].Row != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Err() == nil {
+		t.Fatal("expected error")
+	}
+
+	progress, err := os.ReadFile(progressFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(progress), "database "+report.DatabaseID+"\n") {
+		t.Fatalf("unexpected progress file: %q", progress)
+	}
+
+	// Simulate an interruption while the progress of the Globex row was
+	// written, which leaves a partial last line.
+	f, err := os.OpenFile(progressFile, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("row 2 5a6b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Resume the import.
+	mu.Lock()
+	failGlobex = false
+	operations = nil
+	mu.Unlock()
+
+	resumed, err := dbimport.Import(ctx, client, table, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exp := &dbimport.Report{DatabaseID: report.DatabaseID, Created: 1, Skipped: 2}
+	if diff := cmp.Diff(exp, resumed); diff != "" {
+		t.Fatalf("report not equal (-exp, +got):\n%v", diff)
+	}
+	if diff := cmp.Diff([]string{"FindDatabaseByID", "CreatePage"}, operations); diff != "" {
+		t.Fatalf("operations not equal (-exp, +got):\n%v", diff)
+	}
+
+	// Resuming again skips all rows, as the partial line was dropped.
+	resumed, err = dbimport.Import(ctx, client, table, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exp = &dbimport.Report{DatabaseID: report.DatabaseID, Skipped: 3}
+	if diff := cmp.Diff(exp, resumed); diff != "" {
+		t.Fatalf("report not equal (-exp, +got):\n%v", diff)
+	}
+
+	pages, err := client.QueryDatabaseAll(ctx, report.DatabaseID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, page := range pages {
+		props := page.Properties.(notion.DatabasePageProperties)
+		names = append(names, props["Name"].Title[0].PlainText)
+	}
+	sort.Strings(names)
+	if diff := cmp.Diff([]string{"Acme", "Globex", "Initech"}, names); diff != "" {
+		t.Fatalf("page names not equal (-exp, +got):\n%v", diff)
+	}
+
+	t.Run("existing database", func(t *testing.T) {
+		t.Parallel()
+
+		table, err := dbimport.ReadCSV(strings.NewReader("Customer,SEATS,Ignored\nHooli,many,x\nPied Piper,4,y\n"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		report, err := dbimport.Import(ctx, client, table, dbimport.Options{
+			DatabaseID: resumed.DatabaseID,
+			Columns:    map[string]string{"Customer": "Name", "Ignored": ""},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Created != 1 || len(report.Failures) != 1 {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+		if exp := `dbimport: failed to import row 1: invalid value of column "Seats": "many" is not a number`; report.Failures[0].Error() != exp {
+			t.Fatalf("error not equal (expected: %v, got: %v)", exp, report.Failures[0].Error())
+		}
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		t.Parallel()
+
+		table, err := dbimport.ReadCSV(strings.NewReader("Name,Region\nHooli,US\n"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = dbimport.Import(ctx, client, table, dbimport.Options{DatabaseID: resumed.DatabaseID})
+		if exp := "dbimport: columns without matching property: Region"; err == nil || err.Error() != exp {
+			t.Fatalf("error not equal (expected: %v, got: %v)", exp, err)
+		}
+	})
+}
+
+func TestImportCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+
+	// Cancel the run once the first page is created.
+	client := srv.Client(notion.WithMiddleware(func(next notion.Doer) notion.Doer {
+		return notion.DoerFunc(func(req *notion.Request) (*notion.Response, error) {
+			res, err := next.Do(req)
+			if req.Operation == "CreatePage" {
+				cancel()
+			}
+			return res, err
+		})
+	}))
+	root := srv.AddPage("Root")
+
+	table, err := dbimport.ReadCSV(strings.NewReader("Name\n" + strings.Repeat("Hooli\n", 10)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report, err := dbimport.Import(ctx, client, table, dbimport.Options{
+		ParentPageID: root.ID,
+		Title:        "Customers",
+		Concurrency:  1,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error not equal (expected: %v, got: %v)", context.Canceled, err)
+	}
+	if report == nil || report.Created != 1 {
+		t.Fatalf("expected partial report with 1 created page, got: %+v", report)
+	}
+	for _, failure := range report.Failures {
+		if !errors.Is(failure, context.Canceled) {
+			t.Errorf("unexpected failure: %v", failure)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package dbimport
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// progress records the database and the created pages of an import in a
+// file, so a failed import can be resumed. The file has a line per event:
+//
+//	database 668d797c-76fa-4934-9b05-ad288df2d136
+//	row 1 1c2d3e4f-…
+//	row 3 5a6b7c8d-…
+//
+// Without a file, progress is only kept in memory.
+type progress struct {
+	mu         sync.Mutex
+	f          *os.File
+	databaseID string
+	rows       map[int]string
+}
+
+// openProgress reads the progress file at path, if it exists, and opens it
+// for appending.
+func openProgress(path string) (*progress, error) {
+	p := &progress{rows: make(map[int]string)}
+	if path == "" {
+		return p, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	// The last line is partially written if an import was interrupted while
+	// writing it. It's dropped, and its row is imported again.
+	complete := data[:bytes.LastIndexByte(data, '\n')+1]
+	if err := p.read(complete); err != nil {
+		return nil, fmt.Errorf("invalid progress file %v: %w", path, err)
+	}
+
+	p.f, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if len(complete) < len(data) {
+		if err := p.f.Truncate(int64(len(complete))); err != nil {
+			p.f.Close()
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// read reads the entries of a progress file.
+func (p *progress) read(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "database" && len(fields) == 2:
+			p.databaseID = fields[1]
+		case fields[0] == "row" && len(fields) == 3:
+			row, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return fmt.Errorf("line %v: invalid row number %q", line, fields[1])
+			}
+			p.rows[row] = fields[2]
+		default:
+			return fmt.Errorf("line %v: invalid entry %q", line, scanner.Text())
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (p *progress) setDatabase(id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.databaseID = id
+	return p.write("database " + id)
+}
+
+func (p *progress) done(row int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.rows[row]
+	return ok
+}
+
+func (p *progress) markDone(row int, pageID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rows[row] = pageID
+	return p.write(fmt.Sprintf("row %v %v", row, pageID))
+}
+
+func (p *progress) write(line string) error {
+	if p.f == nil {
+		return nil
+	}
+	_, err := p.f.WriteString(line + "\n")
+	return err
+}
+
+func (p *progress) close() error {
+	if p.f == nil {
+		return nil
+	}
+	return p.f.Close()
+}
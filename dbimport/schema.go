@@ -0,0 +1,231 @@
+package dbimport
+
+import (
+	"errors"
+	"math"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// Limits for inferring select and multi-select properties. Columns with more
+// distinct values, or longer values, are inferred as rich text.
+const (
+	maxSelectOptions    = 50
+	maxSelectOptionSize = 100
+)
+
+// InferSchema infers database properties from the columns of a table. The
+// title column (the first column, if titleColumn is empty) becomes the title
+// property. Other columns are inferred from their non-empty values, as the
+// first type that matches all of them:
+//
+//   - checkbox: "true", "false", "yes" or "no" (case insensitive)
+//   - number
+//   - date: ISO 8601 dates, or date-times with or without time zone
+//   - url: absolute http(s) URLs
+//   - email
+//   - multi_select: lists separated by sep, with at most 50 distinct
+//     options, which repeat
+//   - select: at most 50 distinct options, which repeat
+//   - rich_text
+//
+// Columns without values are inferred as rich text. Options of select and
+// multi-select properties are in order of appearance.
+func InferSchema(table *Table, titleColumn, sep string) notion.DatabaseProperties {
+	if titleColumn == "" && len(table.Header) > 0 {
+		titleColumn = table.Header[0]
+	}
+	if sep == "" {
+		sep = DefaultSeparator
+	}
+
+	props := make(notion.DatabaseProperties, len(table.Header))
+
+	for i, name := range table.Header {
+		if name == titleColumn {
+			props[name] = notion.DatabaseProperty{Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}}
+			continue
+		}
+
+		var values []string
+		for _, record := range table.Rows {
+			if i < len(record) {
+				if v := strings.TrimSpace(record[i]); v != "" {
+					values = append(values, v)
+				}
+			}
+		}
+
+		props[name] = inferProperty(values, sep)
+	}
+
+	return props
+}
+
+func inferProperty(values []string, sep string) notion.DatabaseProperty {
+	if len(values) == 0 {
+		return notion.DatabaseProperty{Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}}
+	}
+
+	switch {
+	case all(values, isBool):
+		return notion.DatabaseProperty{Type: notion.DBPropTypeCheckbox, Checkbox: &notion.EmptyMetadata{}}
+	case all(values, isNumber):
+		return notion.DatabaseProperty{Type: notion.DBPropTypeNumber, Number: &notion.NumberMetadata{Format: notion.NumberFormatNumber}}
+	case all(values, isDate):
+		return notion.DatabaseProperty{Type: notion.DBPropTypeDate, Date: &notion.EmptyMetadata{}}
+	case all(values, isURL):
+		return notion.DatabaseProperty{Type: notion.DBPropTypeURL, URL: &notion.EmptyMetadata{}}
+	case all(values, isEmail):
+		return notion.DatabaseProperty{Type: notion.DBPropTypeEmail, Email: &notion.EmptyMetadata{}}
+	}
+
+	var multi bool
+	for _, v := range values {
+		if strings.Contains(v, sep) {
+			multi = true
+			break
+		}
+	}
+
+	if multi {
+		if options, n, ok := selectOptions(values, sep); ok && len(options) < n {
+			return notion.DatabaseProperty{Type: notion.DBPropTypeMultiSelect, MultiSelect: &notion.SelectMetadata{Options: options}}
+		}
+	} else if options, n, ok := selectOptions(values, ""); ok && len(options) < n {
+		return notion.DatabaseProperty{Type: notion.DBPropTypeSelect, Select: &notion.SelectMetadata{Options: options}}
+	}
+
+	return notion.DatabaseProperty{Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}}
+}
+
+// selectOptions returns the distinct values, split by sep if it's not empty,
+// and the total number of values. It reports false if there are too many
+// options, or any is too long.
+func selectOptions(values []string, sep string) ([]notion.SelectOptions, int, bool) {
+	var (
+		options []notion.SelectOptions
+		n       int
+	)
+	seen := make(map[string]bool)
+
+	for _, v := range values {
+		names := []string{v}
+		if sep != "" {
+			names = splitList(v, sep)
+		}
+		for _, name := range names {
+			n++
+			if seen[name] {
+				continue
+			}
+			if len(name) > maxSelectOptionSize || len(options) == maxSelectOptions {
+				return nil, 0, false
+			}
+			seen[name] = true
+			options = append(options, notion.SelectOptions{Name: name})
+		}
+	}
+
+	return options, n, true
+}
+
+func all(values []string, fn func(string) bool) bool {
+	for _, v := range values {
+		if !fn(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func isBool(s string) bool {
+	_, ok := parseBool(s)
+	return ok
+}
+
+func isNumber(s string) bool {
+	_, err := parseNumber(s)
+	return err == nil
+}
+
+func isDate(s string) bool {
+	_, err := parseDate(s)
+	return err == nil
+}
+
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "yes":
+		return true, true
+	case "false", "no":
+		return false, true
+	}
+	return false, false
+}
+
+func parseNumber(s string) (float64, error) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, errors.New("number must be finite")
+	}
+	return n, nil
+}
+
+// dateLayouts are the layouts of dates that are parsed, and whether they
+// include time.
+var dateLayouts = []struct {
+	layout  string
+	hasTime bool
+}{
+	{"2006-01-02", false},
+	{time.RFC3339, true},
+	{"2006-01-02T15:04:05", true},
+	{"2006-01-02 15:04:05", true},
+	{"2006-01-02 15:04", true},
+}
+
+func parseDate(s string) (notion.DateTime, error) {
+	s = strings.TrimSpace(s)
+
+	var err error
+	for _, l := range dateLayouts {
+		var t time.Time
+		t, err = time.Parse(l.layout, s)
+		if err == nil {
+			return notion.NewDateTime(t, l.hasTime), nil
+		}
+	}
+
+	return notion.DateTime{}, err
+}
+
+// splitList splits a list of values separated by sep, and trims them. Empty
+// values are omitted.
+func splitList(s, sep string) []string {
+	var values []string
+	for _, v := range strings.Split(s, sep) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,299 @@
+// Package dbsync mirrors rows from an external system into a Notion database.
+//
+// A Syncer compares desired rows, identified by the value of a key property,
+// with the current pages of a database, and computes a Plan of creates,
+// updates and archives. The plan can be reviewed (dry-run) before it's
+// applied:
+//
+//	s := dbsync.New(client, databaseID, dbsync.Options{
+//		KeyProperty: "External ID",
+//		Archive:     true,
+//		Concurrency: 3,
+//	})
+//
+//	plan, err := s.Plan(ctx, rows)
+//	if err != nil {
+//		// Handle error...
+//	}
+//	plan.WriteTo(os.Stdout)
+//
+//	report, err := s.Apply(ctx, plan)
+//
+// Failures of individual changes don't abort a run; they're collected in the
+// Report.
+package dbsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// DefaultConcurrency is the number of changes applied in parallel when
+// Options.Concurrency isn't set.
+const DefaultConcurrency = 3
+
+// Row is a desired database page.
+type Row struct {
+	// Key is the value of the key property, e.g. the primary key of a row in
+	// the source database. Number keys are formatted without exponent, e.g.
+	// "42" or "4.5".
+	Key string
+
+	// Properties are the desired page properties. Properties of current pages
+	// that aren't included are left as is. The key property is added when a
+	// page is created, if it's not included.
+	Properties notion.DatabasePageProperties
+}
+
+// Options configures a Syncer.
+type Options struct {
+	// KeyProperty is the name of the property that identifies pages. It must
+	// be a title, rich text, number, URL, email or phone number property.
+	KeyProperty string
+
+	// Archive enables archiving of current pages that have a key that isn't in
+	// the desired rows. Pages with an empty key are never archived.
+	Archive bool
+
+	// Concurrency is the number of changes applied in parallel. Defaults to
+	// DefaultConcurrency.
+	Concurrency int
+
+	// RateLimiter paces the changes of Apply, in addition to any rate limit of
+	// the client. Loading current pages isn't paced by it.
+	RateLimiter *notion.RateLimiter
+
+	// DryRun makes Sync write the plan to Output instead of applying it.
+	DryRun bool
+
+	// Output is where Sync writes the plan in dry-run mode. The plan isn't
+	// written if it's nil.
+	Output io.Writer
+}
+
+// Syncer syncs desired rows into a database.
+type Syncer struct {
+	client     *notion.Client
+	databaseID string
+	opts       Options
+}
+
+// New returns a new Syncer.
+func New(client *notion.Client, databaseID string, opts Options) *Syncer {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = DefaultConcurrency
+	}
+
+	return &Syncer{
+		client:     client,
+		databaseID: databaseID,
+		opts:       opts,
+	}
+}
+
+// Report is the result of applying a plan.
+type Report struct {
+	Plan *Plan
+
+	Created  int
+	Updated  int
+	Archived int
+
+	// Conflicts are the conflicts of the plan. Rows with their keys weren't
+	// applied.
+	Conflicts []Conflict
+
+	// Failures are the changes that failed, in plan order.
+	Failures []Failure
+}
+
+// Failure is a change that failed.
+type Failure struct {
+	Change Change
+	Err    error
+}
+
+// Error implements `error`.
+func (f Failure) Error() string {
+	return fmt.Sprintf("dbsync: failed to %v page with key %q: %v", f.Change.Type, f.Change.Key, f.Err)
+}
+
+// Unwrap returns the underlying error.
+func (f Failure) Unwrap() error {
+	return f.Err
+}
+
+// Err returns an error that joins all failures and conflicts, or nil if there
+// are none.
+func (r *Report) Err() error {
+	if len(r.Failures) == 0 && len(r.Conflicts) == 0 {
+		return nil
+	}
+
+	return &FailuresError{Failures: r.Failures, Conflicts: r.Conflicts}
+}
+
+// FailuresError is returned by Report.Err if any change failed, or if the plan
+// had conflicts.
+type FailuresError struct {
+	Failures  []Failure
+	Conflicts []Conflict
+}
+
+// Error implements `error`.
+func (err *FailuresError) Error() string {
+	msg := fmt.Sprintf("dbsync: %v change(s) failed", len(err.Failures))
+	if len(err.Conflicts) > 0 {
+		msg += fmt.Sprintf(", %v key(s) used by multiple pages", len(err.Conflicts))
+	}
+	if len(err.Failures) > 0 {
+		msg += "; first error: " + err.Failures[0].Error()
+	}
+	return msg
+}
+
+// Sync plans the changes for rows and applies them. In dry-run mode, the plan
+// is written to Options.Output, and a report without any applied changes is
+// returned.
+func (s *Syncer) Sync(ctx context.Context, rows []Row) (*Report, error) {
+	plan, err := s.Plan(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.opts.DryRun {
+		if s.opts.Output != nil {
+			if _, err := plan.WriteTo(s.opts.Output); err != nil {
+				return nil, fmt.Errorf("dbsync: failed to write plan: %w", err)
+			}
+		}
+		return &Report{Plan: plan}, nil
+	}
+
+	return s.Apply(ctx, plan)
+}
+
+// Apply applies the changes of a plan, with the configured concurrency. It
+// only returns an error if ctx is done, along with the report of the changes
+// applied so far; failures of individual changes are reported in the Report.
+func (s *Syncer) Apply(ctx context.Context, plan *Plan) (*Report, error) {
+	report := &Report{Plan: plan, Conflicts: plan.Conflicts}
+	errs := make([]error, len(plan.Changes))
+	applied := make([]bool, len(plan.Changes))
+
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+
+	for i := 0; i < s.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = s.apply(ctx, plan.Changes[i])
+				applied[i] = true
+			}
+		}()
+	}
+
+loop:
+	for i := range plan.Changes {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, change := range plan.Changes {
+		if !applied[i] {
+			continue
+		}
+		if errs[i] != nil {
+			report.Failures = append(report.Failures, Failure{Change: change, Err: errs[i]})
+			continue
+		}
+
+		switch change.Type {
+		case ChangeTypeCreate:
+			report.Created++
+		case ChangeTypeUpdate:
+			report.Updated++
+		case ChangeTypeArchive:
+			report.Archived++
+		}
+	}
+
+	return report, ctx.Err()
+}
+
+func (s *Syncer) apply(ctx context.Context, change Change) error {
+	if s.opts.RateLimiter != nil {
+		if err := s.opts.RateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	var err error
+
+	switch change.Type {
+	case ChangeTypeCreate:
+		props := change.Properties
+		_, err = s.client.CreatePage(ctx, notion.CreatePageParams{
+			ParentType:             notion.ParentTypeDatabase,
+			ParentID:               s.databaseID,
+			DatabasePageProperties: &props,
+		})
+	case ChangeTypeUpdate:
+		_, err = s.client.UpdatePage(ctx, change.PageID, notion.UpdatePageParams{
+			DatabasePageProperties: change.Properties,
+		})
+	case ChangeTypeArchive:
+		_, err = s.client.UpdatePage(ctx, change.PageID, notion.UpdatePageParams{
+			Archived: notion.BoolPtr(true),
+		})
+	default:
+		err = fmt.Errorf("unknown change type %q", change.Type)
+	}
+
+	return err
+}
+
+// keyString returns the key of a page, given its key property.
+func keyString(prop notion.DatabasePageProperty) (string, error) {
+	switch prop.Type {
+	case notion.DBPropTypeTitle:
+		return notion.PlainText(prop.Title), nil
+	case notion.DBPropTypeRichText:
+		return notion.PlainText(prop.RichText), nil
+	case notion.DBPropTypeNumber:
+		if prop.Number == nil {
+			return "", nil
+		}
+		return strconv.FormatFloat(*prop.Number, 'f', -1, 64), nil
+	case notion.DBPropTypeURL:
+		return derefString(prop.URL), nil
+	case notion.DBPropTypeEmail:
+		return derefString(prop.Email), nil
+	case notion.DBPropTypePhoneNumber:
+		return derefString(prop.PhoneNumber), nil
+	}
+
+	return "", errUnsupportedKeyType
+}
+
+var errUnsupportedKeyType = errors.New("unsupported key property type")
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
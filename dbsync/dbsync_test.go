@@ -0,0 +1,288 @@
+package dbsync_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/dbsync"
+	"github.com/cryptowizard0/go-notion/notiontest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func richText(s string) []notion.RichText {
+	return []notion.RichText{{Text: &notion.Text{Content: s}}}
+}
+
+func ticket(key, name string, estimate float64) notion.DatabasePageProperties {
+	props := notion.DatabasePageProperties{
+		"Name":     {Title: richText(name)},
+		"Estimate": {Number: notion.Float64Ptr(estimate)},
+	}
+	if key != "" {
+		props["External ID"] = notion.DatabasePageProperty{RichText: richText(key)}
+	}
+	return props
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client := srv.Client()
+	root := srv.AddPage("Root")
+
+	db, err := client.CreateDatabase(ctx, notion.CreateDatabaseParams{
+		ParentPageID: root.ID,
+		Title:        richText("Tickets"),
+		Properties: notion.DatabaseProperties{
+			"Name":        {Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+			"External ID": {Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}},
+			"Estimate":    {Type: notion.DBPropTypeNumber, Number: &notion.NumberMetadata{}},
+			"Status":      {Type: notion.DBPropTypeStatus, Status: &notion.StatusMetadata{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pageIDs := map[string][]string{}
+	for _, props := range []notion.DatabasePageProperties{
+		ticket("EXT-1", "Write docs", 1),
+		ticket("EXT-2", "Fix bug", 3),
+		ticket("EXT-3", "Old ticket", 2),
+		ticket("EXT-4", "Duplicate", 1),
+		ticket("EXT-4", "Duplicate", 1),
+		ticket("", "Unmanaged", 1),
+	} {
+		props := props
+		page, err := client.CreatePage(ctx, notion.CreatePageParams{
+			ParentType:             notion.ParentTypeDatabase,
+			ParentID:               db.ID,
+			DatabasePageProperties: &props,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		key := props["External ID"].RichText
+		if key != nil {
+			pageIDs[key[0].Text.Content] = append(pageIDs[key[0].Text.Content], page.ID)
+		}
+	}
+
+	invalid := ticket("", "Invalid status", 1)
+	invalid["Status"] = notion.DatabasePageProperty{Status: &notion.SelectOptions{Name: "Unknown"}}
+
+	rows := []dbsync.Row{
+		{Key: "EXT-1", Properties: ticket("EXT-1", "Write docs", 1)},
+		{Key: "EXT-2", Properties: ticket("EXT-2", "Fix bug", 5)},
+		{Key: "EXT-4", Properties: ticket("EXT-4", "Duplicate", 2)},
+		{Key: "EXT-5", Properties: ticket("", "Add export", 8)},
+		{Key: "EXT-6", Properties: invalid},
+	}
+
+	opts := dbsync.Options{
+		KeyProperty: "External ID",
+		Archive:     true,
+		Concurrency: 2,
+		RateLimiter: notion.NewRateLimiter(1000, 10),
+		DryRun:      true,
+	}
+
+	out := &bytes.Buffer{}
+	opts.Output = out
+	report, err := dbsync.New(client, db.ID, opts).Sync(ctx, rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expPlan := fmt.Sprintf(`Plan: 2 to create, 1 to update, 1 to archive, 1 unchanged.
+
+! conflict "EXT-4" (pages %v, %v)
+
++ create "EXT-5"
+    Estimate: "8"
+    External ID: "EXT-5"
+    Name: "Add export"
+
++ create "EXT-6"
+    Estimate: "1"
+    External ID: "EXT-6"
+    Name: "Invalid status"
+    Status: "Unknown"
+
+~ update "EXT-2" (page %v)
+    Estimate: "3" → "5"
+
+- archive "EXT-3" (page %v)
+`, pageIDs["EXT-4"][0], pageIDs["EXT-4"][1], pageIDs["EXT-2"][0], pageIDs["EXT-3"][0])
+	if diff := cmp.Diff(expPlan, out.String()); diff != "" {
+		t.Fatalf("plan not equal (-exp, +got):\n%v", diff)
+	}
+	if report.Created+report.Updated+report.Archived != 0 || len(report.Failures) != 0 {
+		t.Fatalf("expected dry-run not to apply changes, got: %+v", report)
+	}
+
+	opts.DryRun = false
+	report, err = dbsync.New(client, db.ID, opts).Sync(ctx, rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Created != 1 || report.Updated != 1 || report.Archived != 1 {
+		t.Errorf("unexpected counts (created: %v, updated: %v, archived: %v)", report.Created, report.Updated, report.Archived)
+	}
+
+	expConflicts := []dbsync.Conflict{{Key: "EXT-4", PageIDs: pageIDs["EXT-4"]}}
+	if diff := cmp.Diff(expConflicts, report.Conflicts); diff != "" {
+		t.Fatalf("conflicts not equal (-exp, +got):\n%v", diff)
+	}
+
+	failedKeys := make([]string, len(report.Failures))
+	for i, failure := range report.Failures {
+		failedKeys[i] = failure.Change.Key
+	}
+	if diff := cmp.Diff([]string{"EXT-6"}, failedKeys); diff != "" {
+		t.Fatalf("failed keys not equal (-exp, +got):\n%v", diff)
+	}
+	if !errors.Is(report.Failures[0], notion.ErrValidation) {
+		t.Errorf("expected validation error, got: %v", report.Failures[0].Err)
+	}
+	if report.Err() == nil {
+		t.Error("expected report error")
+	}
+
+	// A second run only retries the failed row.
+	plan, err := dbsync.New(client, db.ID, opts).Plan(ctx, rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	creates, updates, archives := plan.Counts()
+	if creates != 1 || updates != 0 || archives != 0 || plan.Unchanged != 3 {
+		t.Fatalf("unexpected plan (creates: %v, updates: %v, archives: %v, unchanged: %v)",
+			creates, updates, archives, plan.Unchanged)
+	}
+}
+
+func TestPlanErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client := srv.Client()
+	root := srv.AddPage("Root")
+
+	db, err := client.CreateDatabase(ctx, notion.CreateDatabaseParams{
+		ParentPageID: root.ID,
+		Title:        richText("Tickets"),
+		Properties: notion.DatabaseProperties{
+			"Name":   {Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+			"Status": {Type: notion.DBPropTypeStatus, Status: &notion.StatusMetadata{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		keyProperty string
+		rows        []dbsync.Row
+		expError    string
+	}{
+		{
+			name:        "unknown key property",
+			keyProperty: "External ID",
+			expError:    `dbsync: database has no property "External ID"`,
+		},
+		{
+			name:        "unsupported key property type",
+			keyProperty: "Status",
+			expError:    `dbsync: property "Status" of type status can't be used as key`,
+		},
+		{
+			name:        "duplicate row key",
+			keyProperty: "Name",
+			rows:        []dbsync.Row{{Key: "a"}, {Key: "a"}},
+			expError:    `dbsync: duplicate row key "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := dbsync.New(client, db.ID, dbsync.Options{KeyProperty: tt.keyProperty}).Plan(ctx, tt.rows)
+			if err == nil || err.Error() != tt.expError {
+				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
+			}
+		})
+	}
+}
+
+func TestApplyCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+
+	// Cancel the run once the first page is created.
+	client := srv.Client(notion.WithMiddleware(func(next notion.Doer) notion.Doer {
+		return notion.DoerFunc(func(req *notion.Request) (*notion.Response, error) {
+			res, err := next.Do(req)
+			if req.Operation == "CreatePage" {
+				cancel()
+			}
+			return res, err
+		})
+	}))
+	root := srv.AddPage("Root")
+
+	db, err := client.CreateDatabase(ctx, notion.CreateDatabaseParams{
+		ParentPageID: root.ID,
+		Title:        richText("Tickets"),
+		Properties: notion.DatabaseProperties{
+			"Name": {Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := dbsync.New(client, db.ID, dbsync.Options{KeyProperty: "Name", Concurrency: 1})
+
+	rows := make([]dbsync.Row, 10)
+	for i := range rows {
+		rows[i] = dbsync.Row{Key: fmt.Sprintf("Ticket %v", i)}
+	}
+
+	plan, err := s.Plan(ctx, rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report, err := s.Apply(ctx, plan)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error not equal (expected: %v, got: %v)", context.Canceled, err)
+	}
+	if report == nil || report.Created != 1 {
+		t.Fatalf("expected partial report with 1 created page, got: %+v", report)
+	}
+	for _, failure := range report.Failures {
+		if !errors.Is(failure, context.Canceled) {
+			t.Errorf("unexpected failure: %v", failure)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package dbsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/internal/pageprop"
+)
+
+// ChangeType is the type of a planned change.
+type ChangeType string
+
+const (
+	ChangeTypeCreate  ChangeType = "create"
+	ChangeTypeUpdate  ChangeType = "update"
+	ChangeTypeArchive ChangeType = "archive"
+)
+
+// Change is a planned change of a database page.
+type Change struct {
+	Type ChangeType
+	Key  string
+
+	// PageID is the ID of the page to update or archive.
+	PageID string
+
+	// Properties are the properties sent to the API: all desired properties
+	// for creates, and only the differing properties for updates.
+	Properties notion.DatabasePageProperties
+
+	// Diffs are the property-level differences of an update, sorted by
+	// property name.
+	Diffs []PropertyDiff
+}
+
+// PropertyDiff is the difference between the current and desired value of a
+// page property, formatted for display.
+type PropertyDiff struct {
+	Property string
+	Current  string
+	Desired  string
+}
+
+// Conflict is a key that's used by more than one current page. Rows with the
+// key are skipped, and its pages are never archived.
+type Conflict struct {
+	Key     string
+	PageIDs []string
+}
+
+// Plan is the set of changes that syncs desired rows into a database.
+type Plan struct {
+	DatabaseID  string
+	KeyProperty string
+
+	// Changes are ordered by type (creates, updates, archives), then in the
+	// order of the desired rows and current pages, respectively.
+	Changes []Change
+
+	// Unchanged is the number of rows that match their current page.
+	Unchanged int
+
+	Conflicts []Conflict
+}
+
+// Plan loads the current pages of the database, and computes the changes
+// needed to sync rows into it. It returns an error if rows contain a key more
+// than once.
+func (s *Syncer) Plan(ctx context.Context, rows []Row) (*Plan, error) {
+	db, err := s.client.FindDatabaseByID(ctx, s.databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("dbsync: failed to find database: %w", err)
+	}
+
+	keySchema, ok := db.Properties[s.opts.KeyProperty]
+	if !ok {
+		return nil, fmt.Errorf("dbsync: database has no property %q", s.opts.KeyProperty)
+	}
+	if !pageprop.IsKeyType(string(keySchema.Type)) {
+		return nil, fmt.Errorf("dbsync: property %q of type %v can't be used as key", s.opts.KeyProperty, keySchema.Type)
+	}
+
+	pages, err := s.client.QueryDatabaseAll(ctx, s.databaseID, &notion.DatabaseQuery{PageSize: 100})
+	if err != nil {
+		return nil, fmt.Errorf("dbsync: failed to query database: %w", err)
+	}
+
+	plan := &Plan{
+		DatabaseID:  s.databaseID,
+		KeyProperty: s.opts.KeyProperty,
+	}
+
+	// Index current pages by key, in query order.
+	var keys []string
+	current := make(map[string][]notion.Page)
+	for _, page := range pages {
+		props, _ := page.Properties.(notion.DatabasePageProperties)
+		key, err := keyString(props[s.opts.KeyProperty])
+		if err != nil {
+			return nil, fmt.Errorf("dbsync: invalid key of page %v: %w", page.ID, err)
+		}
+		if key == "" {
+			continue
+		}
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+		current[key] = append(current[key], page)
+	}
+
+	for _, key := range keys {
+		if len(current[key]) < 2 {
+			continue
+		}
+		conflict := Conflict{Key: key}
+		for _, page := range current[key] {
+			conflict.PageIDs = append(conflict.PageIDs, page.ID)
+		}
+		plan.Conflicts = append(plan.Conflicts, conflict)
+	}
+
+	var creates, updates, archives []Change
+	desired := make(map[string]bool, len(rows))
+
+	for _, row := range rows {
+		if row.Key == "" {
+			return nil, fmt.Errorf("dbsync: row has empty key")
+		}
+		if desired[row.Key] {
+			return nil, fmt.Errorf("dbsync: duplicate row key %q", row.Key)
+		}
+		desired[row.Key] = true
+
+		pages := current[row.Key]
+		switch len(pages) {
+		case 0:
+			props := notion.DatabasePageProperties{}
+			if _, ok := row.Properties[s.opts.KeyProperty]; !ok {
+				keyProp, err := pageprop.KeyProperty(string(keySchema.Type), row.Key)
+				if err != nil {
+					return nil, fmt.Errorf("dbsync: invalid row key: %w", err)
+				}
+				props[s.opts.KeyProperty] = keyProp.(notion.DatabasePageProperty)
+			}
+			for name, prop := range row.Properties {
+				props[name] = prop
+			}
+			creates = append(creates, Change{Type: ChangeTypeCreate, Key: row.Key, Properties: props})
+		case 1:
+			change, ok := updateChange(row, pages[0])
+			if !ok {
+				plan.Unchanged++
+				continue
+			}
+			updates = append(updates, change)
+		}
+	}
+
+	if s.opts.Archive {
+		for _, key := range keys {
+			if desired[key] || len(current[key]) > 1 {
+				continue
+			}
+			archives = append(archives, Change{Type: ChangeTypeArchive, Key: key, PageID: current[key][0].ID})
+		}
+	}
+
+	plan.Changes = append(plan.Changes, creates...)
+	plan.Changes = append(plan.Changes, updates...)
+	plan.Changes = append(plan.Changes, archives...)
+
+	return plan, nil
+}
+
+// updateChange returns the update of page for row. It reports false if the
+// page is up to date.
+func updateChange(row Row, page notion.Page) (Change, bool) {
+	current, _ := page.Properties.(notion.DatabasePageProperties)
+
+	change := Change{
+		Type:       ChangeTypeUpdate,
+		Key:        row.Key,
+		PageID:     page.ID,
+		Properties: notion.DatabasePageProperties{},
+	}
+
+	for name, prop := range row.Properties {
+		if pageprop.ValuesEqual(current[name], prop) {
+			continue
+		}
+		change.Properties[name] = prop
+		change.Diffs = append(change.Diffs, PropertyDiff{
+			Property: name,
+			Current:  formatValue(current[name]),
+			Desired:  formatValue(prop),
+		})
+	}
+
+	if len(change.Diffs) == 0 {
+		return Change{}, false
+	}
+
+	sort.Slice(change.Diffs, func(i, j int) bool {
+		return change.Diffs[i].Property < change.Diffs[j].Property
+	})
+
+	return change, true
+}
+
+// Counts returns the number of planned creates, updates and archives.
+func (p *Plan) Counts() (creates, updates, archives int) {
+	for _, change := range p.Changes {
+		switch change.Type {
+		case ChangeTypeCreate:
+			creates++
+		case ChangeTypeUpdate:
+			updates++
+		case ChangeTypeArchive:
+			archives++
+		}
+	}
+	return creates, updates, archives
+}
+
+// WriteTo writes a human readable summary of the plan to w, e.g.:
+//
+//	Plan: 1 to create, 1 to update, 1 to archive, 12 unchanged.
+//
+//	+ create "EXT-4"
+//	    Name: "Add export"
+//
+//	~ update "EXT-2" (page 0a7d…)
+//	    Estimate: "3" → "5"
+//
+//	- archive "EXT-3" (page 5c1e…)
+func (p *Plan) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	creates, updates, archives := p.Counts()
+	fmt.Fprintf(&buf, "Plan: %v to create, %v to update, %v to archive, %v unchanged.\n",
+		creates, updates, archives, p.Unchanged)
+
+	for _, conflict := range p.Conflicts {
+		fmt.Fprintf(&buf, "\n! conflict %q (pages %v)\n", conflict.Key, strings.Join(conflict.PageIDs, ", "))
+	}
+
+	for _, change := range p.Changes {
+		switch change.Type {
+		case ChangeTypeCreate:
+			fmt.Fprintf(&buf, "\n+ create %q\n", change.Key)
+			names := make([]string, 0, len(change.Properties))
+			for name := range change.Properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Fprintf(&buf, "    %v: %q\n", name, formatValue(change.Properties[name]))
+			}
+		case ChangeTypeUpdate:
+			fmt.Fprintf(&buf, "\n~ update %q (page %v)\n", change.Key, change.PageID)
+			for _, diff := range change.Diffs {
+				fmt.Fprintf(&buf, "    %v: %q → %q\n", diff.Property, diff.Current, diff.Desired)
+			}
+		case ChangeTypeArchive:
+			fmt.Fprintf(&buf, "\n- archive %q (page %v)\n", change.Key, change.PageID)
+		}
+	}
+
+	return buf.WriteTo(w)
+}
+
+// formatValue formats a property value for display.
+func formatValue(prop notion.DatabasePageProperty) string {
+	selectName := func(opt *notion.SelectOptions) string {
+		if opt == nil {
+			return ""
+		}
+		return opt.Name
+	}
+
+	var values []string
+
+	switch {
+	case prop.Title != nil:
+		return notion.PlainText(prop.Title)
+	case prop.RichText != nil:
+		return notion.PlainText(prop.RichText)
+	case prop.Number != nil:
+		return strconv.FormatFloat(*prop.Number, 'f', -1, 64)
+	case prop.Select != nil:
+		return selectName(prop.Select)
+	case prop.Status != nil:
+		return selectName(prop.Status)
+	case prop.MultiSelect != nil:
+		for _, opt := range prop.MultiSelect {
+			values = append(values, opt.Name)
+		}
+	case prop.Date != nil:
+		b, _ := prop.Date.Start.MarshalJSON()
+		s, _ := strconv.Unquote(string(b))
+		if prop.Date.End != nil {
+			b, _ := prop.Date.End.MarshalJSON()
+			end, _ := strconv.Unquote(string(b))
+			s += " → " + end
+		}
+		return s
+	case prop.Relation != nil:
+		for _, rel := range prop.Relation {
+			values = append(values, rel.ID)
+		}
+	case prop.People != nil:
+		for _, user := range prop.People {
+			values = append(values, user.ID)
+		}
+	case prop.Files != nil:
+		for _, file := range prop.Files {
+			values = append(values, file.Name)
+		}
+	case prop.Checkbox != nil:
+		return strconv.FormatBool(*prop.Checkbox)
+	case prop.URL != nil:
+		return *prop.URL
+	case prop.Email != nil:
+		return *prop.Email
+	case prop.PhoneNumber != nil:
+		return *prop.PhoneNumber
+	case prop.Type == notion.DBPropTypeCheckbox:
+		return "false"
+	}
+
+	return strings.Join(values, ", ")
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // See: https://developers.notion.com/reference/errors.
@@ -22,6 +25,13 @@ var (
 	ErrServiceUnavailable = errors.New("notion: service is unavailable")
 )
 
+// Errors for responses of a gateway in between, which have no Notion error
+// code.
+var (
+	ErrBadGateway     = errors.New("notion: bad gateway")
+	ErrGatewayTimeout = errors.New("notion: gateway timeout")
+)
+
 var errMap = map[string]error{
 	"invalid_json":          ErrInvalidJSON,
 	"invalid_request_url":   ErrInvalidRequestURL,
@@ -36,19 +46,66 @@ var errMap = map[string]error{
 	"service_unavailable":   ErrServiceUnavailable,
 }
 
+// statusErrMap maps HTTP status codes of error responses without a Notion
+// error object (e.g. from a proxy in between) to errors.
+var statusErrMap = map[int]error{
+	http.StatusTooManyRequests:     ErrRateLimited,
+	http.StatusInternalServerError: ErrInternalServer,
+	http.StatusBadGateway:          ErrBadGateway,
+	http.StatusServiceUnavailable:  ErrServiceUnavailable,
+	http.StatusGatewayTimeout:      ErrGatewayTimeout,
+}
+
+// APIError is an error response of the Notion API. Responses that don't
+// contain a Notion error object, e.g. an HTML page from a proxy in between,
+// result in an APIError without a Code.
+// See: https://developers.notion.com/reference/errors
 type APIError struct {
-	Object  string `json:"object"`
-	Status  int    `json:"status"`
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Object    string `json:"object"`
+	Status    int    `json:"status"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+
+	// Method and Path of the HTTP request.
+	Method string `json:"-"`
+	Path   string `json:"-"`
+
+	// RetryAfter is the delay requested by the API via the `Retry-After`
+	// response header, or zero if the header is absent.
+	RetryAfter time.Duration `json:"-"`
+
+	// Header and Body of the HTTP response. Body is the raw response body,
+	// which is useful when it couldn't be decoded.
+	Header http.Header `json:"-"`
+	Body   []byte      `json:"-"`
 }
 
 // Error implements `error`.
 func (err *APIError) Error() string {
-	return fmt.Sprintf("%v (code: %v, status: %v)", err.Message, err.Code, err.Status)
+	var sb strings.Builder
+
+	sb.WriteString(err.Message)
+	sb.WriteString(" (")
+	if err.Code != "" {
+		fmt.Fprintf(&sb, "code: %v, ", err.Code)
+	}
+	fmt.Fprintf(&sb, "status: %v", err.Status)
+	if err.RequestID != "" {
+		fmt.Fprintf(&sb, ", request ID: %v", err.RequestID)
+	}
+	sb.WriteString(")")
+
+	return sb.String()
 }
 
 func (err *APIError) Unwrap() error {
+	if err.Code == "" {
+		if mapped, ok := statusErrMap[err.Status]; ok {
+			return mapped
+		}
+	}
+
 	mapped, ok := errMap[err.Code]
 	if !ok {
 		return fmt.Errorf("notion: %v", err.Error())
@@ -57,12 +114,71 @@ func (err *APIError) Unwrap() error {
 	return mapped
 }
 
+// IsRetryable reports whether the request can safely be retried, e.g. when it
+// was rate limited, or when the API was (temporarily) unavailable.
+func (err *APIError) IsRetryable() bool {
+	if err.Code != "" {
+		return retryableErrCodes[err.Code]
+	}
+
+	switch err.Status {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsClientError reports whether the error is caused by the request, i.e. the
+// response has a 4xx status code.
+func (err *APIError) IsClientError() bool {
+	return err.Status >= 400 && err.Status < 500
+}
+
+// IsServerError reports whether the error is caused by the server, i.e. the
+// response has a 5xx status code.
+func (err *APIError) IsServerError() bool {
+	return err.Status >= 500 && err.Status < 600
+}
+
 func parseErrorResponse(res *http.Response) error {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read error from HTTP response: %w", err)
+	}
+
+	return newAPIError(res, body)
+}
+
+// newAPIError returns the API error of an error response, given its body.
+func newAPIError(res *http.Response, body []byte) *APIError {
 	var apiErr APIError
 
-	err := json.NewDecoder(res.Body).Decode(&apiErr)
-	if err != nil {
-		return fmt.Errorf("failed to parse error from HTTP response: %w", err)
+	if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Code == "" {
+		apiErr = APIError{Message: http.StatusText(res.StatusCode)}
+		if apiErr.Message == "" {
+			apiErr.Message = "unexpected response"
+		}
+	}
+
+	apiErr.Body = body
+	apiErr.Header = res.Header
+	if apiErr.Status == 0 {
+		apiErr.Status = res.StatusCode
+	}
+	if requestID := res.Header.Get("X-Request-Id"); requestID != "" {
+		apiErr.RequestID = requestID
+	}
+	if delay, ok := retryAfter(res); ok {
+		apiErr.RetryAfter = delay
+	}
+	if res.Request != nil {
+		apiErr.Method = res.Request.Method
+		apiErr.Path = res.Request.URL.Path
 	}
 
 	return &apiErr
@@ -0,0 +1,142 @@
+package notion_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		statusCode     int
+		header         http.Header
+		body           string
+		expError       string
+		expSentinel    error
+		expCode        string
+		expRequestID   string
+		expRetryAfter  time.Duration
+		expRetryable   bool
+		expClientError bool
+	}{
+		{
+			name:       "notion error object",
+			statusCode: http.StatusTooManyRequests,
+			header: http.Header{
+				"X-Request-Id": []string{"req-123"},
+				"Retry-After":  []string{"2"},
+			},
+			body: `{
+				"object": "error",
+				"status": 429,
+				"code": "rate_limited",
+				"message": "You have been rate limited."
+			}`,
+			expError:       "notion: failed to find page: You have been rate limited. (code: rate_limited, status: 429, request ID: req-123)",
+			expSentinel:    notion.ErrRateLimited,
+			expCode:        "rate_limited",
+			expRequestID:   "req-123",
+			expRetryAfter:  2 * time.Second,
+			expRetryable:   true,
+			expClientError: true,
+		},
+		{
+			name:       "request ID in body",
+			statusCode: http.StatusNotFound,
+			body: `{
+				"object": "error",
+				"status": 404,
+				"code": "object_not_found",
+				"message": "Could not find page.",
+				"request_id": "req-456"
+			}`,
+			expError:       "notion: failed to find page: Could not find page. (code: object_not_found, status: 404, request ID: req-456)",
+			expSentinel:    notion.ErrObjectNotFound,
+			expCode:        "object_not_found",
+			expRequestID:   "req-456",
+			expClientError: true,
+		},
+		{
+			name:         "html body",
+			statusCode:   http.StatusBadGateway,
+			body:         "<html><body><h1>502 Bad Gateway</h1></body></html>",
+			expError:     "notion: failed to find page: Bad Gateway (status: 502)",
+			expSentinel:  notion.ErrBadGateway,
+			expRetryable: true,
+		},
+		{
+			name:         "empty body",
+			statusCode:   http.StatusServiceUnavailable,
+			expError:     "notion: failed to find page: Service Unavailable (status: 503)",
+			expSentinel:  notion.ErrServiceUnavailable,
+			expRetryable: true,
+		},
+		{
+			name:         "gateway timeout",
+			statusCode:   http.StatusGatewayTimeout,
+			expError:     "notion: failed to find page: Gateway Timeout (status: 504)",
+			expSentinel:  notion.ErrGatewayTimeout,
+			expRetryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key, values := range tt.header {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := notion.NewClient("secret-api-key", notion.WithBaseURL(srv.URL))
+			_, err := client.FindPageByID(context.Background(), "page-id")
+
+			if err == nil || err.Error() != tt.expError {
+				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
+			}
+			if tt.expSentinel != nil && !errors.Is(err, tt.expSentinel) {
+				t.Fatalf("expected error to wrap %v", tt.expSentinel)
+			}
+
+			var apiErr *notion.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *notion.APIError, got: %T", err)
+			}
+			if apiErr.Code != tt.expCode {
+				t.Errorf("code not equal (expected: %v, got: %v)", tt.expCode, apiErr.Code)
+			}
+			if apiErr.Method != http.MethodGet || apiErr.Path != "/pages/page-id" {
+				t.Errorf("unexpected request: %v %v", apiErr.Method, apiErr.Path)
+			}
+			if apiErr.RequestID != tt.expRequestID {
+				t.Errorf("request ID not equal (expected: %v, got: %v)", tt.expRequestID, apiErr.RequestID)
+			}
+			if apiErr.RetryAfter != tt.expRetryAfter {
+				t.Errorf("retry after not equal (expected: %v, got: %v)", tt.expRetryAfter, apiErr.RetryAfter)
+			}
+			if string(apiErr.Body) != tt.body {
+				t.Errorf("body not equal (expected: %q, got: %q)", tt.body, apiErr.Body)
+			}
+			if apiErr.IsRetryable() != tt.expRetryable {
+				t.Errorf("retryable not equal (expected: %v, got: %v)", tt.expRetryable, apiErr.IsRetryable())
+			}
+			if apiErr.IsClientError() != tt.expClientError {
+				t.Errorf("client error not equal (expected: %v, got: %v)", tt.expClientError, apiErr.IsClientError())
+			}
+		})
+	}
+}
@@ -1,11 +1,8 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"flag"
-	"io"
 	"log"
 	"net/http"
 	"os"
@@ -15,32 +12,22 @@ import (
 	"github.com/sanity-io/litter"
 )
 
-type httpTransport struct {
-	w io.Writer
-}
-
-// RoundTrip implements http.RoundTripper. It multiplexes the read HTTP response
-// data to an io.Writer for debugging.
-func (t *httpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	res, err := http.DefaultTransport.RoundTrip(req)
-	if err != nil {
-		return nil, err
-	}
-
-	res.Body = io.NopCloser(io.TeeReader(res.Body, t.w))
-
-	return res, nil
-}
-
 func main() {
 	ctx := context.Background()
 	apiKey := os.Getenv("NOTION_API_KEY")
-	buf := &bytes.Buffer{}
 	httpClient := &http.Client{
-		Timeout:   10 * time.Second,
-		Transport: &httpTransport{w: buf},
+		Timeout: 10 * time.Second,
 	}
-	client := notion.NewClient(apiKey, notion.WithHTTPClient(httpClient))
+	// Log requests and responses for debugging.
+	logger := notion.NewStdLogger(log.New(os.Stderr, "", log.LstdFlags), notion.LogLevelDebug)
+	client := notion.NewClient(apiKey,
+		notion.WithHTTPClient(httpClient),
+		notion.WithLogger(logger, &notion.LoggerOptions{
+			Level:       notion.LogLevelDebug,
+			ErrorLevel:  notion.LogLevelError,
+			MaxBodySize: 64 * 1024,
+		}),
+	)
 
 	var parentPageID string
 	flag.StringVar(&parentPageID, "parentPageId", "", "Parent page ID.")
@@ -57,13 +44,10 @@ func main() {
 			},
 		},
 		Children: []notion.Block{
-
-			notion.BlockDTO{
-				Image: &notion.ImageBlock{
-					Type: notion.FileTypeExternal,
-					External: &notion.FileExternal{
-						URL: "https://picsum.photos/600/200.jpg",
-					},
+			notion.ImageBlock{
+				Type: notion.FileTypeExternal,
+				External: &notion.FileExternal{
+					URL: "https://picsum.photos/600/200.jpg",
 				},
 			},
 		},
@@ -73,18 +57,6 @@ func main() {
 		log.Fatalf("Failed to create page: %v", err)
 	}
 
-	decoded := map[string]interface{}{}
-	if err := json.NewDecoder(buf).Decode(&decoded); err != nil {
-		log.Fatal(err)
-	}
-
-	// Pretty print JSON reponse.
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "    ")
-	if err := enc.Encode(decoded); err != nil {
-		log.Fatal(err)
-	}
-
 	// Pretty print parsed `notion.Page` value.
 	litter.Dump(page)
 }
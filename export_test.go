@@ -0,0 +1,4 @@
+package notion
+
+// PropertyValuesEqual is exported for tests.
+var PropertyValuesEqual = propertyValuesEqual
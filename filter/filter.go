@@ -0,0 +1,234 @@
+// Package filter provides a fluent builder for database query filters.
+//
+//	f, err := filter.Prop("Status").Status().Equals("Done").
+//		And(filter.Prop("Due").Date().PastWeek()).
+//		Build()
+//
+// Conditions are typed by property kind, so only operators that are valid for
+// a property type can be used. Use Filter.Validate to check the property kinds
+// against a database schema.
+package filter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// MaxNestingDepth is the maximum nesting depth of compound filters supported by
+// the Notion API.
+// See: https://developers.notion.com/reference/post-database-query-filter#compound-filter-object
+const MaxNestingDepth = 2
+
+type compoundType string
+
+const (
+	compoundAnd compoundType = "and"
+	compoundOr  compoundType = "or"
+)
+
+// Filter is a database query filter: either a single property or timestamp
+// condition, or a compound (`and`/`or`) filter. The zero value is an empty
+// filter, which is invalid.
+type Filter struct {
+	// Single condition.
+	condition *notion.DatabaseQueryFilter
+	kind      notion.DatabasePropertyType
+
+	// Compound filter.
+	compound compoundType
+	filters  []Filter
+
+	depth int
+	err   error
+}
+
+// And returns a compound filter that matches when all filters match.
+func And(filters ...Filter) Filter {
+	return newCompound(compoundAnd, filters)
+}
+
+// Or returns a compound filter that matches when any of the filters match.
+func Or(filters ...Filter) Filter {
+	return newCompound(compoundOr, filters)
+}
+
+// And returns a compound filter that matches when f and all other filters
+// match. If f is an `and` filter itself, the filters are appended to it.
+func (f Filter) And(filters ...Filter) Filter {
+	return f.combine(compoundAnd, filters)
+}
+
+// Or returns a compound filter that matches when f or any of the other
+// filters match. If f is an `or` filter itself, the filters are appended to
+// it.
+func (f Filter) Or(filters ...Filter) Filter {
+	return f.combine(compoundOr, filters)
+}
+
+func (f Filter) combine(typ compoundType, filters []Filter) Filter {
+	if f.compound == typ {
+		all := make([]Filter, 0, len(f.filters)+len(filters))
+		all = append(all, f.filters...)
+		return newCompound(typ, append(all, filters...))
+	}
+
+	return newCompound(typ, append([]Filter{f}, filters...))
+}
+
+func newCompound(typ compoundType, filters []Filter) Filter {
+	f := Filter{
+		compound: typ,
+		filters:  filters,
+		depth:    1,
+	}
+
+	if len(filters) == 0 {
+		f.err = fmt.Errorf("filter: %q filter must have at least one filter", typ)
+		return f
+	}
+
+	for _, child := range filters {
+		if child.err != nil && f.err == nil {
+			f.err = child.err
+		}
+		if child.depth+1 > f.depth {
+			f.depth = child.depth + 1
+		}
+	}
+
+	if f.err == nil && f.depth > MaxNestingDepth {
+		f.err = fmt.Errorf("filter: compound filters can be nested at most %v levels deep", MaxNestingDepth)
+	}
+
+	return f
+}
+
+// Err returns the first error that occurred while building the filter.
+func (f Filter) Err() error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.condition == nil && f.compound == "" {
+		return errors.New("filter: empty filter")
+	}
+	return nil
+}
+
+// Build returns the filter for use with notion.DatabaseQuery.
+func (f Filter) Build() (*notion.DatabaseQueryFilter, error) {
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+
+	built := f.build()
+	return &built, nil
+}
+
+// MustBuild is like Build, but panics on error. It simplifies initialization
+// of filters that are known to be valid.
+func (f Filter) MustBuild() *notion.DatabaseQueryFilter {
+	built, err := f.Build()
+	if err != nil {
+		panic(err)
+	}
+	return built
+}
+
+func (f Filter) build() notion.DatabaseQueryFilter {
+	if f.condition != nil {
+		return *f.condition
+	}
+
+	filters := make([]notion.DatabaseQueryFilter, len(f.filters))
+	for i, child := range f.filters {
+		filters[i] = child.build()
+	}
+
+	if f.compound == compoundAnd {
+		return notion.DatabaseQueryFilter{And: filters}
+	}
+	return notion.DatabaseQueryFilter{Or: filters}
+}
+
+// Validate checks that all properties used in the filter exist in the
+// database schema, and that the conditions match their property types.
+func (f Filter) Validate(props notion.DatabaseProperties) error {
+	if err := f.Err(); err != nil {
+		return err
+	}
+
+	if f.condition == nil {
+		for _, child := range f.filters {
+			if err := child.Validate(props); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Timestamp filters don't refer to a property.
+	if f.condition.Property == "" {
+		return nil
+	}
+
+	prop, ok := findProperty(props, f.condition.Property)
+	if !ok {
+		return fmt.Errorf("filter: unknown property %q", f.condition.Property)
+	}
+	if prop.Type != f.kind {
+		return fmt.Errorf("filter: property %q has type %q, but is filtered as %q", f.condition.Property, prop.Type, f.kind)
+	}
+
+	return nil
+}
+
+// findProperty finds a property by name or by ID, as both can be used in
+// filters.
+func findProperty(props notion.DatabaseProperties, nameOrID string) (notion.DatabaseProperty, bool) {
+	if prop, ok := props[nameOrID]; ok {
+		return prop, true
+	}
+	for _, prop := range props {
+		if prop.ID == nameOrID {
+			return prop, true
+		}
+	}
+	return notion.DatabaseProperty{}, false
+}
+
+func newCondition(property string, kind notion.DatabasePropertyType, pf notion.DatabaseQueryPropertyFilter) Filter {
+	f := Filter{
+		condition: &notion.DatabaseQueryFilter{
+			Property:                    property,
+			DatabaseQueryPropertyFilter: pf,
+		},
+		kind: kind,
+	}
+	if property == "" {
+		f.err = errors.New("filter: property name is required")
+	}
+	return f
+}
+
+// CreatedTime returns conditions for the `created_time` timestamp of pages.
+func CreatedTime() DateCondition {
+	return DateCondition{build: func(df notion.DatePropertyFilter) Filter {
+		return Filter{condition: &notion.DatabaseQueryFilter{
+			Timestamp:                   notion.TimestampCreatedTime,
+			DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{CreatedTime: &df},
+		}}
+	}}
+}
+
+// LastEditedTime returns conditions for the `last_edited_time` timestamp of
+// pages.
+func LastEditedTime() DateCondition {
+	return DateCondition{build: func(df notion.DatePropertyFilter) Filter {
+		return Filter{condition: &notion.DatabaseQueryFilter{
+			Timestamp:                   notion.TimestampLastEditedTime,
+			DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{LastEditedTime: &df},
+		}}
+	}}
+}
@@ -0,0 +1,174 @@
+package filter_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/filter"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2021, time.May, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filter  filter.Filter
+		expJSON string
+		expErr  string
+	}{
+		{
+			name:    "single condition",
+			filter:  filter.Prop("Name").Title().Contains("foo"),
+			expJSON: `{"property":"Name","title":{"contains":"foo"}}`,
+		},
+		{
+			name:    "and",
+			filter:  filter.Prop("Status").Status().Equals("Done").And(filter.Prop("Due").Date().PastWeek()),
+			expJSON: `{"and":[{"property":"Status","status":{"equals":"Done"}},{"property":"Due","date":{"past_week":{}}}]}`,
+		},
+		{
+			name: "chained and is flattened",
+			filter: filter.Prop("A").Checkbox().Equals(true).
+				And(filter.Prop("B").Number().GreaterThan(1)).
+				And(filter.Prop("C").MultiSelect().Contains("x")),
+			expJSON: `{"and":[` +
+				`{"property":"A","checkbox":{"equals":true}},` +
+				`{"property":"B","number":{"greater_than":1}},` +
+				`{"property":"C","multi_select":{"contains":"x"}}]}`,
+		},
+		{
+			name: "nested compound",
+			filter: filter.Or(
+				filter.Prop("Tags").MultiSelect().IsEmpty(),
+				filter.And(
+					filter.Prop("Score").Formula().Number().LessThan(5),
+					filter.CreatedTime().After(date),
+				),
+			),
+			expJSON: `{"or":[` +
+				`{"property":"Tags","multi_select":{"is_empty":true}},` +
+				`{"and":[{"property":"Score","formula":{"number":{"less_than":5}}},` +
+				`{"created_time":{"after":"2021-05-10T00:00:00Z"},"timestamp":"created_time"}]}]}`,
+		},
+		{
+			name:    "rollup",
+			filter:  filter.Prop("Tasks").Rollup().Any().RichText().Contains("bug"),
+			expJSON: `{"property":"Tasks","rollup":{"any":{"rich_text":{"contains":"bug"}}}}`,
+		},
+		{
+			name: "nesting too deep",
+			filter: filter.Or(
+				filter.And(
+					filter.Or(filter.Prop("A").Checkbox().Equals(true)),
+				),
+			),
+			expErr: "filter: compound filters can be nested at most 2 levels deep",
+		},
+		{
+			name:   "empty compound",
+			filter: filter.And(),
+			expErr: `filter: "and" filter must have at least one filter`,
+		},
+		{
+			name:   "missing property name",
+			filter: filter.Prop("").Select().Equals("foo"),
+			expErr: "filter: property name is required",
+		},
+		{
+			name:   "empty filter",
+			filter: filter.Filter{},
+			expErr: "filter: empty filter",
+		},
+		{
+			name:   "empty text value",
+			filter: filter.Prop("Name").Title().Equals(""),
+			expErr: `filter: "equals" condition requires a value, use IsEmpty or IsNotEmpty to match empty properties`,
+		},
+		{
+			name:   "empty select value",
+			filter: filter.Prop("Status").Status().DoesNotEqual(""),
+			expErr: `filter: "does_not_equal" condition requires a value, use IsEmpty or IsNotEmpty to match empty properties`,
+		},
+		{
+			name:   "empty contains value in compound filter",
+			filter: filter.Prop("Done").Checkbox().Equals(true).And(filter.Prop("Tags").MultiSelect().Contains("")),
+			expErr: `filter: "contains" condition requires a value, use IsEmpty or IsNotEmpty to match empty properties`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := tt.filter.Build()
+			if tt.expErr != "" {
+				if err == nil || err.Error() != tt.expErr {
+					t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			b, err := json.Marshal(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.expJSON, string(b)); diff != "" {
+				t.Fatalf("filter not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	props := notion.DatabaseProperties{
+		"Name":   {ID: "title", Type: notion.DBPropTypeTitle},
+		"Status": {ID: "abc", Type: notion.DBPropTypeStatus},
+	}
+
+	tests := []struct {
+		name   string
+		filter filter.Filter
+		expErr string
+	}{
+		{
+			name:   "valid",
+			filter: filter.Prop("Name").Title().IsNotEmpty().And(filter.Prop("abc").Status().Equals("Done")),
+		},
+		{
+			name:   "unknown property",
+			filter: filter.Prop("Foo").Title().IsNotEmpty(),
+			expErr: `filter: unknown property "Foo"`,
+		},
+		{
+			name:   "kind mismatch",
+			filter: filter.And(filter.Prop("Status").Select().Equals("Done")),
+			expErr: `filter: property "Status" has type "status", but is filtered as "select"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.filter.Validate(props)
+			if tt.expErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expErr != "" && (err == nil || err.Error() != tt.expErr) {
+				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,534 @@
+package filter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// Property is a database property to filter on, by name or ID. Use one of its
+// methods to select the property type, and with it the available conditions.
+type Property struct {
+	name string
+}
+
+// Prop returns a property to filter on, by name or ID.
+func Prop(name string) Property {
+	return Property{name: name}
+}
+
+func (p Property) condition(kind notion.DatabasePropertyType, pf notion.DatabaseQueryPropertyFilter) Filter {
+	return newCondition(p.name, kind, pf)
+}
+
+// Title returns conditions for a `title` property.
+func (p Property) Title() TextCondition {
+	return TextCondition{build: func(tf notion.TextPropertyFilter) Filter {
+		return p.condition(notion.DBPropTypeTitle, notion.DatabaseQueryPropertyFilter{Title: &tf})
+	}}
+}
+
+// RichText returns conditions for a `rich_text` property.
+func (p Property) RichText() TextCondition {
+	return TextCondition{build: func(tf notion.TextPropertyFilter) Filter {
+		return p.condition(notion.DBPropTypeRichText, notion.DatabaseQueryPropertyFilter{RichText: &tf})
+	}}
+}
+
+// URL returns conditions for a `url` property.
+func (p Property) URL() TextCondition {
+	return TextCondition{build: func(tf notion.TextPropertyFilter) Filter {
+		return p.condition(notion.DBPropTypeURL, notion.DatabaseQueryPropertyFilter{URL: &tf})
+	}}
+}
+
+// Email returns conditions for an `email` property.
+func (p Property) Email() TextCondition {
+	return TextCondition{build: func(tf notion.TextPropertyFilter) Filter {
+		return p.condition(notion.DBPropTypeEmail, notion.DatabaseQueryPropertyFilter{Email: &tf})
+	}}
+}
+
+// PhoneNumber returns conditions for a `phone_number` property.
+func (p Property) PhoneNumber() TextCondition {
+	return TextCondition{build: func(tf notion.TextPropertyFilter) Filter {
+		return p.condition(notion.DBPropTypePhoneNumber, notion.DatabaseQueryPropertyFilter{PhoneNumber: &tf})
+	}}
+}
+
+// Number returns conditions for a `number` property.
+func (p Property) Number() NumberCondition {
+	return NumberCondition{build: func(nf notion.NumberDatabaseQueryFilter) Filter {
+		return p.condition(notion.DBPropTypeNumber, notion.DatabaseQueryPropertyFilter{Number: &nf})
+	}}
+}
+
+// Checkbox returns conditions for a `checkbox` property.
+func (p Property) Checkbox() CheckboxCondition {
+	return CheckboxCondition{build: func(cf notion.CheckboxDatabaseQueryFilter) Filter {
+		return p.condition(notion.DBPropTypeCheckbox, notion.DatabaseQueryPropertyFilter{Checkbox: &cf})
+	}}
+}
+
+// Select returns conditions for a `select` property.
+func (p Property) Select() SelectCondition {
+	return SelectCondition{build: func(sf notion.SelectDatabaseQueryFilter) Filter {
+		return p.condition(notion.DBPropTypeSelect, notion.DatabaseQueryPropertyFilter{Select: &sf})
+	}}
+}
+
+// Status returns conditions for a `status` property.
+func (p Property) Status() SelectCondition {
+	return SelectCondition{build: func(sf notion.SelectDatabaseQueryFilter) Filter {
+		status := notion.StatusDatabaseQueryFilter(sf)
+		return p.condition(notion.DBPropTypeStatus, notion.DatabaseQueryPropertyFilter{Status: &status})
+	}}
+}
+
+// MultiSelect returns conditions for a `multi_select` property.
+func (p Property) MultiSelect() ContainsCondition {
+	return ContainsCondition{build: func(cf containsFilter) Filter {
+		mf := notion.MultiSelectDatabaseQueryFilter(cf)
+		return p.condition(notion.DBPropTypeMultiSelect, notion.DatabaseQueryPropertyFilter{MultiSelect: &mf})
+	}}
+}
+
+// People returns conditions for a `people` property.
+func (p Property) People() ContainsCondition {
+	return ContainsCondition{build: func(cf containsFilter) Filter {
+		pf := notion.PeopleDatabaseQueryFilter(cf)
+		return p.condition(notion.DBPropTypePeople, notion.DatabaseQueryPropertyFilter{People: &pf})
+	}}
+}
+
+// CreatedBy returns conditions for a `created_by` property.
+func (p Property) CreatedBy() ContainsCondition {
+	return ContainsCondition{build: func(cf containsFilter) Filter {
+		pf := notion.PeopleDatabaseQueryFilter(cf)
+		return p.condition(notion.DBPropTypeCreatedBy, notion.DatabaseQueryPropertyFilter{CreatedBy: &pf})
+	}}
+}
+
+// LastEditedBy returns conditions for a `last_edited_by` property.
+func (p Property) LastEditedBy() ContainsCondition {
+	return ContainsCondition{build: func(cf containsFilter) Filter {
+		pf := notion.PeopleDatabaseQueryFilter(cf)
+		return p.condition(notion.DBPropTypeLastEditedBy, notion.DatabaseQueryPropertyFilter{LastEditedBy: &pf})
+	}}
+}
+
+// Relation returns conditions for a `relation` property.
+func (p Property) Relation() ContainsCondition {
+	return ContainsCondition{build: func(cf containsFilter) Filter {
+		rf := notion.RelationDatabaseQueryFilter(cf)
+		return p.condition(notion.DBPropTypeRelation, notion.DatabaseQueryPropertyFilter{Relation: &rf})
+	}}
+}
+
+// Files returns conditions for a `files` property.
+func (p Property) Files() FilesCondition {
+	return FilesCondition{build: func(ff notion.FilesDatabaseQueryFilter) Filter {
+		return p.condition(notion.DBPropTypeFiles, notion.DatabaseQueryPropertyFilter{Files: &ff})
+	}}
+}
+
+// Date returns conditions for a `date` property.
+func (p Property) Date() DateCondition {
+	return DateCondition{build: func(df notion.DatePropertyFilter) Filter {
+		return p.condition(notion.DBPropTypeDate, notion.DatabaseQueryPropertyFilter{Date: &df})
+	}}
+}
+
+// CreatedTime returns conditions for a `created_time` property. To filter on
+// the creation time of pages regardless of database properties, use the
+// CreatedTime function instead.
+func (p Property) CreatedTime() DateCondition {
+	return DateCondition{build: func(df notion.DatePropertyFilter) Filter {
+		return p.condition(notion.DBPropTypeCreatedTime, notion.DatabaseQueryPropertyFilter{CreatedTime: &df})
+	}}
+}
+
+// LastEditedTime returns conditions for a `last_edited_time` property. To
+// filter on the last edited time of pages regardless of database properties,
+// use the LastEditedTime function instead.
+func (p Property) LastEditedTime() DateCondition {
+	return DateCondition{build: func(df notion.DatePropertyFilter) Filter {
+		return p.condition(notion.DBPropTypeLastEditedTime, notion.DatabaseQueryPropertyFilter{LastEditedTime: &df})
+	}}
+}
+
+// Formula returns conditions for a `formula` property, by result type.
+func (p Property) Formula() FormulaCondition {
+	return FormulaCondition{build: func(ff notion.FormulaDatabaseQueryFilter) Filter {
+		return p.condition(notion.DBPropTypeFormula, notion.DatabaseQueryPropertyFilter{Formula: &ff})
+	}}
+}
+
+// Rollup returns conditions for a `rollup` property.
+func (p Property) Rollup() RollupCondition {
+	return RollupCondition{build: func(rf notion.RollupDatabaseQueryFilter) Filter {
+		return p.condition(notion.DBPropTypeRollup, notion.DatabaseQueryPropertyFilter{Rollup: &rf})
+	}}
+}
+
+// requireValue sets an error on f if value is empty. The API omits empty
+// strings, which leaves a condition without a value; IsEmpty and IsNotEmpty
+// are used to match (non-)empty properties instead.
+func requireValue(f Filter, operator, value string) Filter {
+	if value == "" && f.err == nil {
+		f.err = fmt.Errorf("filter: %q condition requires a value, use IsEmpty or IsNotEmpty to match empty properties", operator)
+	}
+	return f
+}
+
+// TextCondition has conditions for text-like properties: title, rich text,
+// URL, email and phone number.
+type TextCondition struct {
+	build func(notion.TextPropertyFilter) Filter
+}
+
+func (c TextCondition) Equals(value string) Filter {
+	return requireValue(c.build(notion.TextPropertyFilter{Equals: value}), "equals", value)
+}
+
+func (c TextCondition) DoesNotEqual(value string) Filter {
+	return requireValue(c.build(notion.TextPropertyFilter{DoesNotEqual: value}), "does_not_equal", value)
+}
+
+func (c TextCondition) Contains(value string) Filter {
+	return requireValue(c.build(notion.TextPropertyFilter{Contains: value}), "contains", value)
+}
+
+func (c TextCondition) DoesNotContain(value string) Filter {
+	return requireValue(c.build(notion.TextPropertyFilter{DoesNotContain: value}), "does_not_contain", value)
+}
+
+func (c TextCondition) StartsWith(value string) Filter {
+	return requireValue(c.build(notion.TextPropertyFilter{StartsWith: value}), "starts_with", value)
+}
+
+func (c TextCondition) EndsWith(value string) Filter {
+	return requireValue(c.build(notion.TextPropertyFilter{EndsWith: value}), "ends_with", value)
+}
+
+func (c TextCondition) IsEmpty() Filter {
+	return c.build(notion.TextPropertyFilter{IsEmpty: true})
+}
+
+func (c TextCondition) IsNotEmpty() Filter {
+	return c.build(notion.TextPropertyFilter{IsNotEmpty: true})
+}
+
+// NumberCondition has conditions for number properties.
+type NumberCondition struct {
+	build func(notion.NumberDatabaseQueryFilter) Filter
+}
+
+func (c NumberCondition) Equals(value int) Filter {
+	return c.build(notion.NumberDatabaseQueryFilter{Equals: &value})
+}
+
+func (c NumberCondition) DoesNotEqual(value int) Filter {
+	return c.build(notion.NumberDatabaseQueryFilter{DoesNotEqual: &value})
+}
+
+func (c NumberCondition) GreaterThan(value int) Filter {
+	return c.build(notion.NumberDatabaseQueryFilter{GreaterThan: &value})
+}
+
+func (c NumberCondition) LessThan(value int) Filter {
+	return c.build(notion.NumberDatabaseQueryFilter{LessThan: &value})
+}
+
+func (c NumberCondition) GreaterThanOrEqualTo(value int) Filter {
+	return c.build(notion.NumberDatabaseQueryFilter{GreaterThanOrEqualTo: &value})
+}
+
+func (c NumberCondition) LessThanOrEqualTo(value int) Filter {
+	return c.build(notion.NumberDatabaseQueryFilter{LessThanOrEqualTo: &value})
+}
+
+func (c NumberCondition) IsEmpty() Filter {
+	return c.build(notion.NumberDatabaseQueryFilter{IsEmpty: true})
+}
+
+func (c NumberCondition) IsNotEmpty() Filter {
+	return c.build(notion.NumberDatabaseQueryFilter{IsNotEmpty: true})
+}
+
+// CheckboxCondition has conditions for checkbox properties.
+type CheckboxCondition struct {
+	build func(notion.CheckboxDatabaseQueryFilter) Filter
+}
+
+func (c CheckboxCondition) Equals(value bool) Filter {
+	return c.build(notion.CheckboxDatabaseQueryFilter{Equals: &value})
+}
+
+func (c CheckboxCondition) DoesNotEqual(value bool) Filter {
+	return c.build(notion.CheckboxDatabaseQueryFilter{DoesNotEqual: &value})
+}
+
+// SelectCondition has conditions for select and status properties.
+type SelectCondition struct {
+	build func(notion.SelectDatabaseQueryFilter) Filter
+}
+
+func (c SelectCondition) Equals(option string) Filter {
+	return requireValue(c.build(notion.SelectDatabaseQueryFilter{Equals: option}), "equals", option)
+}
+
+func (c SelectCondition) DoesNotEqual(option string) Filter {
+	return requireValue(c.build(notion.SelectDatabaseQueryFilter{DoesNotEqual: option}), "does_not_equal", option)
+}
+
+func (c SelectCondition) IsEmpty() Filter {
+	return c.build(notion.SelectDatabaseQueryFilter{IsEmpty: true})
+}
+
+func (c SelectCondition) IsNotEmpty() Filter {
+	return c.build(notion.SelectDatabaseQueryFilter{IsNotEmpty: true})
+}
+
+// containsFilter has the fields shared by multi-select, people and relation
+// filters.
+type containsFilter struct {
+	Contains       string
+	DoesNotContain string
+	IsEmpty        bool
+	IsNotEmpty     bool
+}
+
+// ContainsCondition has conditions for multi-select, people and relation
+// properties.
+type ContainsCondition struct {
+	build func(containsFilter) Filter
+}
+
+// Contains matches when the property contains value: a multi-select option
+// name, user ID or related page ID.
+func (c ContainsCondition) Contains(value string) Filter {
+	return requireValue(c.build(containsFilter{Contains: value}), "contains", value)
+}
+
+// DoesNotContain matches when the property doesn't contain value: a
+// multi-select option name, user ID or related page ID.
+func (c ContainsCondition) DoesNotContain(value string) Filter {
+	return requireValue(c.build(containsFilter{DoesNotContain: value}), "does_not_contain", value)
+}
+
+func (c ContainsCondition) IsEmpty() Filter {
+	return c.build(containsFilter{IsEmpty: true})
+}
+
+func (c ContainsCondition) IsNotEmpty() Filter {
+	return c.build(containsFilter{IsNotEmpty: true})
+}
+
+// FilesCondition has conditions for files properties.
+type FilesCondition struct {
+	build func(notion.FilesDatabaseQueryFilter) Filter
+}
+
+func (c FilesCondition) IsEmpty() Filter {
+	return c.build(notion.FilesDatabaseQueryFilter{IsEmpty: true})
+}
+
+func (c FilesCondition) IsNotEmpty() Filter {
+	return c.build(notion.FilesDatabaseQueryFilter{IsNotEmpty: true})
+}
+
+// DateCondition has conditions for date, created time and last edited time
+// properties and timestamps.
+type DateCondition struct {
+	build func(notion.DatePropertyFilter) Filter
+}
+
+func (c DateCondition) Equals(t time.Time) Filter {
+	return c.build(notion.DatePropertyFilter{Equals: &t})
+}
+
+func (c DateCondition) Before(t time.Time) Filter {
+	return c.build(notion.DatePropertyFilter{Before: &t})
+}
+
+func (c DateCondition) After(t time.Time) Filter {
+	return c.build(notion.DatePropertyFilter{After: &t})
+}
+
+func (c DateCondition) OnOrBefore(t time.Time) Filter {
+	return c.build(notion.DatePropertyFilter{OnOrBefore: &t})
+}
+
+func (c DateCondition) OnOrAfter(t time.Time) Filter {
+	return c.build(notion.DatePropertyFilter{OnOrAfter: &t})
+}
+
+func (c DateCondition) IsEmpty() Filter {
+	return c.build(notion.DatePropertyFilter{IsEmpty: true})
+}
+
+func (c DateCondition) IsNotEmpty() Filter {
+	return c.build(notion.DatePropertyFilter{IsNotEmpty: true})
+}
+
+func (c DateCondition) PastWeek() Filter {
+	return c.build(notion.DatePropertyFilter{PastWeek: &struct{}{}})
+}
+
+func (c DateCondition) PastMonth() Filter {
+	return c.build(notion.DatePropertyFilter{PastMonth: &struct{}{}})
+}
+
+func (c DateCondition) PastYear() Filter {
+	return c.build(notion.DatePropertyFilter{PastYear: &struct{}{}})
+}
+
+func (c DateCondition) NextWeek() Filter {
+	return c.build(notion.DatePropertyFilter{NextWeek: &struct{}{}})
+}
+
+func (c DateCondition) NextMonth() Filter {
+	return c.build(notion.DatePropertyFilter{NextMonth: &struct{}{}})
+}
+
+func (c DateCondition) NextYear() Filter {
+	return c.build(notion.DatePropertyFilter{NextYear: &struct{}{}})
+}
+
+// FormulaCondition has conditions for formula properties, by result type.
+type FormulaCondition struct {
+	build func(notion.FormulaDatabaseQueryFilter) Filter
+}
+
+// String returns conditions for formulas with a string result.
+func (c FormulaCondition) String() TextCondition {
+	return TextCondition{build: func(tf notion.TextPropertyFilter) Filter {
+		return c.build(notion.FormulaDatabaseQueryFilter{String: &tf})
+	}}
+}
+
+// Checkbox returns conditions for formulas with a boolean result.
+func (c FormulaCondition) Checkbox() CheckboxCondition {
+	return CheckboxCondition{build: func(cf notion.CheckboxDatabaseQueryFilter) Filter {
+		return c.build(notion.FormulaDatabaseQueryFilter{Checkbox: &cf})
+	}}
+}
+
+// Number returns conditions for formulas with a number result.
+func (c FormulaCondition) Number() NumberCondition {
+	return NumberCondition{build: func(nf notion.NumberDatabaseQueryFilter) Filter {
+		return c.build(notion.FormulaDatabaseQueryFilter{Number: &nf})
+	}}
+}
+
+// Date returns conditions for formulas with a date result.
+func (c FormulaCondition) Date() DateCondition {
+	return DateCondition{build: func(df notion.DatePropertyFilter) Filter {
+		return c.build(notion.FormulaDatabaseQueryFilter{Date: &df})
+	}}
+}
+
+// RollupCondition has conditions for rollup properties.
+type RollupCondition struct {
+	build func(notion.RollupDatabaseQueryFilter) Filter
+}
+
+// Any returns conditions that match when any rolled up value matches.
+func (c RollupCondition) Any() RollupItemCondition {
+	return RollupItemCondition{build: func(pf notion.DatabaseQueryPropertyFilter) Filter {
+		return c.build(notion.RollupDatabaseQueryFilter{Any: &pf})
+	}}
+}
+
+// Every returns conditions that match when every rolled up value matches.
+func (c RollupCondition) Every() RollupItemCondition {
+	return RollupItemCondition{build: func(pf notion.DatabaseQueryPropertyFilter) Filter {
+		return c.build(notion.RollupDatabaseQueryFilter{Every: &pf})
+	}}
+}
+
+// None returns conditions that match when no rolled up value matches.
+func (c RollupCondition) None() RollupItemCondition {
+	return RollupItemCondition{build: func(pf notion.DatabaseQueryPropertyFilter) Filter {
+		return c.build(notion.RollupDatabaseQueryFilter{None: &pf})
+	}}
+}
+
+// Number returns conditions for rollups with a number result.
+func (c RollupCondition) Number() NumberCondition {
+	return NumberCondition{build: func(nf notion.NumberDatabaseQueryFilter) Filter {
+		return c.build(notion.RollupDatabaseQueryFilter{Number: &nf})
+	}}
+}
+
+// Date returns conditions for rollups with a date result.
+func (c RollupCondition) Date() DateCondition {
+	return DateCondition{build: func(df notion.DatePropertyFilter) Filter {
+		return c.build(notion.RollupDatabaseQueryFilter{Date: &df})
+	}}
+}
+
+// RollupItemCondition has conditions for the values of rollups with an array
+// result, by the type of the rolled up property.
+type RollupItemCondition struct {
+	build func(notion.DatabaseQueryPropertyFilter) Filter
+}
+
+func (c RollupItemCondition) RichText() TextCondition {
+	return TextCondition{build: func(tf notion.TextPropertyFilter) Filter {
+		return c.build(notion.DatabaseQueryPropertyFilter{RichText: &tf})
+	}}
+}
+
+func (c RollupItemCondition) Number() NumberCondition {
+	return NumberCondition{build: func(nf notion.NumberDatabaseQueryFilter) Filter {
+		return c.build(notion.DatabaseQueryPropertyFilter{Number: &nf})
+	}}
+}
+
+func (c RollupItemCondition) Checkbox() CheckboxCondition {
+	return CheckboxCondition{build: func(cf notion.CheckboxDatabaseQueryFilter) Filter {
+		return c.build(notion.DatabaseQueryPropertyFilter{Checkbox: &cf})
+	}}
+}
+
+func (c RollupItemCondition) Select() SelectCondition {
+	return SelectCondition{build: func(sf notion.SelectDatabaseQueryFilter) Filter {
+		return c.build(notion.DatabaseQueryPropertyFilter{Select: &sf})
+	}}
+}
+
+func (c RollupItemCondition) MultiSelect() ContainsCondition {
+	return ContainsCondition{build: func(cf containsFilter) Filter {
+		mf := notion.MultiSelectDatabaseQueryFilter(cf)
+		return c.build(notion.DatabaseQueryPropertyFilter{MultiSelect: &mf})
+	}}
+}
+
+func (c RollupItemCondition) Relation() ContainsCondition {
+	return ContainsCondition{build: func(cf containsFilter) Filter {
+		rf := notion.RelationDatabaseQueryFilter(cf)
+		return c.build(notion.DatabaseQueryPropertyFilter{Relation: &rf})
+	}}
+}
+
+func (c RollupItemCondition) Date() DateCondition {
+	return DateCondition{build: func(df notion.DatePropertyFilter) Filter {
+		return c.build(notion.DatabaseQueryPropertyFilter{Date: &df})
+	}}
+}
+
+func (c RollupItemCondition) People() ContainsCondition {
+	return ContainsCondition{build: func(cf containsFilter) Filter {
+		pf := notion.PeopleDatabaseQueryFilter(cf)
+		return c.build(notion.DatabaseQueryPropertyFilter{People: &pf})
+	}}
+}
+
+func (c RollupItemCondition) Files() FilesCondition {
+	return FilesCondition{build: func(ff notion.FilesDatabaseQueryFilter) Filter {
+		return c.build(notion.DatabaseQueryPropertyFilter{Files: &ff})
+	}}
+}
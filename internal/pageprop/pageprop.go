@@ -0,0 +1,30 @@
+// Package pageprop shares helpers for database page properties between package
+// notion and its subpackages, without adding them to the API of package
+// notion.
+//
+// Package notion can't be imported here, as it imports this package. Helpers
+// that need its types are implemented by package notion, which sets them when
+// it's initialized.
+package pageprop
+
+// IsKeyType reports whether properties of a type can be used as key that
+// identifies database pages: title, rich text, number, URL, email and phone
+// number properties.
+func IsKeyType(propType string) bool {
+	switch propType {
+	case "title", "rich_text", "number", "url", "email", "phone_number":
+		return true
+	}
+	return false
+}
+
+var (
+	// ValuesEqual reports whether the current value of a page property, as
+	// returned by the API, equals a desired value. Both are
+	// notion.DatabasePageProperty values.
+	ValuesEqual func(current, desired interface{}) bool
+
+	// KeyProperty returns the notion.DatabasePageProperty value of a key
+	// property for new pages, given the key as string.
+	KeyProperty func(propType, key string) (interface{}, error)
+)
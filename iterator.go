@@ -0,0 +1,248 @@
+package notion
+
+import "context"
+
+// Iterator iterates over the results of a paginated list endpoint, following
+// `next_cursor` until all results have been fetched.
+//
+//	iter := client.QueryDatabaseIter(databaseID, nil)
+//	for iter.Next(ctx) {
+//		page := iter.Value()
+//		// ...
+//	}
+//	if err := iter.Err(); err != nil {
+//		// Handle error...
+//	}
+type Iterator[T any] struct {
+	fetch func(ctx context.Context, cursor string) (results []T, nextCursor string, err error)
+
+	results []T
+	idx     int
+	cursor  string
+	done    bool
+	value   T
+	err     error
+}
+
+func newIterator[T any](
+	startCursor string,
+	fetch func(ctx context.Context, cursor string) ([]T, string, error),
+) *Iterator[T] {
+	return &Iterator[T]{
+		fetch:  fetch,
+		cursor: startCursor,
+	}
+}
+
+// Next advances the iterator to the next result, fetching the next page of
+// results when needed. It returns false when there are no more results, or
+// when an error occurred (see Err).
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	for it.idx >= len(it.results) {
+		if it.done {
+			return false
+		}
+
+		results, nextCursor, err := it.fetch(ctx, it.cursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.results = results
+		it.idx = 0
+		it.cursor = nextCursor
+		it.done = nextCursor == ""
+	}
+
+	it.value = it.results[it.idx]
+	it.idx++
+
+	return true
+}
+
+// Value returns the current result. It should only be called after a call to
+// Next returned true.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the first error encountered while iterating, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All collects the remaining results of the iterator.
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+
+	for it.Next(ctx) {
+		all = append(all, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
+
+func cursorValue(cursor *string) string {
+	if cursor == nil {
+		return ""
+	}
+	return *cursor
+}
+
+// QueryDatabaseIter returns an iterator over all pages matching the database
+// query. The query's StartCursor and PageSize are respected.
+func (c *Client) QueryDatabaseIter(id string, query *DatabaseQuery) *Iterator[Page] {
+	var q DatabaseQuery
+	if query != nil {
+		q = *query
+	}
+
+	return newIterator(q.StartCursor, func(ctx context.Context, cursor string) ([]Page, string, error) {
+		q.StartCursor = cursor
+		resp, err := c.QueryDatabase(ctx, id, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Results, nextCursor(resp.HasMore, cursorValue(resp.NextCursor)), nil
+	})
+}
+
+// QueryDatabaseAll returns all pages matching the database query, following
+// pagination cursors.
+func (c *Client) QueryDatabaseAll(ctx context.Context, id string, query *DatabaseQuery) ([]Page, error) {
+	return c.QueryDatabaseIter(id, query).All(ctx)
+}
+
+// FindBlockChildrenByIDIter returns an iterator over all children of a block.
+func (c *Client) FindBlockChildrenByIDIter(blockID string, query *PaginationQuery) *Iterator[Block] {
+	var q PaginationQuery
+	if query != nil {
+		q = *query
+	}
+
+	return newIterator(q.StartCursor, func(ctx context.Context, cursor string) ([]Block, string, error) {
+		q.StartCursor = cursor
+		resp, err := c.FindBlockChildrenByID(ctx, blockID, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Results, nextCursor(resp.HasMore, cursorValue(resp.NextCursor)), nil
+	})
+}
+
+// FindBlockChildrenByIDAll returns all children of a block, following
+// pagination cursors.
+func (c *Client) FindBlockChildrenByIDAll(ctx context.Context, blockID string, query *PaginationQuery) ([]Block, error) {
+	return c.FindBlockChildrenByIDIter(blockID, query).All(ctx)
+}
+
+// ListUsersIter returns an iterator over all users in the workspace.
+func (c *Client) ListUsersIter(query *PaginationQuery) *Iterator[User] {
+	var q PaginationQuery
+	if query != nil {
+		q = *query
+	}
+
+	return newIterator(q.StartCursor, func(ctx context.Context, cursor string) ([]User, string, error) {
+		q.StartCursor = cursor
+		resp, err := c.ListUsers(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Results, nextCursor(resp.HasMore, cursorValue(resp.NextCursor)), nil
+	})
+}
+
+// ListUsersAll returns all users in the workspace, following pagination cursors.
+func (c *Client) ListUsersAll(ctx context.Context, query *PaginationQuery) ([]User, error) {
+	return c.ListUsersIter(query).All(ctx)
+}
+
+// SearchIter returns an iterator over all search results. Results are either
+// a Page or a Database.
+func (c *Client) SearchIter(opts *SearchOpts) *Iterator[interface{}] {
+	var o SearchOpts
+	if opts != nil {
+		o = *opts
+	}
+
+	return newIterator(o.StartCursor, func(ctx context.Context, cursor string) ([]interface{}, string, error) {
+		o.StartCursor = cursor
+		resp, err := c.Search(ctx, &o)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Results, nextCursor(resp.HasMore, cursorValue(resp.NextCursor)), nil
+	})
+}
+
+// SearchAll returns all search results, following pagination cursors.
+func (c *Client) SearchAll(ctx context.Context, opts *SearchOpts) ([]interface{}, error) {
+	return c.SearchIter(opts).All(ctx)
+}
+
+// FindCommentsByBlockIDIter returns an iterator over all unresolved comments
+// of a block.
+func (c *Client) FindCommentsByBlockIDIter(query FindCommentsByBlockIDQuery) *Iterator[Comment] {
+	return newIterator(query.StartCursor, func(ctx context.Context, cursor string) ([]Comment, string, error) {
+		query.StartCursor = cursor
+		resp, err := c.FindCommentsByBlockID(ctx, query)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Results, nextCursor(resp.HasMore, cursorValue(resp.NextCursor)), nil
+	})
+}
+
+// FindCommentsByBlockIDAll returns all unresolved comments of a block,
+// following pagination cursors.
+func (c *Client) FindCommentsByBlockIDAll(ctx context.Context, query FindCommentsByBlockIDQuery) ([]Comment, error) {
+	return c.FindCommentsByBlockIDIter(query).All(ctx)
+}
+
+// FindPagePropertyByIDIter returns an iterator over all items of a paginated
+// page property, e.g. a `title`, `rich_text`, `relation` or `people` property.
+func (c *Client) FindPagePropertyByIDIter(pageID, propID string, query *PaginationQuery) *Iterator[PagePropItem] {
+	var q PaginationQuery
+	if query != nil {
+		q = *query
+	}
+
+	return newIterator(q.StartCursor, func(ctx context.Context, cursor string) ([]PagePropItem, string, error) {
+		q.StartCursor = cursor
+		resp, err := c.FindPagePropertyByID(ctx, pageID, propID, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		// Non-paginated properties are returned as a single property item.
+		if resp.Type != DBPropTypePropertyItem {
+			return []PagePropItem{resp.PagePropItem}, "", nil
+		}
+		return resp.Results, nextCursor(resp.HasMore, resp.NextCursor), nil
+	})
+}
+
+// FindPagePropertyByIDAll returns all items of a paginated page property,
+// following pagination cursors.
+func (c *Client) FindPagePropertyByIDAll(ctx context.Context, pageID, propID string, query *PaginationQuery) ([]PagePropItem, error) {
+	return c.FindPagePropertyByIDIter(pageID, propID, query).All(ctx)
+}
+
+func nextCursor(hasMore bool, cursor string) string {
+	if !hasMore {
+		return ""
+	}
+	return cursor
+}
@@ -0,0 +1,191 @@
+package notion_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestQueryDatabaseIter(t *testing.T) {
+	t.Parallel()
+
+	var queries []notion.DatabaseQuery
+
+	httpClient := &http.Client{
+		Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+			var query notion.DatabaseQuery
+			if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+				t.Fatal(err)
+			}
+			queries = append(queries, query)
+
+			var body string
+			switch query.StartCursor {
+			case "":
+				body = `{
+					"object": "list",
+					"results": [
+						{"object": "page", "id": "page-1", "parent": {"type": "database_id", "database_id": "db"}, "properties": {}},
+						{"object": "page", "id": "page-2", "parent": {"type": "database_id", "database_id": "db"}, "properties": {}}
+					],
+					"next_cursor": "cursor-1",
+					"has_more": true
+				}`
+			case "cursor-1":
+				body = `{
+					"object": "list",
+					"results": [
+						{"object": "page", "id": "page-3", "parent": {"type": "database_id", "database_id": "db"}, "properties": {}}
+					],
+					"next_cursor": null,
+					"has_more": false
+				}`
+			default:
+				t.Fatalf("unexpected start cursor: %q", query.StartCursor)
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Status:     http.StatusText(http.StatusOK),
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+			}, nil
+		}},
+	}
+	client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+
+	pages, err := client.QueryDatabaseAll(context.Background(), "db", &notion.DatabaseQuery{PageSize: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, page := range pages {
+		ids = append(ids, page.ID)
+	}
+
+	if diff := cmp.Diff([]string{"page-1", "page-2", "page-3"}, ids); diff != "" {
+		t.Fatalf("page IDs not equal (-exp, +got):\n%v", diff)
+	}
+
+	expQueries := []notion.DatabaseQuery{
+		{PageSize: 2},
+		{PageSize: 2, StartCursor: "cursor-1"},
+	}
+	if diff := cmp.Diff(expQueries, queries); diff != "" {
+		t.Fatalf("queries not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestListUsersIter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("follows cursors", func(t *testing.T) {
+		t.Parallel()
+
+		httpClient := &http.Client{
+			Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+				cursor := r.URL.Query().Get("start_cursor")
+				next := "null"
+				hasMore := false
+				if cursor == "" {
+					next = `"cursor-1"`
+					hasMore = true
+				}
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     http.StatusText(http.StatusOK),
+					Body: ioutil.NopCloser(strings.NewReader(fmt.Sprintf(`{
+						"object": "list",
+						"results": [{"object": "user", "id": "user-%v"}],
+						"next_cursor": %v,
+						"has_more": %v
+					}`, cursor, next, hasMore))),
+				}, nil
+			}},
+		}
+		client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+
+		var ids []string
+		iter := client.ListUsersIter(nil)
+		for iter.Next(context.Background()) {
+			ids = append(ids, iter.Value().ID)
+		}
+		if err := iter.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if diff := cmp.Diff([]string{"user-", "user-cursor-1"}, ids); diff != "" {
+			t.Fatalf("user IDs not equal (-exp, +got):\n%v", diff)
+		}
+	})
+
+	t.Run("stops on error response", func(t *testing.T) {
+		t.Parallel()
+
+		httpClient := &http.Client{
+			Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Status:     http.StatusText(http.StatusBadRequest),
+					Body: ioutil.NopCloser(strings.NewReader(`{
+						"object": "error",
+						"status": 400,
+						"code": "validation_error",
+						"message": "foobar"
+					}`)),
+				}, nil
+			}},
+		}
+		client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+
+		_, err := client.ListUsersAll(context.Background(), nil)
+		if !errors.Is(err, notion.ErrValidation) {
+			t.Fatalf("error not equal (expected: %v, got: %v)", notion.ErrValidation, err)
+		}
+	})
+
+	t.Run("stops on context cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		requests := 0
+		httpClient := &http.Client{
+			Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+				// Cancel while the first page is being fetched.
+				requests++
+				cancel()
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     http.StatusText(http.StatusOK),
+					Body: ioutil.NopCloser(strings.NewReader(`{
+						"object": "list",
+						"results": [{"object": "user", "id": "user-1"}, {"object": "user", "id": "user-2"}],
+						"next_cursor": "cursor-1",
+						"has_more": true
+					}`)),
+				}, nil
+			}},
+		}
+		client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+
+		iter := client.ListUsersIter(nil)
+		for iter.Next(ctx) {
+		}
+
+		if requests != 1 {
+			t.Fatalf("expected 1 request before cancellation, got %v", requests)
+		}
+		if !errors.Is(iter.Err(), context.Canceled) {
+			t.Fatalf("error not equal (expected: %v, got: %v)", context.Canceled, iter.Err())
+		}
+	})
+}
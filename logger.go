@@ -0,0 +1,272 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// LogLevel is the severity of a log entry. The values match the levels of
+// the `log/slog` package, so that a Logger can easily be backed by it.
+type LogLevel int
+
+// Log levels.
+const (
+	LogLevelDebug LogLevel = -4
+	LogLevelInfo  LogLevel = 0
+	LogLevelWarn  LogLevel = 4
+	LogLevelError LogLevel = 8
+)
+
+// String implements fmt.Stringer.
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// Logger is used by the client to log requests and responses. Args are
+// alternating keys and values, like the arguments of `(*slog.Logger).Log`, so
+// a *slog.Logger can be used with a small adapter:
+//
+//	type slogLogger struct{ *slog.Logger }
+//
+//	func (l slogLogger) Log(ctx context.Context, level notion.LogLevel, msg string, args ...interface{}) {
+//		l.Logger.Log(ctx, slog.Level(level), msg, args...)
+//	}
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, args ...interface{})
+}
+
+// LoggerOptions configures what the client logs. See DefaultLoggerOptions for
+// the defaults.
+type LoggerOptions struct {
+	// Level is the level of log entries for successful calls.
+	Level LogLevel
+
+	// ErrorLevel is the level of log entries for calls that failed, either
+	// with an error response or a transport error.
+	ErrorLevel LogLevel
+
+	// MaxBodySize is the maximum number of bytes of request and response
+	// bodies that are logged. Longer bodies are truncated. Bodies are omitted
+	// if it's zero or negative.
+	MaxBodySize int
+
+	// RedactPersonalData replaces the values of `email` and `phone_number`
+	// fields in logged bodies, e.g. email and phone number property values,
+	// and email addresses of people.
+	RedactPersonalData bool
+}
+
+// DefaultLoggerOptions returns the LoggerOptions used when WithLogger is
+// called with nil options.
+func DefaultLoggerOptions() LoggerOptions {
+	return LoggerOptions{
+		Level:       LogLevelDebug,
+		ErrorLevel:  LogLevelWarn,
+		MaxBodySize: 1024,
+	}
+}
+
+// redacted replaces secrets and (optionally) personal data in log entries.
+const redacted = "[REDACTED]"
+
+// Fields of request and response bodies that hold personal data.
+var personalDataFields = map[string]bool{
+	"email":        true,
+	"phone_number": true,
+}
+
+// WithLogger logs every request and response of the client: the operation,
+// method, path, status code, duration and (truncated) bodies. The integration
+// token is always redacted. It's added to the client's middleware chain, so its
+// position relative to middleware added with WithMiddleware follows the order
+// of the options.
+func WithLogger(logger Logger, opts *LoggerOptions) ClientOption {
+	o := DefaultLoggerOptions()
+	if opts != nil {
+		o = *opts
+	}
+
+	return func(c *Client) {
+		l := &requestLogger{logger: logger, opts: o}
+		c.middleware = append(c.middleware, l.middleware)
+	}
+}
+
+type requestLogger struct {
+	logger Logger
+	opts   LoggerOptions
+}
+
+func (l *requestLogger) middleware(next Doer) Doer {
+	return DoerFunc(func(req *Request) (*Response, error) {
+		httpReq := req.HTTPRequest
+		token := bearerToken(httpReq)
+		args := []interface{}{
+			"operation", req.Operation,
+			"method", httpReq.Method,
+			"path", redactToken(httpReq.URL.RequestURI(), token),
+		}
+		if req.ResourceID != "" {
+			args = append(args, "resource_id", req.ResourceID)
+		}
+		if l.opts.MaxBodySize > 0 && httpReq.GetBody != nil {
+			if body, err := httpReq.GetBody(); err == nil {
+				b, _ := io.ReadAll(body)
+				body.Close()
+				if len(b) > 0 {
+					args = append(args, "request_body", l.formatBody(b, token))
+				}
+			}
+		}
+
+		start := time.Now()
+		res, err := next.Do(req)
+		args = append(args, "duration", time.Since(start))
+
+		ctx := httpReq.Context()
+
+		if err != nil {
+			args = append(args, "error", redactToken(err.Error(), token))
+			l.logger.Log(ctx, l.opts.ErrorLevel, "notion: request failed", args...)
+			return res, err
+		}
+
+		httpRes := res.HTTPResponse
+		args = append(args, "status", httpRes.StatusCode)
+
+		if l.opts.MaxBodySize > 0 {
+			b, err := io.ReadAll(httpRes.Body)
+			httpRes.Body.Close()
+			httpRes.Body = io.NopCloser(bytes.NewReader(b))
+			if err == nil && len(b) > 0 {
+				args = append(args, "response_body", l.formatBody(b, token))
+			}
+		}
+
+		level := l.opts.Level
+		if res.Err != nil {
+			level = l.opts.ErrorLevel
+			if res.Err.Code != "" {
+				args = append(args, "error_code", res.Err.Code)
+			}
+			if res.Err.RequestID != "" {
+				args = append(args, "request_id", res.Err.RequestID)
+			}
+		}
+
+		l.logger.Log(ctx, level, "notion: request", args...)
+
+		return res, nil
+	})
+}
+
+// formatBody redacts and truncates a request or response body.
+func (l *requestLogger) formatBody(b []byte, token string) string {
+	if l.opts.RedactPersonalData {
+		b = redactPersonalData(b)
+	}
+
+	body := redactToken(string(b), token)
+	if len(body) > l.opts.MaxBodySize {
+		n := l.opts.MaxBodySize
+		for n > 0 && !utf8.RuneStart(body[n]) {
+			n--
+		}
+		body = body[:n] + "…(truncated)"
+	}
+
+	return body
+}
+
+// redactToken replaces all occurrences of the integration token in s.
+func redactToken(s, token string) string {
+	if token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, token, redacted)
+}
+
+// redactPersonalData replaces the values of personal data fields in a JSON
+// body. Bodies that aren't valid JSON are returned as is.
+func redactPersonalData(b []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return b
+	}
+
+	redactPersonalDataValue(v)
+
+	redactedBody, err := json.Marshal(v)
+	if err != nil {
+		return b
+	}
+
+	return redactedBody
+}
+
+func redactPersonalDataValue(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := value.(string); ok && personalDataFields[key] {
+				v[key] = redacted
+				continue
+			}
+			redactPersonalDataValue(value)
+		}
+	case []interface{}:
+		for _, value := range v {
+			redactPersonalDataValue(value)
+		}
+	}
+}
+
+// StdLogger is a Logger that writes entries with a level of at least
+// MinLevel to a standard library *log.Logger, formatted as `key=value` pairs.
+type StdLogger struct {
+	Logger   *log.Logger
+	MinLevel LogLevel
+}
+
+// NewStdLogger returns a new StdLogger.
+func NewStdLogger(logger *log.Logger, minLevel LogLevel) *StdLogger {
+	return &StdLogger{Logger: logger, MinLevel: minLevel}
+}
+
+// Log implements Logger.
+func (l *StdLogger) Log(_ context.Context, level LogLevel, msg string, args ...interface{}) {
+	if level < l.MinLevel {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(level.String())
+	sb.WriteString(" ")
+	sb.WriteString(msg)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&sb, " %v=%q", args[i], fmt.Sprint(args[i+1]))
+	}
+
+	l.Logger.Print(sb.String())
+}
@@ -0,0 +1,187 @@
+package notion_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/google/go-cmp/cmp"
+)
+
+type logEntry struct {
+	Level notion.LogLevel
+	Msg   string
+	Attrs map[string]string
+}
+
+type testLogger struct {
+	entries []logEntry
+}
+
+func (l *testLogger) Log(_ context.Context, level notion.LogLevel, msg string, args ...interface{}) {
+	entry := logEntry{Level: level, Msg: msg, Attrs: map[string]string{}}
+	for i := 0; i+1 < len(args); i += 2 {
+		key := args[i].(string)
+		if key == "duration" {
+			continue
+		}
+		entry.Attrs[key] = fmt.Sprint(args[i+1])
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	const apiKey = "secret-api-key"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.Header().Set("X-Request-Id", "req-1")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"Could not find user."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"object":"list","results":[{"object":"page","id":"p1","parent":{"type":"database_id","database_id":"db1"},"properties":{"Email":{"id":"x","type":"email","email":"jane@example.com"}}}],"has_more":false}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name       string
+		opts       *notion.LoggerOptions
+		expEntries []logEntry
+	}{
+		{
+			name: "default options",
+			expEntries: []logEntry{
+				{
+					Level: notion.LogLevelDebug,
+					Msg:   "notion: request",
+					Attrs: map[string]string{
+						"operation":     "Search",
+						"method":        "POST",
+						"path":          "/search",
+						"request_body":  `{"query":"[REDACTED]"}` + "\n",
+						"status":        "200",
+						"response_body": `{"object":"list","results":[{"object":"page","id":"p1","parent":{"type":"database_id","database_id":"db1"},"properties":{"Email":{"id":"x","type":"email","email":"jane@example.com"}}}],"has_more":false}`,
+					},
+				},
+				{
+					Level: notion.LogLevelWarn,
+					Msg:   "notion: request",
+					Attrs: map[string]string{
+						"operation":     "FindUserByID",
+						"method":        "GET",
+						"path":          "/users/u1",
+						"resource_id":   "u1",
+						"status":        "404",
+						"response_body": `{"object":"error","status":404,"code":"object_not_found","message":"Could not find user."}`,
+						"error_code":    "object_not_found",
+						"request_id":    "req-1",
+					},
+				},
+			},
+		},
+		{
+			name: "redact personal data and truncate bodies",
+			opts: &notion.LoggerOptions{
+				Level:              notion.LogLevelInfo,
+				ErrorLevel:         notion.LogLevelError,
+				MaxBodySize:        168,
+				RedactPersonalData: true,
+			},
+			expEntries: []logEntry{
+				{
+					Level: notion.LogLevelInfo,
+					Msg:   "notion: request",
+					Attrs: map[string]string{
+						"operation":     "Search",
+						"method":        "POST",
+						"path":          "/search",
+						"request_body":  `{"query":"[REDACTED]"}`,
+						"status":        "200",
+						"response_body": `{"has_more":false,"object":"list","results":[{"id":"p1","object":"page","parent":{"database_id":"db1","type":"database_id"},"properties":{"Email":{"email":"[REDACTED]",…(truncated)`,
+					},
+				},
+				{
+					Level: notion.LogLevelError,
+					Msg:   "notion: request",
+					Attrs: map[string]string{
+						"operation":     "FindUserByID",
+						"method":        "GET",
+						"path":          "/users/u1",
+						"resource_id":   "u1",
+						"status":        "404",
+						"response_body": `{"code":"object_not_found","message":"Could not find user.","object":"error","status":404}`,
+						"error_code":    "object_not_found",
+						"request_id":    "req-1",
+					},
+				},
+			},
+		},
+		{
+			name: "omit bodies",
+			opts: &notion.LoggerOptions{MaxBodySize: -1},
+			expEntries: []logEntry{
+				{
+					Msg: "notion: request",
+					Attrs: map[string]string{
+						"operation": "Search",
+						"method":    "POST",
+						"path":      "/search",
+						"status":    "200",
+					},
+				},
+				{
+					Msg: "notion: request",
+					Attrs: map[string]string{
+						"operation":   "FindUserByID",
+						"method":      "GET",
+						"path":        "/users/u1",
+						"resource_id": "u1",
+						"status":      "404",
+						"error_code":  "object_not_found",
+						"request_id":  "req-1",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := &testLogger{}
+			client := notion.NewClient(apiKey,
+				notion.WithBaseURL(srv.URL),
+				notion.WithLogger(logger, tt.opts),
+			)
+
+			// The query contains the API key, to verify it's redacted.
+			if _, err := client.Search(context.Background(), &notion.SearchOpts{Query: apiKey}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := client.FindUserByID(context.Background(), "u1"); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			if diff := cmp.Diff(tt.expEntries, logger.entries); diff != "" {
+				t.Fatalf("log entries not equal (-exp, +got):\n%v", diff)
+			}
+			for _, entry := range logger.entries {
+				for key, value := range entry.Attrs {
+					if strings.Contains(value, apiKey) {
+						t.Errorf("API key not redacted in %q: %v", key, value)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,450 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// MaxRichTextLength is the maximum length of the content of a single rich
+// text object, as enforced by the Notion API.
+// See: https://developers.notion.com/reference/request-limits#limits-for-property-values
+const MaxRichTextLength = 2000
+
+// ParseRichText parses inline Markdown (emphasis, code spans, links, inline
+// math, etc.) into rich text. Text longer than MaxRichTextLength is split
+// over multiple rich text objects.
+func ParseRichText(md string) []notion.RichText {
+	return toRichText(parseInline(md, notion.Annotations{}, ""))
+}
+
+// span is a run of inline content with uniform formatting.
+type span struct {
+	text        string
+	annotations notion.Annotations
+	link        string
+	equation    bool
+}
+
+var (
+	autolinkRegexp = regexp.MustCompile(`^<((?:https?|mailto):[^\s<>]*)>`)
+	bareURLRegexp  = regexp.MustCompile(`^https?://[^\s<]+`)
+	lineBreakTag   = regexp.MustCompile(`^<br\s*/?>`)
+)
+
+// parseInline parses inline Markdown into spans. The annotations and link
+// are applied to all spans, and are used for nested content.
+func parseInline(s string, annotations notion.Annotations, link string) []span {
+	var (
+		spans []span
+		text  strings.Builder
+	)
+
+	flush := func() {
+		if text.Len() > 0 {
+			spans = append(spans, span{text: text.String(), annotations: annotations, link: link})
+			text.Reset()
+		}
+	}
+	emit := func(nested ...span) {
+		flush()
+		spans = append(spans, nested...)
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch c {
+		case '\\':
+			switch {
+			case i+1 < len(s) && s[i+1] == '\n':
+				text.WriteByte('\n')
+				i += 2
+			case i+1 < len(s) && isASCIIPunct(s[i+1]):
+				text.WriteByte(s[i+1])
+				i += 2
+			default:
+				text.WriteByte(c)
+				i++
+			}
+			continue
+		case '\n':
+			// Soft line breaks are rendered as spaces.
+			text.WriteByte(' ')
+			i++
+			continue
+		case '`':
+			n := runLength(s, i, '`')
+			if end := findBacktickRun(s, i+n, n); end >= 0 {
+				anno := annotations
+				anno.Code = true
+				emit(span{text: codeSpanContent(s[i+n : end]), annotations: anno, link: link})
+				i = end + n
+				continue
+			}
+			text.WriteString(s[i : i+n])
+			i += n
+			continue
+		case '$':
+			if end := findMathEnd(s, i); end >= 0 {
+				emit(span{text: s[i+1 : end], annotations: annotations, equation: true})
+				i = end + 1
+				continue
+			}
+		case '!', '[':
+			start := i
+			if c == '!' {
+				if i+1 >= len(s) || s[i+1] != '[' {
+					break
+				}
+				start++
+			}
+			if label, dest, end, ok := parseLink(s, start); ok {
+				emit(parseInline(label, annotations, dest)...)
+				i = end
+				continue
+			}
+		case '<':
+			rest := s[i:]
+			if m := autolinkRegexp.FindStringSubmatch(rest); m != nil {
+				emit(span{text: m[1], annotations: annotations, link: m[1]})
+				i += len(m[0])
+				continue
+			}
+			if m := lineBreakTag.FindString(rest); m != "" {
+				text.WriteByte('\n')
+				i += len(m)
+				continue
+			}
+			if strings.HasPrefix(rest, "<u>") {
+				if end := strings.Index(rest[3:], "</u>"); end >= 0 {
+					anno := annotations
+					anno.Underline = true
+					emit(parseInline(rest[3:3+end], anno, link)...)
+					i += 3 + end + 4
+					continue
+				}
+			}
+		case 'h':
+			if link == "" && (i == 0 || !isAlnum(s[i-1])) {
+				if url := bareURL(s[i:]); url != "" {
+					emit(span{text: url, annotations: annotations, link: url})
+					i += len(url)
+					continue
+				}
+			}
+		case '*', '_', '~':
+			n := runLength(s, i, c)
+			if end := findClosingDelim(s, i, n, c); end >= 0 {
+				anno := annotations
+				switch {
+				case c == '~':
+					anno.Strikethrough = true
+				case n == 1:
+					anno.Italic = true
+				case n == 2:
+					anno.Bold = true
+				case n == 3:
+					anno.Bold = true
+					anno.Italic = true
+				}
+				emit(parseInline(s[i+n:end], anno, link)...)
+				i = end + n
+				continue
+			}
+			text.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+
+		text.WriteByte(c)
+		i++
+	}
+
+	flush()
+
+	return spans
+}
+
+// findClosingDelim returns the index of the delimiter run of length n that
+// closes the run at i, or -1 if there is none.
+func findClosingDelim(s string, i, n int, c byte) int {
+	if (c == '~' && n > 2) || n > 3 {
+		return -1
+	}
+
+	// Opening runs must be left-flanking.
+	start := i + n
+	if start >= len(s) || isSpace(s[start]) {
+		return -1
+	}
+	if c == '_' && i > 0 && isAlnum(s[i-1]) {
+		return -1
+	}
+
+	for j := start; j < len(s); {
+		switch s[j] {
+		case '\\':
+			j += 2
+			continue
+		case '`':
+			m := runLength(s, j, '`')
+			if end := findBacktickRun(s, j+m, m); end >= 0 {
+				j = end + m
+			} else {
+				j += m
+			}
+			continue
+		case c:
+			m := runLength(s, j, c)
+			closing := m == n && !isSpace(s[j-1])
+			if c == '_' && j+m < len(s) && isAlnum(s[j+m]) {
+				closing = false
+			}
+			if closing {
+				return j
+			}
+			j += m
+			continue
+		}
+		j++
+	}
+
+	return -1
+}
+
+// parseLink parses an inline link (`[label](dest "title")`) starting at the
+// opening bracket at i.
+func parseLink(s string, i int) (label, dest string, end int, ok bool) {
+	depth := 0
+	j := i
+	for ; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if j >= len(s) || j+1 >= len(s) || s[j+1] != '(' {
+		return "", "", 0, false
+	}
+	label = s[i+1 : j]
+
+	k := j + 2
+	for k < len(s) && s[k] == ' ' {
+		k++
+	}
+
+	if k < len(s) && s[k] == '<' {
+		closing := strings.IndexByte(s[k:], '>')
+		if closing < 0 {
+			return "", "", 0, false
+		}
+		dest = s[k+1 : k+closing]
+		k += closing + 1
+	} else {
+		parens := 0
+		destStart := k
+		for ; k < len(s); k++ {
+			if s[k] == ' ' || s[k] == '\n' || (s[k] == ')' && parens == 0) {
+				break
+			}
+			switch s[k] {
+			case '(':
+				parens++
+			case ')':
+				parens--
+			}
+		}
+		dest = s[destStart:k]
+	}
+
+	// Skip optional title.
+	for k < len(s) && (s[k] == ' ' || s[k] == '\n') {
+		k++
+	}
+	if k < len(s) && (s[k] == '"' || s[k] == '\'') {
+		closing := strings.IndexByte(s[k+1:], s[k])
+		if closing < 0 {
+			return "", "", 0, false
+		}
+		k += closing + 2
+		for k < len(s) && s[k] == ' ' {
+			k++
+		}
+	}
+	if k >= len(s) || s[k] != ')' {
+		return "", "", 0, false
+	}
+
+	return label, unescape(dest), k + 1, true
+}
+
+// findMathEnd returns the index of the `$` that closes the inline math
+// starting at i, or -1 if there is none.
+func findMathEnd(s string, i int) int {
+	if i+1 >= len(s) || s[i+1] == '$' || isSpace(s[i+1]) {
+		return -1
+	}
+
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '$':
+			if isSpace(s[j-1]) || (j+1 < len(s) && s[j+1] >= '0' && s[j+1] <= '9') {
+				return -1
+			}
+			return j
+		}
+	}
+
+	return -1
+}
+
+// bareURL returns the (GFM extended) autolink at the start of s, if any.
+func bareURL(s string) string {
+	url := bareURLRegexp.FindString(s)
+	if url == "" {
+		return ""
+	}
+
+	// Trailing punctuation is not part of the link, and neither are closing
+	// parentheses without an opening one.
+	for len(url) > 0 {
+		last := url[len(url)-1]
+		if strings.IndexByte("?!.,:*_~'\"", last) >= 0 {
+			url = url[:len(url)-1]
+			continue
+		}
+		if last == ')' && strings.Count(url, "(") < strings.Count(url, ")") {
+			url = url[:len(url)-1]
+			continue
+		}
+		break
+	}
+
+	return url
+}
+
+func findBacktickRun(s string, start, n int) int {
+	for j := start; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		m := runLength(s, j, '`')
+		if m == n {
+			return j
+		}
+		j += m
+	}
+	return -1
+}
+
+func codeSpanContent(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) >= 2 && s[0] == ' ' && s[len(s)-1] == ' ' && strings.Trim(s, " ") != "" {
+		s = s[1 : len(s)-1]
+	}
+	return s
+}
+
+func runLength(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+func unescape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]) {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isAlnum(c byte) bool {
+	return c >= utf8.RuneSelf || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// toRichText merges adjacent spans with equal formatting and converts them to
+// rich text, splitting text that exceeds MaxRichTextLength.
+func toRichText(spans []span) []notion.RichText {
+	var merged []span
+	for _, s := range spans {
+		if s.text == "" {
+			continue
+		}
+		if n := len(merged); n > 0 && !s.equation && !merged[n-1].equation &&
+			merged[n-1].annotations == s.annotations && merged[n-1].link == s.link {
+			merged[n-1].text += s.text
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	richText := make([]notion.RichText, 0, len(merged))
+	for _, s := range merged {
+		var annotations *notion.Annotations
+		if s.annotations != (notion.Annotations{}) {
+			anno := s.annotations
+			annotations = &anno
+		}
+
+		if s.equation {
+			richText = append(richText, notion.RichText{
+				Type:        notion.RichTextTypeEquation,
+				Annotations: annotations,
+				Equation:    &notion.Equation{Expression: s.text},
+			})
+			continue
+		}
+
+		var link *notion.Link
+		if s.link != "" {
+			link = &notion.Link{URL: s.link}
+		}
+
+		for _, rt := range plainRichText(s.text) {
+			rt.Annotations = annotations
+			rt.Text.Link = link
+			richText = append(richText, rt)
+		}
+	}
+
+	return richText
+}
+
+// plainRichText returns s as unformatted rich text, split to respect
+// MaxRichTextLength.
+func plainRichText(s string) []notion.RichText {
+	richText := notion.TextRichText(s)
+	for i := range richText {
+		richText[i].Type = notion.RichTextTypeText
+	}
+	return richText
+}
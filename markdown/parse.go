@@ -0,0 +1,551 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// Parse parses Markdown (CommonMark, with GFM tables, task lists and
+// strikethrough, and `$`/`$$` delimited math) into blocks, ready to be used
+// for CreatePageParams.Children or Client.AppendBlockChildren.
+//
+// Rich text exceeding MaxRichTextLength is split automatically. Note that the
+// Notion API accepts at most two levels of nested children per request, so
+// deeply nested lists must be appended in multiple requests.
+func Parse(md string) []notion.Block {
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+	md = strings.ReplaceAll(md, "\t", "    ")
+
+	return parseBlocks(strings.Split(md, "\n"))
+}
+
+var (
+	fenceRegexp      = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ ]*([^`]*?)[ ]*$")
+	atxHeadingRegexp = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ ]+(.*?))?(?:[ ]+#+)?[ ]*$`)
+	setextRegexp     = regexp.MustCompile(`^ {0,3}(=+|-+)[ ]*$`)
+	thematicRegexp   = regexp.MustCompile(`^ {0,3}(?:(?:\*[ ]*){3,}|(?:-[ ]*){3,}|(?:_[ ]*){3,})$`)
+	listItemRegexp   = regexp.MustCompile(`^( {0,3})([-+*]|\d{1,9}[.)])(?:( +)(.*))?$`)
+	taskRegexp       = regexp.MustCompile(`^\[([ xX])\](?:[ ]+|$)`)
+	quoteRegexp      = regexp.MustCompile(`^ {0,3}> ?`)
+	mathBlockRegexp  = regexp.MustCompile(`^ {0,3}\$\$(.*)$`)
+	tableDelimRegexp = regexp.MustCompile(`^ {0,3}\|?[ ]*:?-+:?[ ]*(?:\|[ ]*:?-+:?[ ]*)*\|?[ ]*$`)
+	detailsRegexp    = regexp.MustCompile(`^ {0,3}<details>`)
+	summaryRegexp    = regexp.MustCompile(`(?s)^\s*<summary>(.*?)</summary>`)
+	imageRegexp      = regexp.MustCompile(`^!\[([^\]]*)\]\(<?([^\s>]*)>?(?:\s+"[^"]*")?\)$`)
+)
+
+func parseBlocks(lines []string) []notion.Block {
+	var blocks []notion.Block
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		if isBlank(line) {
+			i++
+			continue
+		}
+
+		var (
+			block  notion.Block
+			list   []notion.Block
+			nextLn int
+		)
+
+		switch {
+		case fenceRegexp.MatchString(line):
+			block, nextLn = parseFencedCode(lines, i)
+		case indentOf(line) >= 4:
+			block, nextLn = parseIndentedCode(lines, i)
+		case mathBlockRegexp.MatchString(line):
+			block, nextLn = parseMathBlock(lines, i)
+		case atxHeadingRegexp.MatchString(line):
+			m := atxHeadingRegexp.FindStringSubmatch(line)
+			block, nextLn = newHeading(len(m[1]), ParseRichText(m[2])), i+1
+		case thematicRegexp.MatchString(line):
+			block, nextLn = &notion.DividerBlock{}, i+1
+		case quoteRegexp.MatchString(line):
+			block, nextLn = parseQuote(lines, i)
+		case listItemRegexp.MatchString(line):
+			list, nextLn = parseList(lines, i)
+		case detailsRegexp.MatchString(line):
+			block, nextLn = parseDetails(lines, i)
+		case isTableStart(lines, i):
+			block, nextLn = parseTable(lines, i)
+		default:
+			block, nextLn = parseParagraph(lines, i)
+		}
+
+		if block != nil {
+			blocks = append(blocks, block)
+		}
+		blocks = append(blocks, list...)
+		i = nextLn
+	}
+
+	return blocks
+}
+
+func parseFencedCode(lines []string, i int) (notion.Block, int) {
+	m := fenceRegexp.FindStringSubmatch(lines[i])
+	fence, info := m[1], m[2]
+	indent := indentOf(lines[i])
+
+	var code []string
+	j := i + 1
+	for ; j < len(lines); j++ {
+		trimmed := strings.TrimSpace(lines[j])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" && indentOf(lines[j]) < 4 {
+			j++
+			break
+		}
+		code = append(code, trimLeftSpaces(lines[j], indent))
+	}
+
+	language := ""
+	if fields := strings.Fields(info); len(fields) > 0 {
+		language = fields[0]
+	}
+
+	return &notion.CodeBlock{
+		RichText: plainRichText(strings.Join(code, "\n")),
+		Language: notion.StringPtr(notionLanguage(language)),
+	}, j
+}
+
+func parseIndentedCode(lines []string, i int) (notion.Block, int) {
+	var code []string
+	j := i
+	for ; j < len(lines); j++ {
+		if !isBlank(lines[j]) && indentOf(lines[j]) < 4 {
+			break
+		}
+		code = append(code, trimLeftSpaces(lines[j], 4))
+	}
+	for len(code) > 0 && isBlank(code[len(code)-1]) {
+		code = code[:len(code)-1]
+	}
+
+	return &notion.CodeBlock{
+		RichText: plainRichText(strings.Join(code, "\n")),
+		Language: notion.StringPtr("plain text"),
+	}, j
+}
+
+func parseMathBlock(lines []string, i int) (notion.Block, int) {
+	first := strings.TrimSpace(mathBlockRegexp.FindStringSubmatch(lines[i])[1])
+
+	// Single line, e.g. `$$ x^2 $$`.
+	if strings.HasSuffix(first, "$$") {
+		return &notion.EquationBlock{Expression: strings.TrimSpace(strings.TrimSuffix(first, "$$"))}, i + 1
+	}
+
+	expr := []string{}
+	if first != "" {
+		expr = append(expr, first)
+	}
+
+	j := i + 1
+	for ; j < len(lines); j++ {
+		trimmed := strings.TrimSpace(lines[j])
+		if strings.HasSuffix(trimmed, "$$") {
+			if rest := strings.TrimSpace(strings.TrimSuffix(trimmed, "$$")); rest != "" {
+				expr = append(expr, rest)
+			}
+			j++
+			break
+		}
+		expr = append(expr, lines[j])
+	}
+
+	return &notion.EquationBlock{Expression: strings.Join(expr, "\n")}, j
+}
+
+// newHeading returns a heading block. Notion only supports three heading
+// levels, so lower level headings are converted to `heading_3` blocks.
+func newHeading(level int, richText []notion.RichText) notion.Block {
+	switch level {
+	case 1:
+		return &notion.Heading1Block{RichText: richText}
+	case 2:
+		return &notion.Heading2Block{RichText: richText}
+	default:
+		return &notion.Heading3Block{RichText: richText}
+	}
+}
+
+func parseQuote(lines []string, i int) (notion.Block, int) {
+	var content []string
+	j := i
+	for ; j < len(lines); j++ {
+		line := lines[j]
+		if loc := quoteRegexp.FindStringIndex(line); loc != nil {
+			content = append(content, line[loc[1]:])
+			continue
+		}
+		// Lazy continuation lines of a paragraph.
+		if isBlank(line) || isBlank(content[len(content)-1]) || interruptsParagraph(line) {
+			break
+		}
+		content = append(content, line)
+	}
+
+	richText, children := splitFirstParagraph(parseBlocks(content))
+
+	return &notion.QuoteBlock{RichText: richText, Children: children}, j
+}
+
+func parseList(lines []string, i int) ([]notion.Block, int) {
+	var (
+		items  []notion.Block
+		marker string
+	)
+
+	j := i
+	for j < len(lines) {
+		m := listItemRegexp.FindStringSubmatch(lines[j])
+		if m == nil || (marker != "" && listMarkerKind(m[2]) != marker) {
+			break
+		}
+		marker = listMarkerKind(m[2])
+
+		// Content is indented to the first non-space character after the
+		// marker, unless the item starts with indented code or is empty.
+		offset := len(m[1]) + len(m[2]) + 1
+		if n := len(m[3]); n > 0 && n <= 4 && m[4] != "" {
+			offset = len(m[1]) + len(m[2]) + n
+		}
+
+		content := []string{m[4]}
+		j++
+
+	itemLines:
+		for ; j < len(lines); j++ {
+			line := lines[j]
+			switch {
+			case isBlank(line):
+				content = append(content, "")
+			case indentOf(line) >= offset:
+				content = append(content, line[offset:])
+			case !isBlank(content[len(content)-1]) && !interruptsParagraph(line) && !listItemRegexp.MatchString(line):
+				// Lazy continuation line.
+				content = append(content, strings.TrimLeft(line, " "))
+			default:
+				break itemLines
+			}
+		}
+
+		// Blank lines at the end of an item belong to the list, not to the item.
+		for len(content) > 1 && isBlank(content[len(content)-1]) {
+			content = content[:len(content)-1]
+		}
+
+		items = append(items, newListItem(m[2], content))
+	}
+
+	return items, j
+}
+
+// listMarkerKind returns the kind of list a marker belongs to. Changing the
+// bullet character or ordered list delimiter starts a new list.
+func listMarkerKind(marker string) string {
+	last := marker[len(marker)-1:]
+	if last == "." || last == ")" {
+		return "ordered" + last
+	}
+	return marker
+}
+
+func newListItem(marker string, content []string) notion.Block {
+	var checked *bool
+	if m := taskRegexp.FindStringSubmatch(content[0]); m != nil {
+		checked = notion.BoolPtr(m[1] != " ")
+		content[0] = content[0][len(m[0]):]
+	}
+
+	richText, children := splitFirstParagraph(parseBlocks(content))
+
+	switch {
+	case checked != nil:
+		return &notion.ToDoBlock{RichText: richText, Children: children, Checked: checked}
+	case listMarkerKind(marker) == marker:
+		return &notion.BulletedListItemBlock{RichText: richText, Children: children}
+	default:
+		return &notion.NumberedListItemBlock{RichText: richText, Children: children}
+	}
+}
+
+// parseDetails parses a `<details>` HTML block, with an optional `<summary>`,
+// into a toggle block.
+func parseDetails(lines []string, i int) (notion.Block, int) {
+	var (
+		content []string
+		depth   int
+	)
+
+	j := i
+	for ; j < len(lines); j++ {
+		depth += strings.Count(lines[j], "<details>") - strings.Count(lines[j], "</details>")
+		content = append(content, lines[j])
+		if depth <= 0 {
+			j++
+			break
+		}
+	}
+
+	inner := strings.Join(content, "\n")
+	inner = inner[strings.Index(inner, "<details>")+len("<details>"):]
+	if end := strings.LastIndex(inner, "</details>"); end >= 0 {
+		inner = inner[:end]
+	}
+
+	var summary []notion.RichText
+	if m := summaryRegexp.FindStringSubmatchIndex(inner); m != nil {
+		summary = ParseRichText(strings.TrimSpace(inner[m[2]:m[3]]))
+		inner = inner[m[1]:]
+	}
+	if summary == nil {
+		summary = []notion.RichText{}
+	}
+
+	return &notion.ToggleBlock{
+		RichText: summary,
+		Children: parseBlocks(strings.Split(inner, "\n")),
+	}, j
+}
+
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") || !tableDelimRegexp.MatchString(lines[i+1]) {
+		return false
+	}
+	return len(splitTableRow(lines[i])) == len(splitTableRow(lines[i+1]))
+}
+
+func parseTable(lines []string, i int) (notion.Block, int) {
+	width := len(splitTableRow(lines[i]))
+	rows := []notion.Block{newTableRow(splitTableRow(lines[i]), width)}
+
+	j := i + 2
+	for ; j < len(lines); j++ {
+		if isBlank(lines[j]) || interruptsParagraph(lines[j]) {
+			break
+		}
+		rows = append(rows, newTableRow(splitTableRow(lines[j]), width))
+	}
+
+	return &notion.TableBlock{
+		TableWidth:      width,
+		HasColumnHeader: true,
+		Children:        rows,
+	}, j
+}
+
+func newTableRow(cells []string, width int) notion.Block {
+	row := &notion.TableRowBlock{Cells: make([][]notion.RichText, width)}
+	for i := range row.Cells {
+		row.Cells[i] = []notion.RichText{}
+		if i < len(cells) {
+			row.Cells[i] = ParseRichText(strings.ReplaceAll(cells[i], `\|`, "|"))
+		}
+	}
+	return row
+}
+
+// splitTableRow splits a table row on unescaped pipes.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var (
+		cells []string
+		start int
+	)
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '|':
+			cells = append(cells, strings.TrimSpace(line[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(cells, strings.TrimSpace(line[start:]))
+}
+
+func parseParagraph(lines []string, i int) (notion.Block, int) {
+	content := []string{lines[i]}
+
+	j := i + 1
+	for ; j < len(lines); j++ {
+		line := lines[j]
+		if m := setextRegexp.FindStringSubmatch(line); m != nil {
+			level := 1
+			if m[1][0] == '-' {
+				level = 2
+			}
+			return newHeading(level, ParseRichText(joinLines(content))), j + 1
+		}
+		if isBlank(line) || interruptsParagraph(line) {
+			break
+		}
+		content = append(content, line)
+	}
+
+	text := joinLines(content)
+
+	// A paragraph with only an image is converted to an image block.
+	if m := imageRegexp.FindStringSubmatch(text); m != nil {
+		return &notion.ImageBlock{
+			Type:     notion.FileTypeExternal,
+			External: &notion.FileExternal{URL: m[2]},
+			Caption:  ParseRichText(m[1]),
+		}, j
+	}
+
+	return &notion.ParagraphBlock{RichText: ParseRichText(text)}, j
+}
+
+// joinLines joins the lines of a paragraph. Hard line breaks (a trailing
+// backslash or two or more trailing spaces) are normalized to a backslash
+// followed by a newline.
+func joinLines(lines []string) string {
+	var sb strings.Builder
+
+	for i, line := range lines {
+		line = strings.TrimLeft(line, " ")
+		if i == len(lines)-1 {
+			sb.WriteString(strings.TrimRight(line, " "))
+			break
+		}
+
+		trimmed := strings.TrimRight(line, " ")
+		if len(line)-len(trimmed) >= 2 && !strings.HasSuffix(trimmed, `\`) {
+			trimmed += `\`
+		}
+		sb.WriteString(trimmed)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// interruptsParagraph reports whether line starts a block that can interrupt
+// a paragraph.
+func interruptsParagraph(line string) bool {
+	if fenceRegexp.MatchString(line) || atxHeadingRegexp.MatchString(line) ||
+		thematicRegexp.MatchString(line) || quoteRegexp.MatchString(line) ||
+		mathBlockRegexp.MatchString(line) || detailsRegexp.MatchString(line) {
+		return true
+	}
+
+	// Only non-empty bulleted lists and ordered lists starting with 1 can
+	// interrupt a paragraph.
+	m := listItemRegexp.FindStringSubmatch(line)
+	if m == nil || strings.TrimSpace(m[4]) == "" {
+		return false
+	}
+	if listMarkerKind(m[2]) == m[2] {
+		return true
+	}
+	n, _ := strconv.Atoi(m[2][:len(m[2])-1])
+	return n == 1
+}
+
+// splitFirstParagraph returns the rich text of the first block if it's a
+// paragraph, and the remaining blocks as children.
+func splitFirstParagraph(blocks []notion.Block) ([]notion.RichText, []notion.Block) {
+	if len(blocks) > 0 {
+		if p, ok := blocks[0].(*notion.ParagraphBlock); ok {
+			if len(blocks) == 1 {
+				return p.RichText, nil
+			}
+			return p.RichText, blocks[1:]
+		}
+	}
+	return []notion.RichText{}, blocks
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func trimLeftSpaces(line string, max int) string {
+	n := indentOf(line)
+	if n > max {
+		n = max
+	}
+	return line[n:]
+}
+
+// notionLanguages are the languages supported by code blocks.
+var notionLanguages = map[string]bool{
+	"abap": true, "arduino": true, "bash": true, "basic": true, "c": true,
+	"clojure": true, "coffeescript": true, "c++": true, "c#": true, "css": true,
+	"dart": true, "diff": true, "docker": true, "elixir": true, "elm": true,
+	"erlang": true, "flow": true, "fortran": true, "f#": true, "gherkin": true,
+	"glsl": true, "go": true, "graphql": true, "groovy": true, "haskell": true,
+	"html": true, "java": true, "javascript": true, "json": true, "julia": true,
+	"kotlin": true, "latex": true, "less": true, "lisp": true, "livescript": true,
+	"lua": true, "makefile": true, "markdown": true, "markup": true, "matlab": true,
+	"mermaid": true, "nix": true, "objective-c": true, "ocaml": true, "pascal": true,
+	"perl": true, "php": true, "plain text": true, "powershell": true, "prolog": true,
+	"protobuf": true, "python": true, "r": true, "reason": true, "ruby": true,
+	"rust": true, "sass": true, "scala": true, "scheme": true, "scss": true,
+	"shell": true, "sql": true, "swift": true, "typescript": true, "vb.net": true,
+	"verilog": true, "vhdl": true, "visual basic": true, "webassembly": true,
+	"xml": true, "yaml": true, "java/c/c++/c#": true,
+}
+
+// languageAliases maps common Markdown info strings to Notion languages.
+var languageAliases = map[string]string{
+	"cpp":        "c++",
+	"cs":         "c#",
+	"csharp":     "c#",
+	"dockerfile": "docker",
+	"fsharp":     "f#",
+	"golang":     "go",
+	"js":         "javascript",
+	"jsx":        "javascript",
+	"md":         "markdown",
+	"objc":       "objective-c",
+	"proto":      "protobuf",
+	"ps1":        "powershell",
+	"py":         "python",
+	"rb":         "ruby",
+	"rs":         "rust",
+	"sh":         "shell",
+	"text":       "plain text",
+	"tex":        "latex",
+	"ts":         "typescript",
+	"tsx":        "typescript",
+	"txt":        "plain text",
+	"wasm":       "webassembly",
+	"yml":        "yaml",
+	"zsh":        "shell",
+}
+
+// notionLanguage returns the code block language for a fenced code block
+// info string, falling back to `plain text` for unknown languages.
+func notionLanguage(info string) string {
+	lang := strings.ToLower(strings.ReplaceAll(info, "-", " "))
+	if notionLanguages[lang] {
+		return lang
+	}
+	if alias, ok := languageAliases[lang]; ok {
+		return alias
+	}
+	if lang = strings.ToLower(info); notionLanguages[lang] {
+		return lang
+	}
+	return "plain text"
+}
@@ -0,0 +1,234 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/markdown"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		md        string
+		expBlocks []notion.Block
+	}{
+		{
+			name: "headings and paragraphs",
+			md:   "# Title\n\nSome text\nwrapped.\n\nSetext\n---\n\n#### Deep",
+			expBlocks: []notion.Block{
+				&notion.Heading1Block{RichText: []notion.RichText{text("Title")}},
+				&notion.ParagraphBlock{RichText: []notion.RichText{text("Some text wrapped.")}},
+				&notion.Heading2Block{RichText: []notion.RichText{text("Setext")}},
+				&notion.Heading3Block{RichText: []notion.RichText{text("Deep")}},
+			},
+		},
+		{
+			name: "inline formatting",
+			md:   "**bold** _italic_ ~~strike~~ `code` <u>under</u> [link](https://example.com) $x^2$ \\*not\\*  \nnext",
+			expBlocks: []notion.Block{
+				&notion.ParagraphBlock{RichText: []notion.RichText{
+					annotated("bold", notion.Annotations{Bold: true}),
+					text(" "),
+					annotated("italic", notion.Annotations{Italic: true}),
+					text(" "),
+					annotated("strike", notion.Annotations{Strikethrough: true}),
+					text(" "),
+					annotated("code", notion.Annotations{Code: true}),
+					text(" "),
+					annotated("under", notion.Annotations{Underline: true}),
+					text(" "),
+					{
+						Type: notion.RichTextTypeText,
+						Text: &notion.Text{Content: "link", Link: &notion.Link{URL: "https://example.com"}},
+					},
+					text(" "),
+					{Type: notion.RichTextTypeEquation, Equation: &notion.Equation{Expression: "x^2"}},
+					text(" *not*\nnext"),
+				}},
+			},
+		},
+		{
+			name: "nested emphasis and bare URLs",
+			md:   "**bold _both_** see https://example.com/a_b.",
+			expBlocks: []notion.Block{
+				&notion.ParagraphBlock{RichText: []notion.RichText{
+					annotated("bold ", notion.Annotations{Bold: true}),
+					annotated("both", notion.Annotations{Bold: true, Italic: true}),
+					text(" see "),
+					{
+						Type: notion.RichTextTypeText,
+						Text: &notion.Text{Content: "https://example.com/a_b", Link: &notion.Link{URL: "https://example.com/a_b"}},
+					},
+					text("."),
+				}},
+			},
+		},
+		{
+			name: "nested lists and task lists",
+			md:   "- One\n  - Nested\n- Two\n\n1. First\n2. Second\n   1. Nested\n\n- [x] Done\n- [ ] Todo",
+			expBlocks: []notion.Block{
+				&notion.BulletedListItemBlock{
+					RichText: []notion.RichText{text("One")},
+					Children: []notion.Block{
+						&notion.BulletedListItemBlock{RichText: []notion.RichText{text("Nested")}},
+					},
+				},
+				&notion.BulletedListItemBlock{RichText: []notion.RichText{text("Two")}},
+				&notion.NumberedListItemBlock{RichText: []notion.RichText{text("First")}},
+				&notion.NumberedListItemBlock{
+					RichText: []notion.RichText{text("Second")},
+					Children: []notion.Block{
+						&notion.NumberedListItemBlock{RichText: []notion.RichText{text("Nested")}},
+					},
+				},
+				&notion.ToDoBlock{RichText: []notion.RichText{text("Done")}, Checked: notion.BoolPtr(true)},
+				&notion.ToDoBlock{RichText: []notion.RichText{text("Todo")}, Checked: notion.BoolPtr(false)},
+			},
+		},
+		{
+			name: "code, math, quote, divider and image",
+			md:   "```js\nconsole.log(1)\n```\n\n$$\nx^2\n$$\n\n> Quoted\n> text\n\n***\n\n![Alt](https://example.com/a.png)",
+			expBlocks: []notion.Block{
+				&notion.CodeBlock{
+					RichText: []notion.RichText{text("console.log(1)")},
+					Language: notion.StringPtr("javascript"),
+				},
+				&notion.EquationBlock{Expression: "x^2"},
+				&notion.QuoteBlock{RichText: []notion.RichText{text("Quoted text")}},
+				&notion.DividerBlock{},
+				&notion.ImageBlock{
+					Type:     notion.FileTypeExternal,
+					External: &notion.FileExternal{URL: "https://example.com/a.png"},
+					Caption:  []notion.RichText{text("Alt")},
+				},
+			},
+		},
+		{
+			name: "table",
+			md:   "| Name | Value |\n| --- | ---: |\n| a\\|b | `1` |\n| c |",
+			expBlocks: []notion.Block{
+				&notion.TableBlock{
+					TableWidth:      2,
+					HasColumnHeader: true,
+					Children: []notion.Block{
+						&notion.TableRowBlock{Cells: [][]notion.RichText{{text("Name")}, {text("Value")}}},
+						&notion.TableRowBlock{Cells: [][]notion.RichText{{text("a|b")}, {annotated("1", notion.Annotations{Code: true})}}},
+						&notion.TableRowBlock{Cells: [][]notion.RichText{{text("c")}, {}}},
+					},
+				},
+			},
+		},
+		{
+			name: "details",
+			md:   "<details>\n<summary>More</summary>\n\nHidden\n\n</details>",
+			expBlocks: []notion.Block{
+				&notion.ToggleBlock{
+					RichText: []notion.RichText{text("More")},
+					Children: []notion.Block{
+						&notion.ParagraphBlock{RichText: []notion.RichText{text("Hidden")}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			blocks := markdown.Parse(tt.md)
+
+			if diff := cmp.Diff(tt.expBlocks, blocks); diff != "" {
+				t.Fatalf("blocks not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestParseSplitsLongText(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("a", markdown.MaxRichTextLength+10)
+	blocks := markdown.Parse("**" + long + "**")
+
+	richText := blocks[0].(*notion.ParagraphBlock).RichText
+	if len(richText) != 2 {
+		t.Fatalf("expected 2 rich text objects, got %v", len(richText))
+	}
+	for _, rt := range richText {
+		if !rt.Annotations.Bold {
+			t.Fatalf("expected bold annotation, got %+v", rt.Annotations)
+		}
+	}
+	if n := len(richText[0].Text.Content); n != markdown.MaxRichTextLength {
+		t.Fatalf("expected first rich text content length %v, got %v", markdown.MaxRichTextLength, n)
+	}
+	if n := len(richText[1].Text.Content); n != 10 {
+		t.Fatalf("expected second rich text content length 10, got %v", n)
+	}
+}
+
+func TestParseRenderRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	md := "# Title\n\nSome **bold**, *italic*, ***both*** and foo*bar*baz text.\n\n- One\n  - Nested\n- Two\n\n1. First\n2. Second\n\n- [x] Done\n\n" +
+		"```go\nfmt.Println()\n```\n\n> Quote\n\n| A | B |\n| --- | --- |\n| 1 | 2 |\n\n" +
+		"<details>\n<summary>More</summary>\n\nHidden\n\n</details>\n"
+
+	got, err := markdown.Render(markdown.Parse(md))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(md, got); diff != "" {
+		t.Fatalf("markdown not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestRenderParseRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	blocks := []notion.Block{
+		&notion.BulletedListItemBlock{
+			RichText: []notion.RichText{text("Item")},
+			Children: []notion.Block{
+				&notion.ParagraphBlock{RichText: []notion.RichText{text("Details")}},
+				&notion.BulletedListItemBlock{RichText: []notion.RichText{text("Nested")}},
+			},
+		},
+		&notion.BulletedListItemBlock{
+			RichText: []notion.RichText{text("Next")},
+			Children: []notion.Block{
+				&notion.BulletedListItemBlock{RichText: []notion.RichText{text("Nested")}},
+			},
+		},
+		&notion.NumberedListItemBlock{
+			RichText: []notion.RichText{text("First")},
+			Children: []notion.Block{
+				&notion.QuoteBlock{RichText: []notion.RichText{text("Quote")}},
+			},
+		},
+		&notion.ToDoBlock{
+			RichText: []notion.RichText{text("Task")},
+			Checked:  notion.BoolPtr(false),
+			Children: []notion.Block{
+				&notion.ParagraphBlock{RichText: []notion.RichText{text("Notes")}},
+			},
+		},
+	}
+
+	md, err := markdown.Render(blocks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(blocks, markdown.Parse(md)); diff != "" {
+		t.Fatalf("blocks not equal (-exp, +got):\n%v\nmarkdown:\n%v", diff, md)
+	}
+}
@@ -0,0 +1,695 @@
+// Package markdown converts between Notion blocks and Markdown (CommonMark,
+// with GitHub Flavored Markdown extensions for tables, task lists and
+// strikethrough).
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// Renderer renders Notion blocks and rich text as Markdown. The zero value is
+// ready to use.
+type Renderer struct {
+	// UnsupportedBlock is called for blocks that have no Markdown
+	// representation, e.g. `table_of_contents` or `breadcrumb`. The returned
+	// string is used as-is. When nil, unsupported blocks are omitted.
+	UnsupportedBlock func(block notion.Block) (string, error)
+
+	// Mention is called for every mention in rich text, with the Markdown
+	// rendered for the mention's plain text. When it returns false, or when
+	// it's nil, the mention is rendered as a link (if it has one) or text.
+	Mention func(mention notion.Mention, text string) (string, bool)
+
+	// PageURL returns the URL used for links to pages and databases, e.g. for
+	// `child_page` and `link_to_page` blocks. Defaults to the page's
+	// notion.so URL.
+	PageURL func(id string) string
+}
+
+// Render renders blocks as Markdown using the default Renderer.
+func Render(blocks []notion.Block) (string, error) {
+	var r Renderer
+	return r.Render(blocks)
+}
+
+// Render renders blocks, including their (nested) children, as Markdown. Use
+// Client.FetchBlockTree to fetch a block hierarchy with children filled in.
+func (r *Renderer) Render(blocks []notion.Block) (string, error) {
+	md, err := r.renderBlocks(blocks)
+	if err != nil {
+		return "", err
+	}
+	if md == "" {
+		return "", nil
+	}
+
+	return md + "\n", nil
+}
+
+// WriteTo renders blocks as Markdown to w.
+func (r *Renderer) WriteTo(w io.Writer, blocks []notion.Block) error {
+	md, err := r.Render(blocks)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, md)
+	return err
+}
+
+// RenderPage renders a page as Markdown, with its title as top level heading,
+// followed by its content.
+func (r *Renderer) RenderPage(page notion.Page, blocks []notion.Block) (string, error) {
+	content, err := r.Render(blocks)
+	if err != nil {
+		return "", err
+	}
+
+	title := r.renderRichText(PageTitle(page), false)
+	if title == "" {
+		return content, nil
+	}
+
+	heading := "# " + strings.ReplaceAll(title, "\n", " ") + "\n"
+	if content == "" {
+		return heading, nil
+	}
+
+	return heading + "\n" + content, nil
+}
+
+// ExportPage fetches a page and its content, and renders it as Markdown.
+// The contents of child pages and databases are not fetched.
+func (r *Renderer) ExportPage(ctx context.Context, client *notion.Client, pageID string, opts *notion.BlockTreeOptions) (string, error) {
+	page, err := client.FindPageByID(ctx, pageID)
+	if err != nil {
+		return "", err
+	}
+
+	treeOpts := notion.BlockTreeOptions{}
+	if opts != nil {
+		treeOpts = *opts
+	}
+	treeOpts.SkipChildPages = true
+	treeOpts.SkipChildDatabases = true
+
+	blocks, err := client.FetchBlockTree(ctx, pageID, &treeOpts)
+	if err != nil {
+		return "", err
+	}
+
+	return r.RenderPage(page, blocks)
+}
+
+// PageTitle returns the title of a page, regardless of its parent type.
+func PageTitle(page notion.Page) []notion.RichText {
+	switch props := page.Properties.(type) {
+	case notion.PageProperties:
+		return props.Title.Title
+	case notion.DatabasePageProperties:
+		for _, prop := range props {
+			if prop.Type == notion.DBPropTypeTitle {
+				return prop.Title
+			}
+		}
+	}
+
+	return nil
+}
+
+// RenderRichText renders rich text as inline Markdown.
+func (r *Renderer) RenderRichText(richText []notion.RichText) string {
+	return r.renderRichText(richText, false)
+}
+
+func (r *Renderer) renderBlocks(blocks []notion.Block) (string, error) {
+	var (
+		sb     strings.Builder
+		prev   notion.Block
+		number int
+	)
+
+	for _, block := range blocks {
+		if _, ok := block.(*notion.NumberedListItemBlock); ok {
+			number++
+		} else {
+			number = 0
+		}
+
+		md, err := r.renderBlock(block, number)
+		if err != nil {
+			return "", err
+		}
+		if md == "" {
+			continue
+		}
+
+		if sb.Len() > 0 {
+			if sameList(prev, block) {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString("\n\n")
+			}
+		}
+		sb.WriteString(md)
+		prev = block
+	}
+
+	return sb.String(), nil
+}
+
+// sameList reports whether a and b are items of the same (tight) list.
+func sameList(a, b notion.Block) bool {
+	switch a.(type) {
+	case *notion.BulletedListItemBlock, *notion.ToDoBlock:
+		switch b.(type) {
+		case *notion.BulletedListItemBlock, *notion.ToDoBlock:
+			return true
+		}
+	case *notion.NumberedListItemBlock:
+		_, ok := b.(*notion.NumberedListItemBlock)
+		return ok
+	}
+
+	return false
+}
+
+func (r *Renderer) renderBlock(block notion.Block, number int) (string, error) {
+	switch b := block.(type) {
+	case *notion.ParagraphBlock:
+		return r.withChildren(r.renderRichText(b.RichText, true), b.Children, "")
+	case *notion.Heading1Block:
+		return r.renderHeading(1, b.RichText, b.Children)
+	case *notion.Heading2Block:
+		return r.renderHeading(2, b.RichText, b.Children)
+	case *notion.Heading3Block:
+		return r.renderHeading(3, b.RichText, b.Children)
+	case *notion.BulletedListItemBlock:
+		return r.renderListItem("- ", r.renderRichText(b.RichText, true), b.Children)
+	case *notion.NumberedListItemBlock:
+		return r.renderListItem(fmt.Sprintf("%d. ", number), r.renderRichText(b.RichText, true), b.Children)
+	case *notion.ToDoBlock:
+		// Children are indented like those of bulleted list items, as the
+		// checkbox is part of the item's content.
+		checkbox := "[ ] "
+		if b.Checked != nil && *b.Checked {
+			checkbox = "[x] "
+		}
+		return r.renderListItem("- ", checkbox+r.renderRichText(b.RichText, true), b.Children)
+	case *notion.ToggleBlock:
+		return r.renderDetails(r.renderRichText(b.RichText, false), b.Children)
+	case *notion.QuoteBlock:
+		content, err := r.withChildren(r.renderRichText(b.RichText, true), b.Children, "")
+		if err != nil {
+			return "", err
+		}
+		return prefixLines(content, "> ", ">"), nil
+	case *notion.CalloutBlock:
+		text := r.renderRichText(b.RichText, true)
+		if b.Icon != nil && b.Icon.Emoji != nil {
+			text = *b.Icon.Emoji + " " + text
+		}
+		content, err := r.withChildren(text, b.Children, "")
+		if err != nil {
+			return "", err
+		}
+		return prefixLines(content, "> ", ">"), nil
+	case *notion.CodeBlock:
+		return renderCode(notion.PlainText(b.RichText), codeLanguage(b.Language)), nil
+	case *notion.EquationBlock:
+		return "$$\n" + b.Expression + "\n$$", nil
+	case *notion.DividerBlock:
+		return "---", nil
+	case *notion.ImageBlock:
+		return fmt.Sprintf("![%v](%v)", escapeText(notion.PlainText(b.Caption)), escapeURL(fileURL(b.File, b.External))), nil
+	case *notion.VideoBlock:
+		return r.renderFileLink(b.Caption, b.File, b.External), nil
+	case *notion.AudioBlock:
+		return r.renderFileLink(b.Caption, b.File, b.External), nil
+	case *notion.FileBlock:
+		return r.renderFileLink(b.Caption, b.File, b.External), nil
+	case *notion.PDFBlock:
+		return r.renderFileLink(b.Caption, b.File, b.External), nil
+	case *notion.BookmarkBlock:
+		return r.renderLink(b.Caption, b.URL), nil
+	case *notion.EmbedBlock:
+		return r.renderLink(nil, b.URL), nil
+	case *notion.LinkPreviewBlock:
+		return r.renderLink(nil, b.URL), nil
+	case *notion.ChildPageBlock:
+		return fmt.Sprintf("[%v](%v)", escapeText(b.Title), escapeURL(r.pageURL(b.ID()))), nil
+	case *notion.ChildDatabaseBlock:
+		return fmt.Sprintf("[%v](%v)", escapeText(b.Title), escapeURL(r.pageURL(b.ID()))), nil
+	case *notion.LinkToPageBlock:
+		id := b.PageID
+		if b.Type == notion.LinkToPageTypeDatabaseID {
+			id = b.DatabaseID
+		}
+		url := r.pageURL(id)
+		return fmt.Sprintf("[%v](%v)", escapeText(url), escapeURL(url)), nil
+	case *notion.TableBlock:
+		return r.renderTable(b), nil
+	case *notion.ColumnListBlock:
+		columns := make([]notion.Block, len(b.Children))
+		for i := range b.Children {
+			columns[i] = &b.Children[i]
+		}
+		return r.renderBlocks(columns)
+	case *notion.ColumnBlock:
+		return r.renderBlocks(b.Children)
+	case *notion.SyncedBlock:
+		return r.renderBlocks(b.Children)
+	default:
+		if r.UnsupportedBlock != nil {
+			return r.UnsupportedBlock(block)
+		}
+		return "", nil
+	}
+}
+
+// withChildren appends rendered children to content, indented with indent.
+func (r *Renderer) withChildren(content string, children []notion.Block, indent string) (string, error) {
+	if len(children) == 0 {
+		return content, nil
+	}
+
+	md, err := r.renderBlocks(children)
+	if err != nil {
+		return "", err
+	}
+	if md == "" {
+		return content, nil
+	}
+	if content == "" {
+		return prefixLines(md, indent, ""), nil
+	}
+
+	return content + "\n\n" + prefixLines(md, indent, ""), nil
+}
+
+func (r *Renderer) renderHeading(level int, richText []notion.RichText, children []notion.Block) (string, error) {
+	text := strings.ReplaceAll(r.renderRichText(richText, false), "\n", " ")
+	heading := strings.Repeat("#", level) + " " + text
+
+	// Toggleable headings can have children, rendered after the heading.
+	return r.withChildren(heading, children, "")
+}
+
+func (r *Renderer) renderListItem(marker, text string, children []notion.Block) (string, error) {
+	indent := strings.Repeat(" ", len(marker))
+	item := marker + prefixRest(text, indent)
+
+	if len(children) == 0 {
+		return item, nil
+	}
+
+	md, err := r.renderBlocks(children)
+	if err != nil {
+		return "", err
+	}
+	if md == "" {
+		return item, nil
+	}
+
+	// Nested lists directly follow the item's text. Other children, e.g.
+	// paragraphs, are separated by a blank line, or they'd continue the text.
+	if isListItem(children[0]) {
+		return item + "\n" + prefixLines(md, indent, ""), nil
+	}
+
+	return item + "\n\n" + prefixLines(md, indent, ""), nil
+}
+
+func isListItem(block notion.Block) bool {
+	switch block.(type) {
+	case *notion.BulletedListItemBlock, *notion.NumberedListItemBlock, *notion.ToDoBlock:
+		return true
+	}
+	return false
+}
+
+func (r *Renderer) renderDetails(summary string, children []notion.Block) (string, error) {
+	md, err := r.renderBlocks(children)
+	if err != nil {
+		return "", err
+	}
+
+	details := "<details>\n<summary>" + strings.ReplaceAll(summary, "\n", " ") + "</summary>\n\n"
+	if md != "" {
+		details += md + "\n\n"
+	}
+
+	return details + "</details>", nil
+}
+
+func (r *Renderer) renderFileLink(caption []notion.RichText, file *notion.FileFile, external *notion.FileExternal) string {
+	url := fileURL(file, external)
+	text := r.renderRichText(caption, false)
+	if text == "" {
+		text = escapeText(fileName(url))
+	}
+
+	return fmt.Sprintf("[%v](%v)", text, escapeURL(url))
+}
+
+func (r *Renderer) renderLink(caption []notion.RichText, url string) string {
+	text := r.renderRichText(caption, false)
+	if text == "" {
+		text = escapeText(url)
+	}
+
+	return fmt.Sprintf("[%v](%v)", text, escapeURL(url))
+}
+
+func (r *Renderer) renderTable(table *notion.TableBlock) string {
+	var rows [][]string
+	for _, child := range table.Children {
+		row, ok := child.(*notion.TableRowBlock)
+		if !ok {
+			continue
+		}
+
+		cells := make([]string, table.TableWidth)
+		for i := range cells {
+			if i < len(row.Cells) {
+				cell := r.renderRichText(row.Cells[i], false)
+				cell = strings.ReplaceAll(cell, "\n", "<br>")
+				cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+			}
+		}
+		rows = append(rows, cells)
+	}
+
+	if table.TableWidth == 0 {
+		return ""
+	}
+
+	// GFM tables require a header row, so an empty one is used when the
+	// table has no column header.
+	header := make([]string, table.TableWidth)
+	if table.HasColumnHeader && len(rows) > 0 {
+		header, rows = rows[0], rows[1:]
+	}
+
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for _, cell := range cells {
+			sb.WriteString(" " + cell + " |")
+		}
+	}
+
+	writeRow(header)
+	sb.WriteString("\n|")
+	sb.WriteString(strings.Repeat(" --- |", table.TableWidth))
+	for _, row := range rows {
+		sb.WriteString("\n")
+		writeRow(row)
+	}
+
+	return sb.String()
+}
+
+func (r *Renderer) pageURL(id string) string {
+	if r.PageURL != nil {
+		return r.PageURL(id)
+	}
+	return "https://www.notion.so/" + strings.ReplaceAll(id, "-", "")
+}
+
+// renderRichText renders rich text as inline Markdown. When hardBreaks is
+// true, newlines are rendered as hard line breaks.
+func (r *Renderer) renderRichText(richText []notion.RichText, hardBreaks bool) string {
+	var sb strings.Builder
+
+	for _, rt := range mergeRichText(richText) {
+		sb.WriteString(r.renderRichTextItem(rt))
+	}
+
+	md := sb.String()
+	if hardBreaks {
+		md = strings.ReplaceAll(md, "\n", "\\\n")
+	}
+
+	return md
+}
+
+func (r *Renderer) renderRichTextItem(rt notion.RichText) string {
+	text := richTextContent(rt)
+	annotations := notion.Annotations{}
+	if rt.Annotations != nil {
+		annotations = *rt.Annotations
+	}
+
+	var md string
+
+	switch {
+	case rt.Type == notion.RichTextTypeEquation || (rt.Type == "" && rt.Equation != nil):
+		md = "$" + text + "$"
+	case annotations.Code:
+		md = codeSpan(text)
+	default:
+		md = escapeText(text)
+	}
+
+	if md == "" {
+		return ""
+	}
+
+	leading, md, trailing := splitSpace(md)
+	if md == "" {
+		return leading + trailing
+	}
+
+	if annotations.Strikethrough {
+		md = "~~" + md + "~~"
+	}
+	if annotations.Underline {
+		md = "<u>" + md + "</u>"
+	}
+	if annotations.Italic {
+		md = "*" + md + "*"
+	}
+	if annotations.Bold {
+		md = "**" + md + "**"
+	}
+
+	if rt.Mention != nil {
+		if r.Mention != nil {
+			if mention, ok := r.Mention(*rt.Mention, md); ok {
+				return leading + mention + trailing
+			}
+		}
+		md = r.renderMention(*rt.Mention, md, rt.HRef)
+	} else if url := richTextLink(rt); url != "" {
+		md = "[" + md + "](" + escapeURL(url) + ")"
+	}
+
+	return leading + md + trailing
+}
+
+func (r *Renderer) renderMention(mention notion.Mention, text string, href *string) string {
+	switch {
+	case mention.Page != nil:
+		return "[" + text + "](" + escapeURL(r.pageURL(mention.Page.ID)) + ")"
+	case mention.Database != nil:
+		return "[" + text + "](" + escapeURL(r.pageURL(mention.Database.ID)) + ")"
+	case mention.LinkPreview != nil:
+		return "[" + text + "](" + escapeURL(mention.LinkPreview.URL) + ")"
+	case href != nil && *href != "":
+		return "[" + text + "](" + escapeURL(*href) + ")"
+	default:
+		return text
+	}
+}
+
+// mergeRichText merges adjacent text items with equal annotations and links,
+// to prevent output like `**foo****bar**`.
+func mergeRichText(richText []notion.RichText) []notion.RichText {
+	merged := make([]notion.RichText, 0, len(richText))
+
+	for _, rt := range richText {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if isPlainTextItem(*last) && isPlainTextItem(rt) &&
+				annotationsEqual(last.Annotations, rt.Annotations) &&
+				richTextLink(*last) == richTextLink(rt) {
+				content := richTextContent(*last) + richTextContent(rt)
+				*last = notion.RichText{
+					Type:        notion.RichTextTypeText,
+					Annotations: last.Annotations,
+					PlainText:   content,
+					HRef:        last.HRef,
+					Text:        &notion.Text{Content: content, Link: last.Text.Link},
+				}
+				continue
+			}
+		}
+		merged = append(merged, rt)
+	}
+
+	return merged
+}
+
+func isPlainTextItem(rt notion.RichText) bool {
+	return rt.Text != nil && rt.Mention == nil && rt.Equation == nil
+}
+
+func annotationsEqual(a, b *notion.Annotations) bool {
+	var aa, bb notion.Annotations
+	if a != nil {
+		aa = *a
+	}
+	if b != nil {
+		bb = *b
+	}
+	aa.Color, bb.Color = "", ""
+
+	return aa == bb
+}
+
+func richTextLink(rt notion.RichText) string {
+	if rt.Text != nil && rt.Text.Link != nil {
+		return rt.Text.Link.URL
+	}
+	if rt.HRef != nil {
+		return *rt.HRef
+	}
+	return ""
+}
+
+// richTextContent returns the text of a rich text item. The plain text is
+// only set for rich text returned by the API, so for rich text that's built
+// by hand the text content or equation expression is used.
+func richTextContent(rt notion.RichText) string {
+	switch {
+	case rt.Text != nil:
+		return rt.Text.Content
+	case rt.Equation != nil:
+		return rt.Equation.Expression
+	default:
+		return rt.PlainText
+	}
+}
+
+func splitSpace(s string) (leading, trimmed, trailing string) {
+	trimmed = strings.TrimLeft(s, " \t\n")
+	leading = s[:len(s)-len(trimmed)]
+	trimmed2 := strings.TrimRight(trimmed, " \t\n")
+	trailing = trimmed[len(trimmed2):]
+
+	return leading, trimmed2, trailing
+}
+
+var (
+	markdownEscaper  = strings.NewReplacer(`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `<`, `\<`, `>`, `\>`, `~`, `\~`, `$`, `\$`)
+	blockStartRegexp = regexp.MustCompile(`(?m)^(\s*)([#+\-]|\d+\.)`)
+	backtickRegexp   = regexp.MustCompile("`+")
+)
+
+// escapeText escapes characters that have a special meaning in Markdown.
+func escapeText(s string) string {
+	s = markdownEscaper.Replace(s)
+
+	// Prevent text at the start of a line from being parsed as a heading or
+	// list item.
+	return blockStartRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		trimmed := strings.TrimLeft(m, " \t")
+		prefix := m[:len(m)-len(trimmed)]
+		if strings.HasSuffix(trimmed, ".") {
+			return prefix + trimmed[:len(trimmed)-1] + `\.`
+		}
+		return prefix + `\` + trimmed
+	})
+}
+
+func escapeURL(url string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(url)
+}
+
+// codeSpan returns s as code span, using a backtick string that is longer than
+// any backtick string in s.
+func codeSpan(s string) string {
+	fence := strings.Repeat("`", longestBacktickRun(s)+1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
+
+func renderCode(code, language string) string {
+	n := longestBacktickRun(code) + 1
+	if n < 3 {
+		n = 3
+	}
+	fence := strings.Repeat("`", n)
+
+	return fence + language + "\n" + code + "\n" + fence
+}
+
+func longestBacktickRun(s string) int {
+	longest := 0
+	for _, run := range backtickRegexp.FindAllString(s, -1) {
+		if len(run) > longest {
+			longest = len(run)
+		}
+	}
+	return longest
+}
+
+func codeLanguage(language *string) string {
+	if language == nil || *language == "plain text" {
+		return ""
+	}
+	return strings.ReplaceAll(*language, " ", "-")
+}
+
+func fileURL(file *notion.FileFile, external *notion.FileExternal) string {
+	switch {
+	case file != nil:
+		return file.URL
+	case external != nil:
+		return external.URL
+	default:
+		return ""
+	}
+}
+
+func fileName(url string) string {
+	name := url
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, "/"); i >= 0 && i < len(name)-1 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// prefixLines prefixes every line of s. Empty lines get emptyPrefix.
+func prefixLines(s, prefix, emptyPrefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = emptyPrefix
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// prefixRest prefixes all lines but the first of s.
+func prefixRest(s, prefix string) string {
+	first, rest, ok := strings.Cut(s, "\n")
+	if !ok {
+		return s
+	}
+	return first + "\n" + prefixLines(rest, prefix, "")
+}
@@ -0,0 +1,226 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/markdown"
+	"github.com/google/go-cmp/cmp"
+)
+
+func text(content string) notion.RichText {
+	return notion.RichText{Type: notion.RichTextTypeText, Text: &notion.Text{Content: content}}
+}
+
+func annotated(content string, annotations notion.Annotations) notion.RichText {
+	rt := text(content)
+	rt.Annotations = &annotations
+	return rt
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		blocks []notion.Block
+		expMD  string
+	}{
+		{
+			name: "headings and paragraphs",
+			blocks: []notion.Block{
+				&notion.Heading1Block{RichText: []notion.RichText{text("Title")}},
+				&notion.ParagraphBlock{RichText: []notion.RichText{text("Hello "), text("world"), text(".")}},
+				&notion.Heading2Block{RichText: []notion.RichText{text("Sub")}},
+				&notion.Heading3Block{RichText: []notion.RichText{text("Sub sub")}},
+			},
+			expMD: "# Title\n\nHello world.\n\n## Sub\n\n### Sub sub\n",
+		},
+		{
+			name: "rich text annotations and links",
+			blocks: []notion.Block{
+				&notion.ParagraphBlock{RichText: []notion.RichText{
+					annotated("bold ", notion.Annotations{Bold: true}),
+					annotated("italic", notion.Annotations{Italic: true}),
+					text(", "),
+					annotated("strike", notion.Annotations{Strikethrough: true}),
+					text(", "),
+					annotated("a `b`", notion.Annotations{Code: true}),
+					text(", "),
+					annotated("under", notion.Annotations{Underline: true}),
+					text(", "),
+					{
+						Type: notion.RichTextTypeText,
+						Text: &notion.Text{Content: "link", Link: &notion.Link{URL: "https://example.com"}},
+					},
+					text(", "),
+					{Type: notion.RichTextTypeEquation, Equation: &notion.Equation{Expression: "e=mc^2"}},
+					text(" *_[x]"),
+				}},
+			},
+			expMD: "**bold** *italic*, ~~strike~~, `` a `b` ``, <u>under</u>, [link](https://example.com), $e=mc^2$ \\*\\_\\[x\\]\n",
+		},
+		{
+			name: "mentions",
+			blocks: []notion.Block{
+				&notion.ParagraphBlock{RichText: []notion.RichText{
+					{
+						Type:      notion.RichTextTypeMention,
+						Mention:   &notion.Mention{Type: notion.MentionTypePage, Page: &notion.ID{ID: "a1b2-c3"}},
+						PlainText: "Some page",
+					},
+					text(" by "),
+					{
+						Type:      notion.RichTextTypeMention,
+						Mention:   &notion.Mention{Type: notion.MentionTypeUser, User: &notion.User{Name: "John"}},
+						PlainText: "@John",
+					},
+				}},
+			},
+			expMD: "[Some page](https://www.notion.so/a1b2c3) by @John\n",
+		},
+		{
+			name: "lists and to dos",
+			blocks: []notion.Block{
+				&notion.BulletedListItemBlock{
+					RichText: []notion.RichText{text("One")},
+					Children: []notion.Block{
+						&notion.BulletedListItemBlock{RichText: []notion.RichText{text("Nested")}},
+					},
+				},
+				&notion.BulletedListItemBlock{RichText: []notion.RichText{text("Two")}},
+				&notion.NumberedListItemBlock{RichText: []notion.RichText{text("First")}},
+				&notion.NumberedListItemBlock{
+					RichText: []notion.RichText{text("Second")},
+					Children: []notion.Block{
+						&notion.NumberedListItemBlock{RichText: []notion.RichText{text("Nested")}},
+					},
+				},
+				&notion.ToDoBlock{RichText: []notion.RichText{text("Done")}, Checked: notion.BoolPtr(true)},
+				&notion.ToDoBlock{RichText: []notion.RichText{text("Todo")}},
+			},
+			expMD: "- One\n  - Nested\n- Two\n\n1. First\n2. Second\n   1. Nested\n\n- [x] Done\n- [ ] Todo\n",
+		},
+		{
+			name: "toggle, quote and callout",
+			blocks: []notion.Block{
+				&notion.ToggleBlock{
+					RichText: []notion.RichText{text("More")},
+					Children: []notion.Block{
+						&notion.ParagraphBlock{RichText: []notion.RichText{text("Hidden")}},
+					},
+				},
+				&notion.QuoteBlock{RichText: []notion.RichText{text("Line 1\nLine 2")}},
+				&notion.CalloutBlock{
+					RichText: []notion.RichText{text("Note")},
+					Icon:     &notion.Icon{Type: notion.IconTypeEmoji, Emoji: notion.StringPtr("💡")},
+				},
+			},
+			expMD: "<details>\n<summary>More</summary>\n\nHidden\n\n</details>\n\n> Line 1\\\n> Line 2\n\n> 💡 Note\n",
+		},
+		{
+			name: "code, equation and divider",
+			blocks: []notion.Block{
+				&notion.CodeBlock{
+					RichText: []notion.RichText{text("fmt.Println(\"```\")")},
+					Language: notion.StringPtr("go"),
+				},
+				&notion.EquationBlock{Expression: "x^2"},
+				&notion.DividerBlock{},
+			},
+			expMD: "````go\nfmt.Println(\"```\")\n````\n\n$$\nx^2\n$$\n\n---\n",
+		},
+		{
+			name: "table",
+			blocks: []notion.Block{
+				&notion.TableBlock{
+					TableWidth:      2,
+					HasColumnHeader: true,
+					Children: []notion.Block{
+						&notion.TableRowBlock{Cells: [][]notion.RichText{{text("Name")}, {text("Value")}}},
+						&notion.TableRowBlock{Cells: [][]notion.RichText{{text("a|b")}, {text("1")}}},
+					},
+				},
+			},
+			expMD: "| Name | Value |\n| --- | --- |\n| a\\|b | 1 |\n",
+		},
+		{
+			name: "files and links",
+			blocks: []notion.Block{
+				&notion.ImageBlock{
+					Type:     notion.FileTypeExternal,
+					External: &notion.FileExternal{URL: "https://example.com/image.png"},
+					Caption:  []notion.RichText{text("An image")},
+				},
+				&notion.FileBlock{
+					Type:     notion.FileTypeExternal,
+					External: &notion.FileExternal{URL: "https://example.com/report.pdf?x=1"},
+				},
+				&notion.BookmarkBlock{URL: "https://example.com"},
+			},
+			expMD: "![An image](https://example.com/image.png)\n\n[report.pdf](https://example.com/report.pdf?x=1)\n\n[https://example.com](https://example.com)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			md, err := markdown.Render(tt.blocks)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.expMD, md); diff != "" {
+				t.Fatalf("markdown not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestRendererHooks(t *testing.T) {
+	t.Parallel()
+
+	r := markdown.Renderer{
+		UnsupportedBlock: func(block notion.Block) (string, error) {
+			return "<!-- unsupported -->", nil
+		},
+		Mention: func(mention notion.Mention, text string) (string, bool) {
+			if mention.User == nil {
+				return "", false
+			}
+			return "<@" + mention.User.ID + ">", true
+		},
+		PageURL: func(id string) string {
+			return "/pages/" + id
+		},
+	}
+
+	blocks := []notion.Block{
+		&notion.TableOfContentsBlock{},
+		&notion.ParagraphBlock{RichText: []notion.RichText{
+			{
+				Type:      notion.RichTextTypeMention,
+				Mention:   &notion.Mention{Type: notion.MentionTypeUser, User: &notion.User{BaseUser: notion.BaseUser{ID: "u1"}}},
+				PlainText: "@John",
+			},
+			text(" "),
+			{
+				Type:      notion.RichTextTypeMention,
+				Mention:   &notion.Mention{Type: notion.MentionTypePage, Page: &notion.ID{ID: "p1"}},
+				PlainText: "Page",
+			},
+		}},
+	}
+
+	md, err := r.Render(blocks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := "<!-- unsupported -->\n\n<@u1> [Page](/pages/p1)\n"
+	if diff := cmp.Diff(exp, md); diff != "" {
+		t.Fatalf("markdown not equal (-exp, +got):\n%v", diff)
+	}
+}
@@ -0,0 +1,116 @@
+package notion
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Doer sends a request to the Notion API. Middleware wraps a Doer to run code
+// around every API call made by a client, see WithMiddleware.
+type Doer interface {
+	Do(req *Request) (*Response, error)
+}
+
+// DoerFunc is an adapter to allow the use of ordinary functions as a Doer.
+type DoerFunc func(req *Request) (*Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *Request) (*Response, error) {
+	return f(req)
+}
+
+// Middleware returns a Doer that wraps next, e.g. to add tracing spans,
+// metrics or audit logging around API calls.
+type Middleware func(next Doer) Doer
+
+// Request is a call to the Notion API, as seen by middleware.
+type Request struct {
+	// Operation is the name of the client method that made the call, e.g.
+	// "QueryDatabase". It's stable, so it can be used to label metrics.
+	Operation string
+
+	// ResourceID is the ID of the database, page, block or user the call is
+	// made for. It's empty for calls that don't target an existing resource,
+	// e.g. "CreatePage" or "Search".
+	ResourceID string
+
+	// HTTPRequest is the outgoing HTTP request. Middleware can replace it,
+	// e.g. to add headers.
+	HTTPRequest *http.Request
+}
+
+// Response is the result of a call to the Notion API, as seen by middleware.
+type Response struct {
+	// HTTPResponse is the HTTP response of the (last) attempt. Its body can
+	// be read by middleware, as long as it's replaced with an unread copy.
+	HTTPResponse *http.Response
+
+	// Err is the decoded error of a non-2xx response, or nil.
+	Err *APIError
+
+	// Latency is the duration of the call, including retries and time spent
+	// waiting on the client's rate limiter.
+	Latency time.Duration
+}
+
+// WithMiddleware adds middleware to the client. The first middleware is the
+// outermost one, i.e. it's called first for requests and last for responses.
+// Middleware sees the decoded API error and latency of every call; transport
+// errors (e.g. timeouts) are returned by the wrapped Doer as is.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// do sends an HTTP request for the client method `operation` through the
+// middleware chain of the client.
+func (c *Client) do(req *http.Request, operation, resourceID string) (*http.Response, error) {
+	if len(c.middleware) == 0 {
+		return c.send(req)
+	}
+
+	var doer Doer = DoerFunc(c.doRequest)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		doer = c.middleware[i](doer)
+	}
+
+	res, err := doer.Do(&Request{
+		Operation:   operation,
+		ResourceID:  resourceID,
+		HTTPRequest: req,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.HTTPResponse, nil
+}
+
+// doRequest is the innermost Doer of the middleware chain.
+func (c *Client) doRequest(req *Request) (*Response, error) {
+	start := time.Now()
+
+	res, err := c.send(req.HTTPRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &Response{HTTPResponse: res}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		b, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		resp.Err = newAPIError(res, b)
+	}
+
+	resp.Latency = time.Since(start)
+
+	return resp, nil
+}
@@ -0,0 +1,125 @@
+package notion_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	type call struct {
+		Operation  string
+		ResourceID string
+		Method     string
+		Path       string
+		Status     int
+		ErrCode    string
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace-Id") != "trace-1" {
+			t.Errorf("expected trace header to be set by middleware")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/pages/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"Could not find page."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"object":"list","results":[],"has_more":false}`))
+	}))
+	defer srv.Close()
+
+	var (
+		calls []call
+		order []string
+	)
+
+	recorder := func(next notion.Doer) notion.Doer {
+		return notion.DoerFunc(func(req *notion.Request) (*notion.Response, error) {
+			order = append(order, "recorder")
+			res, err := next.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			if res.Latency <= 0 {
+				t.Errorf("expected latency to be set")
+			}
+			c := call{
+				Operation:  req.Operation,
+				ResourceID: req.ResourceID,
+				Method:     req.HTTPRequest.Method,
+				Path:       req.HTTPRequest.URL.Path,
+				Status:     res.HTTPResponse.StatusCode,
+			}
+			if res.Err != nil {
+				c.ErrCode = res.Err.Code
+			}
+			calls = append(calls, c)
+			return res, nil
+		})
+	}
+	tracer := func(next notion.Doer) notion.Doer {
+		return notion.DoerFunc(func(req *notion.Request) (*notion.Response, error) {
+			order = append(order, "tracer")
+			req.HTTPRequest.Header.Set("X-Trace-Id", "trace-1")
+			return next.Do(req)
+		})
+	}
+
+	client := notion.NewClient("secret-api-key",
+		notion.WithBaseURL(srv.URL),
+		notion.WithMiddleware(recorder),
+		notion.WithMiddleware(tracer),
+	)
+
+	if _, err := client.QueryDatabase(context.Background(), "db-id", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Search(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := client.FindPageByID(context.Background(), "missing")
+	if !errors.Is(err, notion.ErrObjectNotFound) {
+		t.Fatalf("expected error to wrap notion.ErrObjectNotFound, got: %v", err)
+	}
+
+	expCalls := []call{
+		{
+			Operation:  "QueryDatabase",
+			ResourceID: "db-id",
+			Method:     http.MethodPost,
+			Path:       "/databases/db-id/query",
+			Status:     http.StatusOK,
+		},
+		{
+			Operation: "Search",
+			Method:    http.MethodPost,
+			Path:      "/search",
+			Status:    http.StatusOK,
+		},
+		{
+			Operation:  "FindPageByID",
+			ResourceID: "missing",
+			Method:     http.MethodGet,
+			Path:       "/pages/missing",
+			Status:     http.StatusNotFound,
+			ErrCode:    "object_not_found",
+		},
+	}
+	if diff := cmp.Diff(expCalls, calls); diff != "" {
+		t.Fatalf("calls not equal (-exp, +got):\n%v", diff)
+	}
+
+	expOrder := []string{"recorder", "tracer", "recorder", "tracer", "recorder", "tracer"}
+	if diff := cmp.Diff(expOrder, order); diff != "" {
+		t.Fatalf("middleware order not equal (-exp, +got):\n%v", diff)
+	}
+}
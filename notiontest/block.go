@@ -0,0 +1,399 @@
+package notiontest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// Maximum number of nesting levels of block children in a single request.
+// See: https://developers.notion.com/reference/request-limits#limits-for-property-values
+const maxBlockDepth = 2
+
+// Fields of block objects that aren't block type specific.
+var blockMetadataFields = map[string]bool{
+	"object":           true,
+	"id":               true,
+	"parent":           true,
+	"created_time":     true,
+	"created_by":       true,
+	"last_edited_time": true,
+	"last_edited_by":   true,
+	"has_children":     true,
+	"archived":         true,
+	"type":             true,
+}
+
+// parsedBlock is a block from a request body, with its nested children.
+type parsedBlock struct {
+	dto      notion.BlockDTO
+	children []parsedBlock
+}
+
+func (s *Server) findBlock(r *http.Request, params []string) (interface{}, error) {
+	dto, ok := s.renderBlock(params[0])
+	if !ok {
+		return nil, errNotFound("block", params[0])
+	}
+
+	return dto, nil
+}
+
+func (s *Server) updateBlock(r *http.Request, params []string) (interface{}, error) {
+	var fields map[string]json.RawMessage
+	if err := decodeBody(r, &fields); err != nil {
+		return nil, err
+	}
+
+	var archived *bool
+	if raw, ok := fields["archived"]; ok {
+		if err := json.Unmarshal(raw, &archived); err != nil {
+			return nil, errValidation("body failed validation: body.archived should be a boolean.")
+		}
+		delete(fields, "archived")
+	}
+
+	if !s.exists(params[0]) {
+		return nil, errNotFound("block", params[0])
+	}
+	if s.isArchived(params[0]) && (archived == nil || *archived) {
+		return nil, errValidation("Can't edit block that is archived. You must unarchive the block before editing.")
+	}
+
+	if stored, ok := s.blocks[params[0]]; ok && len(fields) > 0 {
+		raw, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := parseBlock(raw, maxBlockDepth)
+		if err != nil {
+			return nil, err
+		}
+		if parsed.dto.Type != stored.Type {
+			return nil, errValidation("Block type %v does not match existing block type %v.", parsed.dto.Type, stored.Type)
+		}
+
+		updated := parsed.dto
+		updated.ID = stored.ID
+		updated.Parent = stored.Parent
+		updated.CreatedTime = stored.CreatedTime
+		updated.CreatedBy = stored.CreatedBy
+		updated.Archived = stored.Archived
+		*stored = updated
+	}
+
+	if archived != nil {
+		s.setArchived(params[0], *archived)
+	}
+	s.touch(params[0])
+
+	dto, _ := s.renderBlock(params[0])
+
+	return dto, nil
+}
+
+func (s *Server) deleteBlock(r *http.Request, params []string) (interface{}, error) {
+	if !s.exists(params[0]) {
+		return nil, errNotFound("block", params[0])
+	}
+
+	s.setArchived(params[0], true)
+	s.touch(params[0])
+
+	dto, _ := s.renderBlock(params[0])
+
+	return dto, nil
+}
+
+func (s *Server) findBlockChildren(r *http.Request, params []string) (interface{}, error) {
+	if _, ok := s.pages[params[0]]; !ok {
+		if _, ok := s.blocks[params[0]]; !ok {
+			return nil, errNotFound("block", params[0])
+		}
+	}
+
+	startCursor, pageSize, err := paginationQuery(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var children []notion.BlockDTO
+	for _, id := range s.children[params[0]] {
+		if s.isArchived(id) {
+			continue
+		}
+		dto, _ := s.renderBlock(id)
+		children = append(children, dto)
+	}
+
+	start, end, next, err := paginate(len(children), func(i int) string { return children[i].ID }, startCursor, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := list{Object: "list", Results: []interface{}{}, NextCursor: next, HasMore: next != nil}
+	for _, dto := range children[start:end] {
+		resp.Results = append(resp.Results, dto)
+	}
+
+	return resp, nil
+}
+
+func (s *Server) appendBlockChildren(r *http.Request, params []string) (interface{}, error) {
+	if _, ok := s.pages[params[0]]; !ok {
+		if _, ok := s.blocks[params[0]]; !ok {
+			return nil, errNotFound("block", params[0])
+		}
+	}
+	if s.isArchived(params[0]) {
+		return nil, errValidation("Can't edit block that is archived. You must unarchive the block before editing.")
+	}
+
+	var body struct {
+		Children []json.RawMessage `json:"children"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+	if body.Children == nil {
+		return nil, errValidation("body failed validation: body.children should be defined, instead was `undefined`.")
+	}
+
+	children, err := parseBlocks(body.Children)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := list{Object: "list", Results: []interface{}{}}
+	for _, id := range s.addBlocks(params[0], children) {
+		dto, _ := s.renderBlock(id)
+		resp.Results = append(resp.Results, dto)
+	}
+	s.touch(params[0])
+
+	return resp, nil
+}
+
+// parseBlocks parses the blocks of a request body, including nested children.
+func parseBlocks(raws []json.RawMessage) ([]parsedBlock, error) {
+	return parseBlocksDepth(raws, 1)
+}
+
+func parseBlocksDepth(raws []json.RawMessage, depth int) ([]parsedBlock, error) {
+	if len(raws) > maxPageSize {
+		return nil, errValidation("body failed validation: body.children.length should be ≤ `%v`, instead was `%v`.", maxPageSize, len(raws))
+	}
+
+	blocks := make([]parsedBlock, len(raws))
+	for i, raw := range raws {
+		block, err := parseBlock(raw, depth)
+		if err != nil {
+			return nil, err
+		}
+		blocks[i] = block
+	}
+
+	return blocks, nil
+}
+
+// parseBlock parses a single block. Its nested children are split off from
+// the block type specific field, so only the block itself is stored in the
+// block DTO.
+func parseBlock(raw json.RawMessage, depth int) (parsedBlock, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil || fields == nil {
+		return parsedBlock{}, errValidation("body failed validation: block should be an object.")
+	}
+
+	typ, _ := fields["type"].(string)
+	if typ == "" {
+		for key := range fields {
+			if blockMetadataFields[key] {
+				continue
+			}
+			if typ != "" {
+				return parsedBlock{}, errValidation("body failed validation: block should have exactly one block type field.")
+			}
+			typ = key
+		}
+	}
+
+	payload, ok := fields[typ].(map[string]interface{})
+	if !ok {
+		return parsedBlock{}, errValidation("body failed validation: block.%v should be an object.", typ)
+	}
+
+	var block parsedBlock
+
+	if children, ok := payload["children"]; ok {
+		if depth >= maxBlockDepth {
+			return parsedBlock{}, errValidation("body failed validation: block children can be nested at most %v levels deep.", maxBlockDepth)
+		}
+
+		b, err := json.Marshal(children)
+		if err != nil {
+			return parsedBlock{}, err
+		}
+		var raws []json.RawMessage
+		if err := json.Unmarshal(b, &raws); err != nil {
+			return parsedBlock{}, errValidation("body failed validation: block.%v.children should be an array.", typ)
+		}
+		if block.children, err = parseBlocksDepth(raws, depth+1); err != nil {
+			return parsedBlock{}, err
+		}
+		delete(payload, "children")
+	}
+
+	normalizeRichTextFields(payload)
+
+	b, err := json.Marshal(map[string]interface{}{
+		"type": typ,
+		typ:    payload,
+	})
+	if err != nil {
+		return parsedBlock{}, err
+	}
+	if err := json.Unmarshal(b, &block.dto); err != nil {
+		return parsedBlock{}, errValidation("body failed validation: block.%v is invalid: %v", typ, err)
+	}
+
+	switch block.dto.Block().(type) {
+	case *notion.UnsupportedBlock, *notion.ChildPageBlock, *notion.ChildDatabaseBlock:
+		return parsedBlock{}, errValidation("body failed validation: block type %v is not supported.", typ)
+	}
+
+	return block, nil
+}
+
+// addBlocks stores blocks as children of a page or block, and returns the IDs
+// of the top level blocks.
+func (s *Server) addBlocks(parentID string, blocks []parsedBlock) []string {
+	parent := notion.Parent{Type: notion.ParentTypeBlock, BlockID: parentID}
+	if _, ok := s.pages[parentID]; ok {
+		parent = notion.Parent{Type: notion.ParentTypePage, PageID: parentID}
+	}
+
+	now := s.timestamp()
+	author := notion.BlockUser{Object: "user", ID: s.bot.ID}
+
+	ids := make([]string, len(blocks))
+	for i, block := range blocks {
+		dto := block.dto
+		dto.ID = s.newID()
+		dto.Parent = &parent
+		dto.CreatedTime = &now
+		dto.CreatedBy = &author
+		dto.LastEditedTime = &now
+		dto.LastEditedBy = &author
+
+		s.blocks[dto.ID] = &dto
+		s.children[parentID] = append(s.children[parentID], dto.ID)
+		ids[i] = dto.ID
+
+		s.addBlocks(dto.ID, block.children)
+	}
+
+	return ids
+}
+
+// renderBlock returns the block object of a block, page or database, for use
+// in responses.
+func (s *Server) renderBlock(id string) (notion.BlockDTO, bool) {
+	var dto notion.BlockDTO
+
+	if block, ok := s.blocks[id]; ok {
+		dto = *block
+	} else if page, ok := s.pages[id]; ok && page.Parent.Type != notion.ParentTypeDatabase {
+		dto = notion.BlockDTO{
+			Type:      notion.BlockTypeChildPage,
+			ChildPage: &notion.ChildPageBlock{Title: pageTitle(page)},
+			Archived:  page.Archived,
+		}
+		dto.Parent = &page.Parent
+		dto.CreatedTime = &page.CreatedTime
+		dto.CreatedBy = &notion.BlockUser{Object: "user", ID: page.CreatedBy.ID}
+		dto.LastEditedTime = &page.LastEditedTime
+		dto.LastEditedBy = &notion.BlockUser{Object: "user", ID: page.LastEditedBy.ID}
+	} else if db, ok := s.databases[id]; ok {
+		dto = notion.BlockDTO{
+			Type:          notion.BlockTypeChildDatabase,
+			ChildDatabase: &notion.ChildDatabaseBlock{Title: notion.PlainText(db.Title)},
+			Archived:      db.Archived,
+		}
+		dto.Parent = &db.Parent
+		dto.CreatedTime = &db.CreatedTime
+		dto.CreatedBy = &notion.BlockUser{Object: "user", ID: db.CreatedBy.ID}
+		dto.LastEditedTime = &db.LastEditedTime
+		dto.LastEditedBy = &notion.BlockUser{Object: "user", ID: db.LastEditedBy.ID}
+	} else {
+		return notion.BlockDTO{}, false
+	}
+
+	dto.ID = id
+	dto.HasChildren = false
+	for _, childID := range s.children[id] {
+		if !s.isArchived(childID) {
+			dto.HasChildren = true
+			break
+		}
+	}
+
+	return dto, true
+}
+
+// exists reports whether a block, page or database exists.
+func (s *Server) exists(id string) bool {
+	_, isBlock := s.blocks[id]
+	_, isPage := s.pages[id]
+	_, isDatabase := s.databases[id]
+
+	return isBlock || isPage || isDatabase
+}
+
+func (s *Server) isArchived(id string) bool {
+	if block, ok := s.blocks[id]; ok {
+		return block.Archived
+	}
+	if page, ok := s.pages[id]; ok {
+		return page.Archived
+	}
+	if db, ok := s.databases[id]; ok {
+		return db.Archived
+	}
+	return false
+}
+
+func (s *Server) setArchived(id string, archived bool) {
+	if block, ok := s.blocks[id]; ok {
+		block.Archived = archived
+	}
+	if page, ok := s.pages[id]; ok {
+		page.Archived = archived
+	}
+	if db, ok := s.databases[id]; ok {
+		db.Archived = archived
+	}
+}
+
+// touch updates the last edited time and author of a block, page or database.
+func (s *Server) touch(id string) {
+	now := s.timestamp()
+
+	if block, ok := s.blocks[id]; ok {
+		block.LastEditedTime = &now
+		block.LastEditedBy = &notion.BlockUser{Object: "user", ID: s.bot.ID}
+	}
+	if page, ok := s.pages[id]; ok {
+		page.LastEditedTime = now
+		page.LastEditedBy = &notion.BaseUser{ID: s.bot.ID}
+	}
+	if db, ok := s.databases[id]; ok {
+		db.LastEditedTime = now
+		db.LastEditedBy = s.botRef()
+	}
+}
@@ -0,0 +1,96 @@
+package notiontest
+
+import (
+	"net/http"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+func (s *Server) createComment(r *http.Request, params []string) (interface{}, error) {
+	var body struct {
+		Parent       *notion.Parent    `json:"parent"`
+		DiscussionID string            `json:"discussion_id"`
+		RichText     []notion.RichText `json:"rich_text"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+
+	if len(body.RichText) == 0 {
+		return nil, errValidation("body failed validation: body.rich_text should be defined, instead was `undefined`.")
+	}
+
+	now := s.timestamp()
+	comment := notion.Comment{
+		ID:             s.newID(),
+		RichText:       normalizeRichText(body.RichText),
+		CreatedTime:    now,
+		LastEditedTime: now,
+		CreatedBy:      s.botRef(),
+	}
+
+	switch {
+	case body.Parent != nil && body.DiscussionID != "":
+		return nil, errValidation("body failed validation. Fix one: body.parent should be not present, or body.discussion_id should be not present.")
+	case body.Parent != nil:
+		page, ok := s.pages[body.Parent.PageID]
+		if !ok || page.Archived {
+			return nil, errNotFound("page", body.Parent.PageID)
+		}
+		comment.Parent = notion.Parent{Type: notion.ParentTypePage, PageID: page.ID}
+		comment.DiscussionID = s.newID()
+	case body.DiscussionID != "":
+		var found bool
+		for _, c := range s.comments {
+			if c.DiscussionID == body.DiscussionID {
+				comment.Parent = c.Parent
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errNotFound("discussion", body.DiscussionID)
+		}
+		comment.DiscussionID = body.DiscussionID
+	default:
+		return nil, errValidation("body failed validation. Fix one: body.parent should be defined, or body.discussion_id should be defined.")
+	}
+
+	s.comments = append(s.comments, comment)
+
+	return object{"comment", comment}, nil
+}
+
+func (s *Server) findComments(r *http.Request, params []string) (interface{}, error) {
+	blockID := r.URL.Query().Get("block_id")
+	if blockID == "" {
+		return nil, errValidation("block_id should be defined, instead was `undefined`.")
+	}
+	if !s.exists(blockID) {
+		return nil, errNotFound("block", blockID)
+	}
+
+	startCursor, pageSize, err := paginationQuery(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []notion.Comment
+	for _, comment := range s.comments {
+		if comment.Parent.PageID == blockID || comment.Parent.BlockID == blockID {
+			comments = append(comments, comment)
+		}
+	}
+
+	start, end, next, err := paginate(len(comments), func(i int) string { return comments[i].ID }, startCursor, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := list{Object: "list", Results: []interface{}{}, NextCursor: next, HasMore: next != nil}
+	for _, comment := range comments[start:end] {
+		resp.Results = append(resp.Results, object{"comment", comment})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,322 @@
+package notiontest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+func (s *Server) findDatabase(r *http.Request, params []string) (interface{}, error) {
+	db, ok := s.databases[params[0]]
+	if !ok {
+		return nil, errNotFound("database", params[0])
+	}
+
+	return object{"database", db}, nil
+}
+
+func (s *Server) createDatabase(r *http.Request, params []string) (interface{}, error) {
+	var body struct {
+		Parent      notion.Parent             `json:"parent"`
+		Title       []notion.RichText         `json:"title"`
+		Description []notion.RichText         `json:"description"`
+		Properties  notion.DatabaseProperties `json:"properties"`
+		Icon        *notion.Icon              `json:"icon"`
+		Cover       *notion.Cover             `json:"cover"`
+		IsInline    bool                      `json:"is_inline"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+
+	if body.Parent.PageID == "" {
+		return nil, errValidation("body failed validation: body.parent.page_id should be defined, instead was `undefined`.")
+	}
+	if _, ok := s.pages[body.Parent.PageID]; !ok {
+		return nil, errNotFound("page", body.Parent.PageID)
+	}
+
+	titleProps := 0
+	for _, prop := range body.Properties {
+		if prop.Type == notion.DBPropTypeTitle {
+			titleProps++
+		}
+	}
+	if titleProps != 1 {
+		return nil, errValidation("Title is not provided")
+	}
+
+	now := s.timestamp()
+	db := &notion.Database{
+		ID:             s.newID(),
+		CreatedTime:    now,
+		CreatedBy:      s.botRef(),
+		LastEditedTime: now,
+		LastEditedBy:   s.botRef(),
+		Title:          normalizeRichText(body.Title),
+		Description:    normalizeRichText(body.Description),
+		Properties:     make(notion.DatabaseProperties, len(body.Properties)),
+		Parent:         notion.Parent{Type: notion.ParentTypePage, PageID: body.Parent.PageID},
+		Icon:           body.Icon,
+		Cover:          body.Cover,
+		IsInline:       body.IsInline,
+	}
+	db.URL = objectURL(db.ID)
+
+	for name, prop := range body.Properties {
+		db.Properties[name] = s.newDatabaseProperty(name, prop)
+	}
+
+	s.databases[db.ID] = db
+	s.objects = append(s.objects, db.ID)
+	s.children[body.Parent.PageID] = append(s.children[body.Parent.PageID], db.ID)
+
+	return object{"database", db}, nil
+}
+
+func (s *Server) updateDatabase(r *http.Request, params []string) (interface{}, error) {
+	db, ok := s.databases[params[0]]
+	if !ok {
+		return nil, errNotFound("database", params[0])
+	}
+
+	var body notion.UpdateDatabaseParams
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+
+	// Validate all property changes before applying any of them.
+	for key, prop := range body.Properties {
+		name, current, exists := findProperty(db.Properties, key)
+		switch {
+		case prop == nil && !exists:
+			return nil, errValidation("%v is not a property that exists.", key)
+		case prop == nil && current.Type == notion.DBPropTypeTitle:
+			return nil, errValidation("Cannot delete the title property %v.", name)
+		case prop != nil && !exists && prop.Type == "":
+			return nil, errValidation("%v is not a property that exists.", key)
+		case prop != nil && exists && prop.Type != "" && prop.Type != current.Type &&
+			(prop.Type == notion.DBPropTypeTitle || current.Type == notion.DBPropTypeTitle):
+			return nil, errValidation("Cannot change the type of title property %v.", name)
+		}
+	}
+
+	for key, prop := range body.Properties {
+		name, current, exists := findProperty(db.Properties, key)
+
+		switch {
+		case prop == nil:
+			delete(db.Properties, name)
+			s.updateDatabasePages(db.ID, func(props notion.DatabasePageProperties) {
+				delete(props, name)
+			})
+		case !exists:
+			db.Properties[key] = s.newDatabaseProperty(key, *prop)
+			s.updateDatabasePages(db.ID, func(props notion.DatabasePageProperties) {
+				props[key] = emptyPageProperty(db.Properties[key])
+			})
+		default:
+			newName := name
+			if prop.Name != "" {
+				newName = prop.Name
+			}
+
+			updated := current
+			if prop.Type != "" && prop.Type != current.Type {
+				updated = *prop
+				updated.ID = current.ID
+			}
+			if prop.Select != nil && updated.Type == notion.DBPropTypeSelect {
+				updated.Select = prop.Select
+			}
+			if prop.MultiSelect != nil && updated.Type == notion.DBPropTypeMultiSelect {
+				updated.MultiSelect = prop.MultiSelect
+			}
+			for _, meta := range []*notion.SelectMetadata{updated.Select, updated.MultiSelect} {
+				if meta == nil {
+					continue
+				}
+				for i := range meta.Options {
+					s.initSelectOption(&meta.Options[i])
+				}
+			}
+			updated.Name = newName
+
+			delete(db.Properties, name)
+			db.Properties[newName] = updated
+
+			s.updateDatabasePages(db.ID, func(props notion.DatabasePageProperties) {
+				value := props[name]
+				if updated.Type != current.Type {
+					value = emptyPageProperty(updated)
+				}
+				delete(props, name)
+				props[newName] = value
+			})
+		}
+	}
+
+	if body.Title != nil {
+		db.Title = normalizeRichText(body.Title)
+	}
+	if body.Description != nil {
+		db.Description = normalizeRichText(body.Description)
+	}
+	if body.Icon != nil {
+		db.Icon = body.Icon
+	}
+	if body.Cover != nil {
+		db.Cover = body.Cover
+	}
+	if body.Archived != nil {
+		db.Archived = *body.Archived
+	}
+	if body.IsInline != nil {
+		db.IsInline = *body.IsInline
+	}
+
+	db.LastEditedTime = s.timestamp()
+	db.LastEditedBy = s.botRef()
+
+	return object{"database", db}, nil
+}
+
+func (s *Server) queryDatabase(r *http.Request, params []string) (interface{}, error) {
+	db, ok := s.databases[params[0]]
+	if !ok {
+		return nil, errNotFound("database", params[0])
+	}
+
+	var query notion.DatabaseQuery
+	if err := decodeBody(r, &query); err != nil {
+		return nil, err
+	}
+
+	var pages []notion.Page
+	for _, id := range s.objects {
+		page, ok := s.pages[id]
+		if !ok || page.Archived || page.Parent.DatabaseID != db.ID {
+			continue
+		}
+		pages = append(pages, s.renderPage(page))
+	}
+
+	pages, err := notion.QueryPages(pages, query, db.Properties, s.now())
+	if err != nil {
+		var qerr *notion.QueryError
+		if errors.As(err, &qerr) {
+			return nil, errValidation("%v", qerr.Message)
+		}
+		return nil, err
+	}
+
+	start, end, next, err := paginate(len(pages), func(i int) string { return pages[i].ID }, query.StartCursor, query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := list{Object: "list", Results: []interface{}{}, NextCursor: next, HasMore: next != nil}
+	for _, page := range pages[start:end] {
+		resp.Results = append(resp.Results, object{"page", page})
+	}
+
+	return resp, nil
+}
+
+// newDatabaseProperty returns a database property for a create or update
+// request, with its ID and name set.
+func (s *Server) newDatabaseProperty(name string, prop notion.DatabaseProperty) notion.DatabaseProperty {
+	prop.Name = name
+	if prop.Type == notion.DBPropTypeTitle {
+		prop.ID = "title"
+	} else {
+		s.lastID++
+		prop.ID = fmt.Sprintf("prop%v", s.lastID)
+	}
+
+	for _, meta := range []*notion.SelectMetadata{prop.Select, prop.MultiSelect} {
+		if meta == nil {
+			continue
+		}
+		for i := range meta.Options {
+			s.initSelectOption(&meta.Options[i])
+		}
+	}
+
+	// Like in the Notion API, status properties get default options and
+	// groups.
+	if prop.Type == notion.DBPropTypeStatus {
+		prop.Status = s.defaultStatusMetadata()
+	}
+
+	return prop
+}
+
+func (s *Server) initSelectOption(option *notion.SelectOptions) {
+	if option.ID == "" {
+		option.ID = s.newID()
+	}
+	if option.Color == "" {
+		option.Color = notion.ColorDefault
+	}
+}
+
+func (s *Server) defaultStatusMetadata() *notion.StatusMetadata {
+	meta := &notion.StatusMetadata{}
+
+	groups := []struct {
+		name, option string
+		color        notion.Color
+	}{
+		{"To-do", "Not started", notion.ColorDefault},
+		{"In progress", "In progress", notion.ColorBlue},
+		{"Complete", "Done", notion.ColorGreen},
+	}
+	for _, group := range groups {
+		option := notion.SelectOptions{ID: s.newID(), Name: group.option, Color: group.color}
+		meta.Options = append(meta.Options, option)
+		meta.Groups = append(meta.Groups, notion.StatusGroup{
+			ID:        s.newID(),
+			Name:      group.name,
+			Color:     group.color,
+			OptionIDs: []string{option.ID},
+		})
+	}
+
+	return meta
+}
+
+// updateDatabasePages calls fn with the properties of all pages in a database.
+func (s *Server) updateDatabasePages(databaseID string, fn func(props notion.DatabasePageProperties)) {
+	for _, page := range s.pages {
+		if page.Parent.DatabaseID == databaseID {
+			fn(page.Properties.(notion.DatabasePageProperties))
+		}
+	}
+}
+
+// findProperty finds a database property by name or by ID, as both can be
+// used to refer to properties.
+func findProperty(props notion.DatabaseProperties, nameOrID string) (string, notion.DatabaseProperty, bool) {
+	if prop, ok := props[nameOrID]; ok {
+		return nameOrID, prop, true
+	}
+
+	// Iterate in a stable order, in case of duplicate IDs.
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if props[name].ID == nameOrID {
+			return name, props[name], true
+		}
+	}
+
+	return "", notion.DatabaseProperty{}, false
+}
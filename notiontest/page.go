@@ -0,0 +1,489 @@
+package notiontest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// Property types whose values are computed, and can't be set using the API.
+var readOnlyPropTypes = map[notion.DatabasePropertyType]bool{
+	notion.DBPropTypeFormula:        true,
+	notion.DBPropTypeRollup:         true,
+	notion.DBPropTypeCreatedTime:    true,
+	notion.DBPropTypeCreatedBy:      true,
+	notion.DBPropTypeLastEditedTime: true,
+	notion.DBPropTypeLastEditedBy:   true,
+}
+
+func (s *Server) findPage(r *http.Request, params []string) (interface{}, error) {
+	page, ok := s.pages[params[0]]
+	if !ok {
+		return nil, errNotFound("page", params[0])
+	}
+
+	return object{"page", s.renderPage(page)}, nil
+}
+
+func (s *Server) createPage(r *http.Request, params []string) (interface{}, error) {
+	var body struct {
+		Parent     notion.Parent     `json:"parent"`
+		Properties json.RawMessage   `json:"properties"`
+		Children   []json.RawMessage `json:"children"`
+		Icon       *notion.Icon      `json:"icon"`
+		Cover      *notion.Cover     `json:"cover"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+
+	children, err := parseBlocks(body.Children)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *notion.Page
+
+	switch {
+	case body.Parent.DatabaseID != "":
+		db, ok := s.databases[body.Parent.DatabaseID]
+		if !ok || db.Archived {
+			return nil, errNotFound("database", body.Parent.DatabaseID)
+		}
+
+		var values notion.DatabasePageProperties
+		if err := json.Unmarshal(body.Properties, &values); err != nil {
+			return nil, errValidation("body failed validation: body.properties should be an object of property values.")
+		}
+
+		props := make(notion.DatabasePageProperties, len(db.Properties))
+		for name, prop := range db.Properties {
+			props[name] = emptyPageProperty(prop)
+		}
+		if err := s.setPageProperties(db, props, values); err != nil {
+			return nil, err
+		}
+
+		page = s.newPage(notion.Parent{Type: notion.ParentTypeDatabase, DatabaseID: db.ID})
+		page.Properties = props
+	case body.Parent.PageID != "":
+		parent, ok := s.pages[body.Parent.PageID]
+		if !ok || parent.Archived {
+			return nil, errNotFound("page", body.Parent.PageID)
+		}
+
+		title, err := parseTitleProperties(body.Properties)
+		if err != nil {
+			return nil, err
+		}
+
+		page = s.newPage(notion.Parent{Type: notion.ParentTypePage, PageID: parent.ID})
+		page.Properties = notion.DatabasePageProperties{
+			"title": {
+				ID:    "title",
+				Type:  notion.DBPropTypeTitle,
+				Title: normalizeRichText(title),
+			},
+		}
+		s.children[parent.ID] = append(s.children[parent.ID], page.ID)
+	default:
+		return nil, errValidation("body failed validation: body.parent should be defined, instead was `undefined`.")
+	}
+
+	page.Icon = body.Icon
+	page.Cover = body.Cover
+
+	s.addBlocks(page.ID, children)
+
+	return object{"page", s.renderPage(page)}, nil
+}
+
+func (s *Server) updatePage(r *http.Request, params []string) (interface{}, error) {
+	page, ok := s.pages[params[0]]
+	if !ok {
+		return nil, errNotFound("page", params[0])
+	}
+
+	var body notion.UpdatePageParams
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+
+	if page.Archived && (body.Archived == nil || *body.Archived) {
+		return nil, errValidation("Can't edit block that is archived. You must unarchive the block before editing.")
+	}
+
+	props := page.Properties.(notion.DatabasePageProperties)
+
+	if body.DatabasePageProperties != nil {
+		if db, ok := s.databases[page.Parent.DatabaseID]; ok {
+			if err := s.setPageProperties(db, props, body.DatabasePageProperties); err != nil {
+				return nil, err
+			}
+		} else {
+			for key, value := range body.DatabasePageProperties {
+				if key != "title" {
+					return nil, errValidation("%v is not a property that exists.", key)
+				}
+				props["title"] = notion.DatabasePageProperty{
+					ID:    "title",
+					Type:  notion.DBPropTypeTitle,
+					Title: normalizeRichText(value.Title),
+				}
+			}
+		}
+	}
+
+	if body.Archived != nil {
+		page.Archived = *body.Archived
+	}
+	if body.Icon != nil {
+		page.Icon = body.Icon
+	}
+	if body.Cover != nil {
+		page.Cover = body.Cover
+	}
+
+	s.touch(page.ID)
+
+	return object{"page", s.renderPage(page)}, nil
+}
+
+func (s *Server) findPageProperty(r *http.Request, params []string) (interface{}, error) {
+	page, ok := s.pages[params[0]]
+	if !ok {
+		return nil, errNotFound("page", params[0])
+	}
+
+	var (
+		prop  notion.DatabasePageProperty
+		found bool
+	)
+	for _, p := range s.renderPage(page).Properties.(notion.DatabasePageProperties) {
+		if p.ID == params[1] {
+			prop, found = p, true
+			break
+		}
+	}
+	if !found {
+		return nil, errNotFound("property", params[1])
+	}
+
+	// Values of these property types are paginated lists of property items,
+	// which each hold a single value.
+	var items []interface{}
+	item := func(value interface{}) {
+		items = append(items, map[string]interface{}{
+			"object":          "property_item",
+			"id":              prop.ID,
+			"type":            prop.Type,
+			string(prop.Type): value,
+		})
+	}
+
+	switch prop.Type {
+	case notion.DBPropTypeTitle:
+		for _, rt := range prop.Title {
+			item(rt)
+		}
+	case notion.DBPropTypeRichText:
+		for _, rt := range prop.RichText {
+			item(rt)
+		}
+	case notion.DBPropTypeRelation:
+		for _, relation := range prop.Relation {
+			item(relation)
+		}
+	case notion.DBPropTypePeople:
+		for _, user := range prop.People {
+			item(object{"user", user})
+		}
+	default:
+		prop.Name = ""
+		return object{"property_item", prop}, nil
+	}
+
+	startCursor, pageSize, err := paginationQuery(r)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, next, err := paginate(len(items), strconv.Itoa, startCursor, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return struct {
+		list
+		Type         string      `json:"type"`
+		PropertyItem interface{} `json:"property_item"`
+	}{
+		list: list{
+			Object:     "list",
+			Results:    append([]interface{}{}, items[start:end]...),
+			NextCursor: next,
+			HasMore:    next != nil,
+		},
+		Type: "property_item",
+		PropertyItem: map[string]interface{}{
+			"id":              prop.ID,
+			"type":            prop.Type,
+			"next_url":        nil,
+			string(prop.Type): struct{}{},
+		},
+	}, nil
+}
+
+// parseTitleProperties parses the properties of a page with a page parent,
+// which only has a title. The title value is either an array of rich text, or
+// a property value object.
+func parseTitleProperties(raw json.RawMessage) ([]notion.RichText, error) {
+	var props map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &props); err != nil {
+		return nil, errValidation("body failed validation: body.properties should be an object.")
+	}
+
+	for key := range props {
+		if key != "title" {
+			return nil, errValidation("body failed validation. Fix one: body.properties.%v should be not present, instead was present.", key)
+		}
+	}
+
+	var title []notion.RichText
+	if err := json.Unmarshal(props["title"], &title); err == nil {
+		return title, nil
+	}
+
+	var prop notion.DatabasePageProperty
+	if err := json.Unmarshal(props["title"], &prop); err != nil {
+		return nil, errValidation("body failed validation: body.properties.title should be an array of rich text.")
+	}
+
+	return prop.Title, nil
+}
+
+func (s *Server) newPage(parent notion.Parent) *notion.Page {
+	now := s.timestamp()
+	page := &notion.Page{
+		ID:             s.newID(),
+		CreatedTime:    now,
+		CreatedBy:      &notion.BaseUser{ID: s.bot.ID},
+		LastEditedTime: now,
+		LastEditedBy:   &notion.BaseUser{ID: s.bot.ID},
+		Parent:         parent,
+	}
+	page.URL = objectURL(page.ID)
+
+	s.pages[page.ID] = page
+	s.objects = append(s.objects, page.ID)
+
+	return page
+}
+
+// renderPage returns a copy of a page for use in responses, with the values of
+// timestamp and author properties set.
+func (s *Server) renderPage(page *notion.Page) notion.Page {
+	rendered := *page
+
+	stored := page.Properties.(notion.DatabasePageProperties)
+	props := make(notion.DatabasePageProperties, len(stored))
+	for name, prop := range stored {
+		switch prop.Type {
+		case notion.DBPropTypeCreatedTime:
+			prop.CreatedTime = &page.CreatedTime
+		case notion.DBPropTypeCreatedBy:
+			prop.CreatedBy = s.findUserByID(page.CreatedBy.ID)
+		case notion.DBPropTypeLastEditedTime:
+			prop.LastEditedTime = &page.LastEditedTime
+		case notion.DBPropTypeLastEditedBy:
+			prop.LastEditedBy = s.findUserByID(page.LastEditedBy.ID)
+		}
+		props[name] = prop
+	}
+	rendered.Properties = props
+
+	return rendered
+}
+
+// setPageProperties validates property values against the database schema,
+// and stores them in props by property name.
+func (s *Server) setPageProperties(db *notion.Database, props, values notion.DatabasePageProperties) error {
+	for key := range values {
+		if _, _, ok := findProperty(db.Properties, key); !ok {
+			return errValidation("%v is not a property that exists.", key)
+		}
+	}
+
+	for key, value := range values {
+		name, schema, _ := findProperty(db.Properties, key)
+
+		if value.Type != "" && value.Type != schema.Type {
+			return errValidation("%v is expected to be %v.", name, schema.Type)
+		}
+		if typ := valueType(value); typ != "" && typ != schema.Type {
+			return errValidation("%v is expected to be %v.", name, schema.Type)
+		}
+		if readOnlyPropTypes[schema.Type] {
+			return errValidation("Cannot update property %v of type %v.", name, schema.Type)
+		}
+
+		value.ID = schema.ID
+		value.Type = schema.Type
+		value.Name = ""
+		value.Title = normalizeRichText(value.Title)
+		value.RichText = normalizeRichText(value.RichText)
+
+		var err error
+		switch schema.Type {
+		case notion.DBPropTypeSelect:
+			if value.Select != nil {
+				var option notion.SelectOptions
+				option, err = s.selectOption(db, name, schema.Select, *value.Select)
+				value.Select = &option
+			}
+		case notion.DBPropTypeMultiSelect:
+			for i, option := range value.MultiSelect {
+				value.MultiSelect[i], err = s.selectOption(db, name, schema.MultiSelect, option)
+				if err != nil {
+					break
+				}
+			}
+		case notion.DBPropTypeStatus:
+			if value.Status != nil {
+				var option notion.SelectOptions
+				option, err = statusOption(name, schema.Status, *value.Status)
+				value.Status = &option
+			}
+		case notion.DBPropTypePeople:
+			for i, user := range value.People {
+				found := s.findUserByID(user.ID)
+				if found == nil {
+					return errValidation("User with ID %v does not exist.", user.ID)
+				}
+				value.People[i] = *found
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		props[name] = value
+	}
+
+	return nil
+}
+
+// selectOption finds an option of a select property by ID or by name. Like in
+// the Notion API, missing options are added to the database schema.
+func (s *Server) selectOption(db *notion.Database, propName string, meta *notion.SelectMetadata, option notion.SelectOptions) (notion.SelectOptions, error) {
+	if meta == nil {
+		meta = &notion.SelectMetadata{}
+	}
+
+	for _, existing := range meta.Options {
+		if (option.ID != "" && existing.ID == option.ID) || (option.ID == "" && existing.Name == option.Name) {
+			return existing, nil
+		}
+	}
+
+	if option.ID != "" || option.Name == "" {
+		return notion.SelectOptions{}, errValidation("Invalid select option for property %v.", propName)
+	}
+
+	s.initSelectOption(&option)
+	meta.Options = append(meta.Options, option)
+
+	prop := db.Properties[propName]
+	if prop.Type == notion.DBPropTypeSelect {
+		prop.Select = meta
+	} else {
+		prop.MultiSelect = meta
+	}
+	db.Properties[propName] = prop
+
+	return option, nil
+}
+
+// statusOption finds an option of a status property by ID or by name. Unlike
+// select options, status options are never added implicitly.
+func statusOption(propName string, meta *notion.StatusMetadata, option notion.SelectOptions) (notion.SelectOptions, error) {
+	if meta != nil {
+		for _, existing := range meta.Options {
+			if (option.ID != "" && existing.ID == option.ID) || (option.ID == "" && existing.Name == option.Name) {
+				return existing, nil
+			}
+		}
+	}
+
+	return notion.SelectOptions{}, errValidation("Invalid status option. Status option %q does not exist for property %v.", option.Name, propName)
+}
+
+// valueType returns the property type that matches the value field that is
+// set, or an empty string if no value is set.
+func valueType(value notion.DatabasePageProperty) notion.DatabasePropertyType {
+	switch {
+	case value.Title != nil:
+		return notion.DBPropTypeTitle
+	case value.RichText != nil:
+		return notion.DBPropTypeRichText
+	case value.Number != nil:
+		return notion.DBPropTypeNumber
+	case value.Select != nil:
+		return notion.DBPropTypeSelect
+	case value.MultiSelect != nil:
+		return notion.DBPropTypeMultiSelect
+	case value.Date != nil:
+		return notion.DBPropTypeDate
+	case value.Formula != nil:
+		return notion.DBPropTypeFormula
+	case value.Relation != nil:
+		return notion.DBPropTypeRelation
+	case value.Rollup != nil:
+		return notion.DBPropTypeRollup
+	case value.People != nil:
+		return notion.DBPropTypePeople
+	case value.Files != nil:
+		return notion.DBPropTypeFiles
+	case value.Checkbox != nil:
+		return notion.DBPropTypeCheckbox
+	case value.URL != nil:
+		return notion.DBPropTypeURL
+	case value.Email != nil:
+		return notion.DBPropTypeEmail
+	case value.PhoneNumber != nil:
+		return notion.DBPropTypePhoneNumber
+	case value.Status != nil:
+		return notion.DBPropTypeStatus
+	case value.CreatedTime != nil:
+		return notion.DBPropTypeCreatedTime
+	case value.CreatedBy != nil:
+		return notion.DBPropTypeCreatedBy
+	case value.LastEditedTime != nil:
+		return notion.DBPropTypeLastEditedTime
+	case value.LastEditedBy != nil:
+		return notion.DBPropTypeLastEditedBy
+	}
+
+	return ""
+}
+
+// emptyPageProperty returns the value of a database property for pages that
+// don't have a value set.
+func emptyPageProperty(prop notion.DatabaseProperty) notion.DatabasePageProperty {
+	return notion.DatabasePageProperty{
+		ID:   prop.ID,
+		Type: prop.Type,
+	}
+}
+
+// pageTitle returns the plain text title of a page.
+func pageTitle(page *notion.Page) string {
+	for _, prop := range page.Properties.(notion.DatabasePageProperties) {
+		if prop.Type == notion.DBPropTypeTitle {
+			return notion.PlainText(prop.Title)
+		}
+	}
+	return ""
+}
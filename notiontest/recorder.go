@@ -0,0 +1,437 @@
+package notiontest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay serves responses from a cassette file, without making any
+	// network requests. Requests that don't match a recorded interaction fail.
+	ModeReplay Mode = iota
+
+	// ModeRecord forwards requests to the underlying transport, and records
+	// the interactions. Call Recorder.Save to write them to the cassette file.
+	ModeRecord
+)
+
+// redactedValue replaces the values of redacted request headers.
+const redactedValue = "[REDACTED]"
+
+// Request headers that are never written to cassettes.
+var redactedHeaders = []string{"Authorization"}
+
+// JSON body fields that are never written to cassettes, at any depth, e.g. the
+// access token in OAuth token responses.
+var redactedFields = []string{"access_token"}
+
+// Cassette holds the recorded interactions of a Recorder.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded HTTP request. The `Authorization` header and
+// `access_token` body fields are redacted.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded HTTP response. The `access_token` body fields
+// are redacted.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records interactions with the Notion
+// API to a cassette file, and replays them. It's used for deterministic
+// integration tests that don't need network access once recorded:
+//
+//	rec, err := notiontest.NewRecorder("testdata/pages.json", notiontest.ModeReplay, nil)
+//	if err != nil {
+//		// Handle error...
+//	}
+//	client := notion.NewClient(apiKey, notion.WithHTTPClient(rec.Client()))
+//
+// Requests are matched on method, path, query and body, where JSON bodies are
+// normalized, so that object key order and whitespace are insignificant. Every
+// recorded interaction is replayed at most once, in recorded order.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	redact func(*Interaction)
+
+	mu       sync.Mutex
+	cassette Cassette
+	replayed []bool
+}
+
+// RecorderOption configures a Recorder.
+type RecorderOption func(*Recorder)
+
+// WithRedact sets a function that is called with every interaction before
+// it's recorded, to remove sensitive data that isn't redacted by default. The
+// request and response can be modified in place. Request fields used for
+// matching (method, URL and body) should be left as is, or requests won't
+// match when replayed.
+func WithRedact(redact func(*Interaction)) RecorderOption {
+	return func(rec *Recorder) {
+		rec.redact = redact
+	}
+}
+
+// NewRecorder returns a new Recorder. In replay mode, the cassette is read
+// from path. In record mode, requests are sent using transport, or using
+// http.DefaultTransport if transport is nil.
+func NewRecorder(path string, mode Mode, transport http.RoundTripper, opts ...RecorderOption) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	rec := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: transport,
+	}
+
+	for _, opt := range opts {
+		opt(rec)
+	}
+
+	if mode == ModeReplay {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("notiontest: failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(b, &rec.cassette); err != nil {
+			return nil, fmt.Errorf("notiontest: failed to parse cassette %v: %w", path, err)
+		}
+		rec.replayed = make([]bool, len(rec.cassette.Interactions))
+	}
+
+	return rec, nil
+}
+
+// Client returns an HTTP client that uses the recorder as its transport, for
+// use with notion.WithHTTPClient.
+func (rec *Recorder) Client() *http.Client {
+	return &http.Client{Transport: rec}
+}
+
+// Cassette returns a copy of the recorded (or loaded) interactions.
+func (rec *Recorder) Cassette() Cassette {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return Cassette{
+		Interactions: append([]Interaction(nil), rec.cassette.Interactions...),
+	}
+}
+
+// Save writes the recorded interactions to the cassette file. It's a no-op in
+// replay mode.
+func (rec *Recorder) Save() error {
+	if rec.mode != ModeRecord {
+		return nil
+	}
+
+	rec.mu.Lock()
+	b, err := json.MarshalIndent(rec.cassette, "", "  ")
+	rec.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("notiontest: failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(rec.path), 0o755); err != nil {
+		return fmt.Errorf("notiontest: failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(rec.path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("notiontest: failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rec *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("notiontest: failed to read request body: %w", err)
+	}
+
+	if rec.mode == ModeReplay {
+		return rec.replay(req, body)
+	}
+
+	return rec.record(req, body)
+}
+
+func (rec *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	// A RoundTripper must not modify the request, so the (consumed) body is
+	// replaced on a clone.
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	res, err := rec.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("notiontest: failed to read response body: %w", err)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	header := req.Header.Clone()
+	for _, key := range redactedHeaders {
+		if header.Get(key) != "" {
+			header.Set(key, redactedValue)
+		}
+	}
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: header,
+			Body:   string(redactBody(body)),
+		},
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     res.Header.Clone(),
+			Body:       string(redactBody(resBody)),
+		},
+	}
+	if rec.redact != nil {
+		rec.redact(&interaction)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.cassette.Interactions = append(rec.cassette.Interactions, interaction)
+
+	return res, nil
+}
+
+func (rec *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	// Recorded bodies are redacted, so the request body must be too to match.
+	key, err := newMatchKey(req.Method, req.URL.String(), redactBody(body))
+	if err != nil {
+		return nil, err
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	for i, interaction := range rec.cassette.Interactions {
+		if rec.replayed[i] {
+			continue
+		}
+
+		recorded, err := newMatchKey(interaction.Request.Method, interaction.Request.URL, []byte(interaction.Request.Body))
+		if err != nil {
+			return nil, fmt.Errorf("notiontest: invalid interaction %v in cassette %v: %w", i, rec.path, err)
+		}
+		if recorded != key {
+			continue
+		}
+
+		rec.replayed[i] = true
+
+		resBody := interaction.Response.Body
+		return &http.Response{
+			Status:        fmt.Sprintf("%v %v", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader([]byte(resBody))),
+			ContentLength: int64(len(resBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, &UnmatchedRequestError{
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Body:     string(body),
+		Cassette: rec.path,
+	}
+}
+
+// UnmatchedRequestError is returned in replay mode for requests that don't
+// match any (remaining) recorded interaction.
+type UnmatchedRequestError struct {
+	Method   string
+	URL      string
+	Body     string
+	Cassette string
+}
+
+// Error implements `error`.
+func (err *UnmatchedRequestError) Error() string {
+	msg := fmt.Sprintf("notiontest: no recorded interaction in cassette %v matches request %v %v", err.Cassette, err.Method, err.URL)
+	if err.Body != "" {
+		msg += " with body " + err.Body
+	}
+	return msg
+}
+
+// matchKey is the normalized form of a request, used for matching requests
+// with recorded interactions.
+type matchKey struct {
+	method string
+	path   string
+	query  string
+	body   string
+}
+
+func newMatchKey(method, rawURL string, body []byte) (matchKey, error) {
+	req, err := http.NewRequest(method, rawURL, nil)
+	if err != nil {
+		return matchKey{}, err
+	}
+
+	return matchKey{
+		method: method,
+		path:   req.URL.Path,
+		// Encoding sorts the query params by key.
+		query: req.URL.Query().Encode(),
+		body:  normalizeBody(body),
+	}, nil
+}
+
+// normalizeBody returns the canonical encoding of a JSON body, with object
+// keys sorted and without insignificant whitespace. Bodies that aren't valid
+// JSON are returned as is.
+func normalizeBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return string(body)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+
+	return string(b)
+}
+
+// redactBody returns a JSON body with the values of redactedFields replaced.
+// Bodies without redacted fields, or that aren't valid JSON, are returned as
+// is.
+func redactBody(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+
+	if !redactValue(v) {
+		return body
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+
+	return b
+}
+
+// redactValue replaces the values of redactedFields in a decoded JSON value,
+// and reports whether any were replaced.
+func redactValue(v interface{}) bool {
+	redacted := false
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isRedactedField(key) {
+				v[key] = redactedValue
+				redacted = true
+				continue
+			}
+			if redactValue(value) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if redactValue(value) {
+				redacted = true
+			}
+		}
+	}
+
+	return redacted
+}
+
+func isRedactedField(key string) bool {
+	for _, field := range redactedFields {
+		if key == field {
+			return true
+		}
+	}
+	return false
+}
+
+// readBody reads and closes the body of a request. The request itself isn't
+// modified.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Body.Close(); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
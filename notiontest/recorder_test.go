@@ -0,0 +1,178 @@
+package notiontest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/notiontest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	srv := notiontest.NewServer()
+	root := srv.AddPage("Root")
+
+	// Record interactions with the server.
+	rec, err := notiontest.NewRecorder(path, notiontest.ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := notion.NewClient(notiontest.DefaultAPIKey,
+		notion.WithBaseURL(srv.URL+"/v1"),
+		notion.WithHTTPClient(rec.Client()),
+	)
+
+	expPage, err := client.FindPageByID(ctx, root.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Search(ctx, &notion.SearchOpts{Query: "root", PageSize: 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	srv.Close()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, []byte(notiontest.DefaultAPIKey)) {
+		t.Fatal("cassette contains the API key")
+	}
+
+	// Replay interactions, without the server.
+	rec, err = notiontest.NewRecorder(path, notiontest.ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client = notion.NewClient("other-api-key",
+		notion.WithBaseURL(srv.URL+"/v1"),
+		notion.WithHTTPClient(rec.Client()),
+	)
+
+	page, err := client.FindPageByID(ctx, root.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(expPage, page); diff != "" {
+		t.Fatalf("page not equal (-exp, +got):\n%v", diff)
+	}
+
+	// JSON bodies are matched regardless of key order and whitespace.
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/v1/search", strings.NewReader(`{ "page_size": 10, "query": "root" }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code %v", res.StatusCode)
+	}
+
+	// Interactions are replayed only once.
+	_, err = client.FindPageByID(ctx, root.ID)
+	var unmatchedErr *notiontest.UnmatchedRequestError
+	if !errors.As(err, &unmatchedErr) {
+		t.Fatalf("expected unmatched request error, got: %v", err)
+	}
+	if unmatchedErr.Method != http.MethodGet || !strings.HasSuffix(unmatchedErr.URL, "/v1/pages/"+root.ID) {
+		t.Fatalf("unexpected error: %v", unmatchedErr)
+	}
+}
+
+func TestRecorderMissingCassette(t *testing.T) {
+	t.Parallel()
+
+	_, err := notiontest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), notiontest.ModeReplay, nil)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not exist error, got: %v", err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestRecorderRedact(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Set-Cookie": []string{"session=secret-cookie"}},
+			Body:       io.NopCloser(strings.NewReader(`{"access_token": "secret-token", "bot_id": "bot-id"}`)),
+		}, nil
+	})
+
+	rec, err := notiontest.NewRecorder(path, notiontest.ModeRecord, transport,
+		notiontest.WithRedact(func(interaction *notiontest.Interaction) {
+			interaction.Response.Header.Del("Set-Cookie")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "https://api.notion.com/v1/oauth/token", strings.NewReader(`{"grant_type": "authorization_code"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := req.Body
+
+	res, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.Body.Close()
+
+	// The request is left as is.
+	if req.Body != body {
+		t.Fatal("request body was replaced")
+	}
+
+	if err := rec.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret-token", "secret-cookie"} {
+		if bytes.Contains(b, []byte(secret)) {
+			t.Fatalf("cassette contains %q", secret)
+		}
+	}
+
+	exp := notiontest.RecordedResponse{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       `{"access_token":"[REDACTED]","bot_id":"bot-id"}`,
+	}
+	if diff := cmp.Diff(exp, rec.Cassette().Interactions[0].Response); diff != "" {
+		t.Fatalf("response not equal (-exp, +got):\n%v", diff)
+	}
+}
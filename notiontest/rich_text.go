@@ -0,0 +1,88 @@
+package notiontest
+
+import "github.com/cryptowizard0/go-notion"
+
+// Block type specific fields that hold rich text, e.g. `caption` of images.
+var richTextFields = map[string]bool{
+	"rich_text": true,
+	"caption":   true,
+}
+
+// normalizeRichText sets the fields of rich text objects that are set by the
+// Notion API, but can be omitted in requests: `type`, `plain_text`, `href` and
+// `annotations`.
+func normalizeRichText(rts []notion.RichText) []notion.RichText {
+	if rts == nil {
+		return nil
+	}
+
+	normalized := make([]notion.RichText, len(rts))
+	for i, rt := range rts {
+		switch {
+		case rt.Text != nil:
+			rt.Type = notion.RichTextTypeText
+			rt.PlainText = rt.Text.Content
+			if rt.Text.Link != nil {
+				href := rt.Text.Link.URL
+				rt.HRef = &href
+			}
+		case rt.Equation != nil:
+			rt.Type = notion.RichTextTypeEquation
+			rt.PlainText = rt.Equation.Expression
+		case rt.Mention != nil:
+			rt.Type = notion.RichTextTypeMention
+		}
+		if rt.Annotations == nil {
+			rt.Annotations = &notion.Annotations{Color: notion.ColorDefault}
+		}
+		normalized[i] = rt
+	}
+
+	return normalized
+}
+
+// normalizeRichTextFields normalizes the rich text in the decoded JSON of a
+// block type specific field, including table row cells.
+func normalizeRichTextFields(payload map[string]interface{}) {
+	for key, value := range payload {
+		switch {
+		case richTextFields[key]:
+			if items, ok := value.([]interface{}); ok {
+				normalizeRichTextJSON(items)
+			}
+		case key == "cells":
+			cells, _ := value.([]interface{})
+			for _, cell := range cells {
+				if items, ok := cell.([]interface{}); ok {
+					normalizeRichTextJSON(items)
+				}
+			}
+		}
+	}
+}
+
+func normalizeRichTextJSON(items []interface{}) {
+	for _, item := range items {
+		rt, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if text, ok := rt["text"].(map[string]interface{}); ok {
+			rt["type"] = string(notion.RichTextTypeText)
+			rt["plain_text"] = text["content"]
+			if link, ok := text["link"].(map[string]interface{}); ok {
+				rt["href"] = link["url"]
+			}
+		} else if equation, ok := rt["equation"].(map[string]interface{}); ok {
+			rt["type"] = string(notion.RichTextTypeEquation)
+			rt["plain_text"] = equation["expression"]
+		} else if _, ok := rt["mention"]; ok {
+			rt["type"] = string(notion.RichTextTypeMention)
+		}
+
+		if _, ok := rt["annotations"]; !ok {
+			rt["annotations"] = map[string]interface{}{"color": string(notion.ColorDefault)}
+		}
+	}
+}
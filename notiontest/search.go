@@ -0,0 +1,87 @@
+package notiontest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+func (s *Server) search(r *http.Request, params []string) (interface{}, error) {
+	var opts notion.SearchOpts
+	if err := decodeBody(r, &opts); err != nil {
+		return nil, err
+	}
+
+	if opts.Filter != nil {
+		if opts.Filter.Property != "object" {
+			return nil, errValidation("body failed validation: body.filter.property should be `\"object\"`, instead was `%q`.", opts.Filter.Property)
+		}
+		if opts.Filter.Value != "page" && opts.Filter.Value != "database" {
+			return nil, errValidation("body failed validation: body.filter.value should be `\"page\"` or `\"database\"`, instead was `%q`.", opts.Filter.Value)
+		}
+	}
+
+	type result struct {
+		id             string
+		lastEditedTime time.Time
+		value          object
+	}
+
+	query := strings.ToLower(opts.Query)
+
+	var results []result
+	for _, id := range s.objects {
+		var (
+			res   result
+			title string
+		)
+
+		if page, ok := s.pages[id]; ok {
+			if page.Archived {
+				continue
+			}
+			res = result{id, page.LastEditedTime, object{"page", s.renderPage(page)}}
+			title = pageTitle(page)
+		} else if db, ok := s.databases[id]; ok {
+			if db.Archived {
+				continue
+			}
+			res = result{id, db.LastEditedTime, object{"database", db}}
+			title = notion.PlainText(db.Title)
+		}
+
+		if opts.Filter != nil && res.value.kind != opts.Filter.Value {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(title), query) {
+			continue
+		}
+
+		results = append(results, res)
+	}
+
+	// Results are sorted by last edited time, most recent first, unless the
+	// sort direction is ascending.
+	ascending := opts.Sort != nil && opts.Sort.Direction == notion.SortDirAsc
+	sort.SliceStable(results, func(i, j int) bool {
+		if ascending {
+			return results[i].lastEditedTime.Before(results[j].lastEditedTime)
+		}
+		return results[i].lastEditedTime.After(results[j].lastEditedTime)
+	})
+
+	start, end, next, err := paginate(len(results), func(i int) string { return results[i].id }, opts.StartCursor, opts.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := list{Object: "list", Results: []interface{}{}, NextCursor: next, HasMore: next != nil}
+	for _, res := range results[start:end] {
+		resp.Results = append(resp.Results, res.value)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,445 @@
+// Package notiontest provides an in-memory fake of the Notion API, for use in
+// tests.
+//
+//	srv := notiontest.NewServer()
+//	defer srv.Close()
+//
+//	root := srv.AddPage("Root")
+//	client := srv.Client()
+//
+// The server stores databases, pages, blocks, users and comments in memory. It
+// supports pagination cursors, archiving, database query filters and sorts,
+// and search. Failed requests get the same error codes as the Notion API
+// returns, so they can be matched using errors.Is, e.g. against
+// notion.ErrObjectNotFound.
+package notiontest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// DefaultAPIKey is the API key that is accepted by a server, unless another
+// key is set using WithAPIKey.
+const DefaultAPIKey = "secret_notiontest"
+
+// Maximum page size of paginated endpoints, and the default when no page size
+// is given.
+const maxPageSize = 100
+
+// Server is a fake Notion API server. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, of the form `http://ipaddr:port`
+	// with no trailing slash. API endpoints are served on `/v1`.
+	URL string
+
+	srv    *httptest.Server
+	apiKey string
+	now    func() time.Time
+	bot    notion.User
+
+	mu        sync.Mutex
+	lastID    int
+	users     []notion.User
+	databases map[string]*notion.Database
+	pages     map[string]*notion.Page
+	blocks    map[string]*notion.BlockDTO
+	children  map[string][]string
+	comments  []notion.Comment
+
+	// Creation order of pages and databases, used for search.
+	objects []string
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAPIKey sets the API key (bearer token) that the server accepts.
+func WithAPIKey(apiKey string) Option {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithClock overrides the function used for getting the current time, which
+// is used for timestamps of objects, and for relative date filters such as
+// `past_week`.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// NewServer starts and returns a new Server. The caller should call Close when
+// finished, to shut it down.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		apiKey:    DefaultAPIKey,
+		now:       time.Now,
+		databases: make(map[string]*notion.Database),
+		pages:     make(map[string]*notion.Page),
+		blocks:    make(map[string]*notion.BlockDTO),
+		children:  make(map[string][]string),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.bot = notion.User{
+		BaseUser: notion.BaseUser{ID: s.newID()},
+		Type:     notion.UserTypeBot,
+		Name:     "notiontest",
+		Bot: &notion.Bot{
+			Owner: notion.BotOwner{
+				Type:      notion.BotOwnerTypeWorkspace,
+				Workspace: true,
+			},
+		},
+	}
+	s.users = append(s.users, s.bot)
+
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a client that is configured to use the server, authorized
+// with the server's API key.
+func (s *Server) Client(opts ...notion.ClientOption) *notion.Client {
+	opts = append([]notion.ClientOption{
+		notion.WithBaseURL(s.URL + "/v1"),
+		notion.WithHTTPClient(s.srv.Client()),
+	}, opts...)
+
+	return notion.NewClient(s.apiKey, opts...)
+}
+
+// Bot returns the bot user of the API key, which is the author of all objects
+// that are created using the API.
+func (s *Server) Bot() notion.User {
+	return s.bot
+}
+
+// AddUser adds a user to the workspace. If the user has no ID, one is
+// generated. The added user is returned.
+func (s *Server) AddUser(user notion.User) notion.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = s.newID()
+	}
+	if user.Type == "" {
+		user.Type = notion.UserTypePerson
+	}
+	s.users = append(s.users, user)
+
+	return user
+}
+
+// AddPage adds a page with a workspace parent, which is something the API
+// doesn't allow for. Use it for creating a root page that databases and other
+// pages can be added to.
+func (s *Server) AddPage(title string) notion.Page {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page := s.newPage(notion.Parent{Type: notion.ParentTypeWorkspace, Workspace: true})
+	page.Properties = notion.DatabasePageProperties{
+		"title": {
+			ID:    "title",
+			Type:  notion.DBPropTypeTitle,
+			Title: normalizeRichText([]notion.RichText{{Text: &notion.Text{Content: title}}}),
+		},
+	}
+
+	return s.renderPage(page)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.apiKey {
+		writeError(w, errUnauthorized())
+		return
+	}
+	if r.Header.Get("Notion-Version") == "" {
+		writeError(w, &apiError{
+			status:  http.StatusBadRequest,
+			code:    "missing_version",
+			message: "Notion-Version header failed validation: Notion-Version header should be defined, instead was `undefined`.",
+		})
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, "/v1/") {
+		writeError(w, errInvalidRequestURL())
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+
+	for _, route := range routes {
+		params, ok := route.match(r.Method, path)
+		if !ok {
+			continue
+		}
+
+		s.mu.Lock()
+		v, err := route.handle(s, r, params)
+		s.mu.Unlock()
+
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, v)
+		return
+	}
+
+	writeError(w, errInvalidRequestURL())
+}
+
+type handlerFunc func(s *Server, r *http.Request, params []string) (interface{}, error)
+
+type route struct {
+	method  string
+	pattern []string
+	handle  handlerFunc
+}
+
+// routes are matched in order. A `*` pattern segment matches any path segment,
+// which is passed to the handler as a param.
+var routes = []route{
+	newRoute(http.MethodGet, "databases/*", (*Server).findDatabase),
+	newRoute(http.MethodPatch, "databases/*", (*Server).updateDatabase),
+	newRoute(http.MethodPost, "databases/*/query", (*Server).queryDatabase),
+	newRoute(http.MethodPost, "databases", (*Server).createDatabase),
+	newRoute(http.MethodGet, "pages/*", (*Server).findPage),
+	newRoute(http.MethodPatch, "pages/*", (*Server).updatePage),
+	newRoute(http.MethodGet, "pages/*/properties/*", (*Server).findPageProperty),
+	newRoute(http.MethodPost, "pages", (*Server).createPage),
+	newRoute(http.MethodGet, "blocks/*", (*Server).findBlock),
+	newRoute(http.MethodPatch, "blocks/*", (*Server).updateBlock),
+	newRoute(http.MethodDelete, "blocks/*", (*Server).deleteBlock),
+	newRoute(http.MethodGet, "blocks/*/children", (*Server).findBlockChildren),
+	newRoute(http.MethodPatch, "blocks/*/children", (*Server).appendBlockChildren),
+	newRoute(http.MethodGet, "users/me", (*Server).findCurrentUser),
+	newRoute(http.MethodGet, "users/*", (*Server).findUser),
+	newRoute(http.MethodGet, "users", (*Server).listUsers),
+	newRoute(http.MethodPost, "search", (*Server).search),
+	newRoute(http.MethodPost, "comments", (*Server).createComment),
+	newRoute(http.MethodGet, "comments", (*Server).findComments),
+}
+
+func newRoute(method, pattern string, handle handlerFunc) route {
+	return route{
+		method:  method,
+		pattern: strings.Split(pattern, "/"),
+		handle:  handle,
+	}
+}
+
+func (rt route) match(method, path string) ([]string, bool) {
+	if method != rt.method {
+		return nil, false
+	}
+
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	if len(segments) != len(rt.pattern) {
+		return nil, false
+	}
+
+	var params []string
+	for i, segment := range segments {
+		switch {
+		case rt.pattern[i] == "*" && segment != "":
+			params = append(params, segment)
+		case rt.pattern[i] != segment:
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+// apiError is an error response, encoded like the error responses of the
+// Notion API.
+// See: https://developers.notion.com/reference/errors
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (err *apiError) Error() string {
+	return err.message
+}
+
+func errUnauthorized() *apiError {
+	return &apiError{http.StatusUnauthorized, "unauthorized", "API token is invalid."}
+}
+
+func errInvalidRequestURL() *apiError {
+	return &apiError{http.StatusBadRequest, "invalid_request_url", "Invalid request URL."}
+}
+
+func errInvalidJSON() *apiError {
+	return &apiError{http.StatusBadRequest, "invalid_json", "Error parsing JSON body."}
+}
+
+func errValidation(format string, a ...interface{}) *apiError {
+	return &apiError{http.StatusBadRequest, "validation_error", fmt.Sprintf(format, a...)}
+}
+
+func errNotFound(object, id string) *apiError {
+	return &apiError{
+		status:  http.StatusNotFound,
+		code:    "object_not_found",
+		message: fmt.Sprintf("Could not find %v with ID: %v. Make sure the relevant pages and databases are shared with your integration.", object, id),
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		apiErr = &apiError{http.StatusInternalServerError, "internal_server_error", err.Error()}
+	}
+
+	writeJSON(w, apiErr.status, struct {
+		Object  string `json:"object"`
+		Status  int    `json:"status"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{"error", apiErr.status, apiErr.code, apiErr.message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// decodeBody decodes a JSON request body. An empty body is decoded as an empty
+// object, so handlers validate missing params alike.
+func decodeBody(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		return errInvalidJSON()
+	}
+	return nil
+}
+
+// object adds the `object` field to the JSON encoding of a value, e.g.
+// `"object": "page"`.
+type object struct {
+	kind  string
+	value interface{}
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(o.value)
+	if err != nil {
+		return nil, err
+	}
+
+	b = bytes.TrimSpace(b)
+	field := fmt.Sprintf(`{"object":%q`, o.kind)
+	if len(b) <= 2 {
+		return []byte(field + "}"), nil
+	}
+
+	return append([]byte(field+","), b[1:]...), nil
+}
+
+// list is a paginated list response.
+// See: https://developers.notion.com/reference/pagination
+type list struct {
+	Object     string        `json:"object"`
+	Results    []interface{} `json:"results"`
+	NextCursor *string       `json:"next_cursor"`
+	HasMore    bool          `json:"has_more"`
+}
+
+// paginate returns the bounds of a page of n items. The cursor of an item is
+// its key, which is the ID of the object for most endpoints.
+func paginate(n int, key func(i int) string, startCursor string, pageSize int) (start, end int, nextCursor *string, err error) {
+	if pageSize < 0 || pageSize > maxPageSize {
+		return 0, 0, nil, errValidation("body failed validation: body.page_size should be ≤ `%v`, instead was `%v`.", maxPageSize, pageSize)
+	}
+	if pageSize == 0 {
+		pageSize = maxPageSize
+	}
+
+	if startCursor != "" {
+		start = -1
+		for i := 0; i < n; i++ {
+			if key(i) == startCursor {
+				start = i
+				break
+			}
+		}
+		if start == -1 {
+			return 0, 0, nil, errValidation("The start_cursor provided is invalid: %v", startCursor)
+		}
+	}
+
+	end = start + pageSize
+	if end >= n {
+		return start, n, nil, nil
+	}
+
+	next := key(end)
+
+	return start, end, &next, nil
+}
+
+// paginationQuery parses the pagination params of GET requests.
+func paginationQuery(r *http.Request) (startCursor string, pageSize int, err error) {
+	q := r.URL.Query()
+
+	if v := q.Get("page_size"); v != "" {
+		pageSize, err = strconv.Atoi(v)
+		if err != nil {
+			return "", 0, errValidation("page_size should be a number, instead was `%v`.", v)
+		}
+	}
+
+	return q.Get("start_cursor"), pageSize, nil
+}
+
+// newID returns a new, unique ID, formatted as an UUIDv4. IDs are sequential,
+// to make tests deterministic.
+func (s *Server) newID() string {
+	s.lastID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012x", s.lastID)
+}
+
+// timestamp returns the current time, truncated to milliseconds like the
+// timestamps of the Notion API.
+func (s *Server) timestamp() time.Time {
+	return s.now().UTC().Truncate(time.Millisecond)
+}
+
+func (s *Server) botRef() notion.BaseUser {
+	return notion.BaseUser{ID: s.bot.ID}
+}
+
+func objectURL(id string) string {
+	return "https://www.notion.so/" + strings.ReplaceAll(id, "-", "")
+}
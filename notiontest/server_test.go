@@ -0,0 +1,404 @@
+package notiontest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/filter"
+	"github.com/cryptowizard0/go-notion/markdown"
+	"github.com/cryptowizard0/go-notion/notiontest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func richText(s string) []notion.RichText {
+	return []notion.RichText{{Text: &notion.Text{Content: s}}}
+}
+
+func pageNames(t *testing.T, pages []notion.Page) []string {
+	t.Helper()
+
+	names := make([]string, len(pages))
+	for i, page := range pages {
+		props, ok := page.Properties.(notion.DatabasePageProperties)
+		if !ok {
+			t.Fatalf("unexpected properties type %T", page.Properties)
+		}
+		names[i] = props["Name"].Title[0].PlainText
+	}
+	return names
+}
+
+func TestDatabaseQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client := srv.Client()
+	root := srv.AddPage("Root")
+
+	db, err := client.CreateDatabase(ctx, notion.CreateDatabaseParams{
+		ParentPageID: root.ID,
+		Title:        richText("Tasks"),
+		Properties: notion.DatabaseProperties{
+			"Name":     {Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+			"Status":   {Type: notion.DBPropTypeStatus, Status: &notion.StatusMetadata{}},
+			"Estimate": {Type: notion.DBPropTypeNumber, Number: &notion.NumberMetadata{}},
+			"Tags":     {Type: notion.DBPropTypeMultiSelect, MultiSelect: &notion.SelectMetadata{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows := []struct {
+		name     string
+		status   string
+		estimate float64
+		tags     []string
+	}{
+		{"Write docs", "Done", 3, []string{"docs"}},
+		{"Fix bug", "In progress", 5, []string{"bug", "api"}},
+		{"Add tests", "Not started", 1, []string{"api"}},
+		{"Release", "Not started", 8, nil},
+	}
+
+	var pageIDs []string
+	for _, row := range rows {
+		props := notion.DatabasePageProperties{
+			"Name":     {Title: richText(row.name)},
+			"Status":   {Status: &notion.SelectOptions{Name: row.status}},
+			"Estimate": {Number: notion.Float64Ptr(row.estimate)},
+		}
+		for _, tag := range row.tags {
+			tags := props["Tags"]
+			tags.MultiSelect = append(tags.MultiSelect, notion.SelectOptions{Name: tag})
+			props["Tags"] = tags
+		}
+
+		page, err := client.CreatePage(ctx, notion.CreatePageParams{
+			ParentType:             notion.ParentTypeDatabase,
+			ParentID:               db.ID,
+			DatabasePageProperties: &props,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pageIDs = append(pageIDs, page.ID)
+	}
+
+	// Archived pages are excluded from query results.
+	_, err = client.UpdatePage(ctx, pageIDs[3], notion.UpdatePageParams{Archived: notion.BoolPtr(true)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		query    *notion.DatabaseQuery
+		expNames []string
+		expErr   error
+	}{
+		{
+			name:     "no filter",
+			query:    nil,
+			expNames: []string{"Write docs", "Fix bug", "Add tests"},
+		},
+		{
+			name: "filter and sort",
+			query: &notion.DatabaseQuery{
+				Filter: filter.Prop("Tags").MultiSelect().Contains("api").
+					Or(filter.Prop("Status").Status().Equals("Done")).
+					MustBuild(),
+				Sorts: []notion.DatabaseQuerySort{
+					{Property: "Estimate", Direction: notion.SortDirDesc},
+				},
+			},
+			expNames: []string{"Fix bug", "Write docs", "Add tests"},
+		},
+		{
+			name: "paginated",
+			query: &notion.DatabaseQuery{
+				Filter: filter.Prop("Estimate").Number().GreaterThanOrEqualTo(1).MustBuild(),
+				Sorts: []notion.DatabaseQuerySort{
+					{Property: "Name", Direction: notion.SortDirAsc},
+				},
+				PageSize: 1,
+			},
+			expNames: []string{"Add tests", "Fix bug", "Write docs"},
+		},
+		{
+			name: "unknown property",
+			query: &notion.DatabaseQuery{
+				Filter: filter.Prop("Foo").Checkbox().Equals(true).MustBuild(),
+			},
+			expErr: notion.ErrValidation,
+		},
+		{
+			name: "property type mismatch",
+			query: &notion.DatabaseQuery{
+				Filter: filter.Prop("Status").Select().Equals("Done").MustBuild(),
+			},
+			expErr: notion.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pages, err := client.QueryDatabaseAll(ctx, db.ID, tt.query)
+			if tt.expErr != nil {
+				if !errors.Is(err, tt.expErr) {
+					t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.expNames, pageNames(t, pages)); diff != "" {
+				t.Fatalf("pages not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestPageProperties(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2022, time.September, 4, 12, 0, 0, 0, time.UTC)
+	srv := notiontest.NewServer(notiontest.WithClock(func() time.Time { return now }))
+	defer srv.Close()
+
+	client := srv.Client()
+	root := srv.AddPage("Root")
+
+	db, err := client.CreateDatabase(ctx, notion.CreateDatabaseParams{
+		ParentPageID: root.ID,
+		Title:        richText("Tasks"),
+		Properties: notion.DatabaseProperties{
+			"Name":    {Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+			"Done":    {Type: notion.DBPropTypeCheckbox, Checkbox: &notion.EmptyMetadata{}},
+			"Created": {Type: notion.DBPropTypeCreatedTime, CreatedTime: &notion.EmptyMetadata{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	page, err := client.CreatePage(ctx, notion.CreatePageParams{
+		ParentType: notion.ParentTypeDatabase,
+		ParentID:   db.ID,
+		DatabasePageProperties: &notion.DatabasePageProperties{
+			"Name": {Title: richText("Write docs")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	page, err = client.UpdatePage(ctx, page.ID, notion.UpdatePageParams{
+		DatabasePageProperties: notion.DatabasePageProperties{
+			db.Properties["Done"].ID: {Checkbox: notion.BoolPtr(true)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got struct {
+		Name    string    `notion:"Name"`
+		Done    bool      `notion:"Done"`
+		Created time.Time `notion:"Created"`
+	}
+	if err := notion.UnmarshalProperties(page, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != "Write docs" || !got.Done || !got.Created.Equal(now) {
+		t.Fatalf("unexpected properties: %+v", got)
+	}
+
+	_, err = client.UpdatePage(ctx, page.ID, notion.UpdatePageParams{
+		DatabasePageProperties: notion.DatabasePageProperties{
+			"Done": {RichText: richText("yes")},
+		},
+	})
+	if !errors.Is(err, notion.ErrValidation) {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+
+	items, err := client.FindPagePropertyByIDAll(ctx, page.ID, "title", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Title.PlainText != "Write docs" {
+		t.Fatalf("unexpected property items: %+v", items)
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	root := srv.AddPage("Root")
+
+	md := "# Notes\n\nSome **bold** text.\n\n- One\n  - Nested\n- Two\n"
+
+	page, err := client.CreatePage(ctx, notion.CreatePageParams{
+		ParentType: notion.ParentTypePage,
+		ParentID:   root.ID,
+		Title:      richText("Notes"),
+		Children:   markdown.Parse(md),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exported, err := (&markdown.Renderer{}).ExportPage(ctx, client, page.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("# Notes\n\n"+md, exported); diff != "" {
+		t.Fatalf("markdown not equal (-exp, +got):\n%v", diff)
+	}
+
+	// The new page is a child page block of its parent.
+	children, err := client.FindBlockChildrenByIDAll(ctx, root.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(children) != 1 {
+		t.Fatalf("expected 1 child block, got %v", len(children))
+	}
+	if childPage, ok := children[0].(*notion.ChildPageBlock); !ok || childPage.Title != "Notes" {
+		t.Fatalf("unexpected child block: %#v", children[0])
+	}
+
+	appended, err := client.AppendBlockChildren(ctx, page.ID, []notion.Block{
+		&notion.ParagraphBlock{RichText: richText("Appended")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id := appended.Results[0].ID()
+
+	updated, err := client.UpdateBlock(ctx, id, &notion.ParagraphBlock{RichText: richText("Updated")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if paragraph := updated.(*notion.ParagraphBlock); paragraph.RichText[0].PlainText != "Updated" {
+		t.Fatalf("unexpected rich text: %+v", paragraph.RichText)
+	}
+
+	deleted, err := client.DeleteBlock(ctx, id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !deleted.Archived() {
+		t.Fatal("expected deleted block to be archived")
+	}
+
+	children, err = client.FindBlockChildrenByIDAll(ctx, page.ID, &notion.PaginationQuery{PageSize: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(children) != 4 {
+		t.Fatalf("expected 4 child blocks, got %v", len(children))
+	}
+
+	_, err = client.UpdateBlock(ctx, id, &notion.ParagraphBlock{RichText: richText("Archived")})
+	if !errors.Is(err, notion.ErrValidation) {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+}
+
+func TestSearchAndComments(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	root := srv.AddPage("Project plan")
+	srv.AddPage("Meeting notes")
+
+	result, err := client.Search(ctx, &notion.SearchOpts{
+		Query:  "PLAN",
+		Filter: &notion.SearchFilter{Property: "object", Value: "page"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Results) != 1 || result.Results[0].(notion.Page).ID != root.ID {
+		t.Fatalf("unexpected search results: %+v", result.Results)
+	}
+
+	comment, err := client.CreateComment(ctx, notion.CreateCommentParams{
+		ParentPageID: root.ID,
+		RichText:     richText("First"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = client.CreateComment(ctx, notion.CreateCommentParams{
+		DiscussionID: comment.DiscussionID,
+		RichText:     richText("Reply"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	comments, err := client.FindCommentsByBlockIDAll(ctx, notion.FindCommentsByBlockIDQuery{BlockID: root.ID, PageSize: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 2 || comments[1].DiscussionID != comment.DiscussionID {
+		t.Fatalf("unexpected comments: %+v", comments)
+	}
+
+	user := srv.AddUser(notion.User{Name: "John Doe", Person: &notion.Person{Email: "john@example.com"}})
+	users, err := client.ListUsersAll(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]notion.User{srv.Bot(), user}, users); diff != "" {
+		t.Fatalf("users not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := notiontest.NewServer()
+	defer srv.Close()
+
+	_, err := srv.Client().FindPageByID(ctx, "00000000-0000-4000-8000-00000000ffff")
+	if !errors.Is(err, notion.ErrObjectNotFound) {
+		t.Fatalf("expected object not found error, got: %v", err)
+	}
+
+	_, err = notion.NewClient("invalid", notion.WithBaseURL(srv.URL+"/v1")).FindCurrentUser(ctx)
+	if !errors.Is(err, notion.ErrUnauthorized) {
+		t.Fatalf("expected unauthorized error, got: %v", err)
+	}
+
+	var apiErr *notion.APIError
+	_, err = srv.Client().QueryDatabase(ctx, "unknown", nil)
+	if !errors.As(err, &apiErr) || apiErr.Status != 404 || apiErr.Code != "object_not_found" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package notiontest
+
+import (
+	"net/http"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+func (s *Server) findUser(r *http.Request, params []string) (interface{}, error) {
+	user := s.findUserByID(params[0])
+	if user == nil {
+		return nil, errNotFound("user", params[0])
+	}
+
+	return object{"user", user}, nil
+}
+
+func (s *Server) findCurrentUser(r *http.Request, params []string) (interface{}, error) {
+	return object{"user", s.bot}, nil
+}
+
+func (s *Server) listUsers(r *http.Request, params []string) (interface{}, error) {
+	startCursor, pageSize, err := paginationQuery(r)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, next, err := paginate(len(s.users), func(i int) string { return s.users[i].ID }, startCursor, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := list{Object: "list", Results: []interface{}{}, NextCursor: next, HasMore: next != nil}
+	for _, user := range s.users[start:end] {
+		resp.Results = append(resp.Results, object{"user", user})
+	}
+
+	return resp, nil
+}
+
+func (s *Server) findUserByID(id string) *notion.User {
+	for _, user := range s.users {
+		if user.ID == id {
+			return &user
+		}
+	}
+	return nil
+}
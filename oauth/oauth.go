@@ -0,0 +1,219 @@
+// Package oauth implements the OAuth 2.0 authorization flow of public Notion
+// integrations.
+//
+//	cfg := &oauth.Config{
+//		ClientID:     os.Getenv("NOTION_CLIENT_ID"),
+//		ClientSecret: os.Getenv("NOTION_CLIENT_SECRET"),
+//		RedirectURI:  "https://example.com/oauth/callback",
+//	}
+//
+//	// Redirect the user to the authorization URL.
+//	url := cfg.AuthCodeURL(state)
+//
+//	// In the redirect URI handler, exchange the code for an access token.
+//	token, err := cfg.Exchange(ctx, r.URL.Query().Get("code"))
+//	if err != nil {
+//		// Handle error...
+//	}
+//	client := cfg.Client(token)
+//
+// Store the token per workspace (see Token.WorkspaceID); access tokens of
+// public integrations don't expire.
+// See: https://developers.notion.com/docs/authorization
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// Config is the OAuth configuration of a public integration.
+type Config struct {
+	// ClientID and ClientSecret are the OAuth credentials of the integration.
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURI is the URI users are redirected to after authorizing the
+	// integration. It's required if the integration has more than one redirect
+	// URI configured.
+	RedirectURI string
+
+	// BaseURL overrides notion.DefaultBaseURL, e.g. to use a local stand-in
+	// server. It's also used for clients returned by Client.
+	BaseURL string
+
+	// HTTPClient overrides http.DefaultClient. It's also used for clients
+	// returned by Client.
+	HTTPClient *http.Client
+}
+
+// Token is the result of a successful code exchange.
+// See: https://developers.notion.com/docs/authorization#step-4-notion-responds-with-an-access_token-and-some-additional-information
+type Token struct {
+	AccessToken          string          `json:"access_token"`
+	TokenType            string          `json:"token_type"`
+	BotID                string          `json:"bot_id"`
+	WorkspaceID          string          `json:"workspace_id"`
+	WorkspaceName        string          `json:"workspace_name"`
+	WorkspaceIcon        string          `json:"workspace_icon"`
+	Owner                notion.BotOwner `json:"owner"`
+	DuplicatedTemplateID *string         `json:"duplicated_template_id"`
+}
+
+// Error is an error response of the token endpoint.
+type Error struct {
+	StatusCode  int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+	RequestID   string `json:"request_id"`
+}
+
+// Error implements `error`.
+func (err *Error) Error() string {
+	msg := fmt.Sprintf("%v (status: %v", err.Code, err.StatusCode)
+	if err.RequestID != "" {
+		msg += ", request ID: " + err.RequestID
+	}
+	msg += ")"
+	if err.Description != "" {
+		msg = err.Description + ": " + msg
+	}
+
+	return msg
+}
+
+func (cfg *Config) baseURL() string {
+	if cfg.BaseURL == "" {
+		return notion.DefaultBaseURL
+	}
+	return strings.TrimRight(cfg.BaseURL, "/")
+}
+
+func (cfg *Config) httpClient() *http.Client {
+	if cfg.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return cfg.HTTPClient
+}
+
+// AuthCodeURL returns the URL to which users are redirected to authorize the
+// integration. The state is passed back to the redirect URI unchanged, and
+// should be used to protect against CSRF.
+func (cfg *Config) AuthCodeURL(state string) string {
+	q := url.Values{}
+	q.Set("client_id", cfg.ClientID)
+	q.Set("response_type", "code")
+	q.Set("owner", "user")
+	if cfg.RedirectURI != "" {
+		q.Set("redirect_uri", cfg.RedirectURI)
+	}
+	if state != "" {
+		q.Set("state", state)
+	}
+
+	return cfg.baseURL() + "/oauth/authorize?" + q.Encode()
+}
+
+// Exchange exchanges an authorization code, received on the redirect URI, for
+// an access token.
+// See: https://developers.notion.com/reference/create-a-token
+func (cfg *Config) Exchange(ctx context.Context, code string) (token Token, err error) {
+	if code == "" {
+		return Token{}, fmt.Errorf("oauth: code is required")
+	}
+
+	params := struct {
+		GrantType   string `json:"grant_type"`
+		Code        string `json:"code"`
+		RedirectURI string `json:"redirect_uri,omitempty"`
+	}{
+		GrantType:   "authorization_code",
+		Code:        code,
+		RedirectURI: cfg.RedirectURI,
+	}
+
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(params); err != nil {
+		return Token{}, fmt.Errorf("oauth: failed to encode body params to JSON: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.baseURL()+"/oauth/token", body)
+	if err != nil {
+		return Token{}, fmt.Errorf("oauth: invalid request: %w", err)
+	}
+	req.SetBasicAuth(cfg.ClientID, cfg.ClientSecret)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Notion-Version", notion.DefaultAPIVersion)
+
+	res, err := cfg.httpClient().Do(req)
+	if err != nil {
+		return Token{}, fmt.Errorf("oauth: failed to make HTTP request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Token{}, fmt.Errorf("oauth: failed to exchange code: %w", parseErrorResponse(res))
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return Token{}, fmt.Errorf("oauth: failed to parse HTTP response: %w", err)
+	}
+
+	return token, nil
+}
+
+// Client returns a Notion API client that authenticates with the access token.
+// Options are applied after the base URL and HTTP client of the config.
+func (cfg *Config) Client(token Token, opts ...notion.ClientOption) *notion.Client {
+	var clientOpts []notion.ClientOption
+	if cfg.BaseURL != "" {
+		clientOpts = append(clientOpts, notion.WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.HTTPClient != nil {
+		clientOpts = append(clientOpts, notion.WithHTTPClient(cfg.HTTPClient))
+	}
+
+	return notion.NewClient(token.AccessToken, append(clientOpts, opts...)...)
+}
+
+// parseErrorResponse returns the error of a token endpoint response. Both
+// OAuth error responses (`error`) and Notion error objects (`code`) are
+// supported.
+func parseErrorResponse(res *http.Response) error {
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read error from HTTP response: %w", err)
+	}
+
+	var body struct {
+		Error
+		Message    string `json:"message"`
+		NotionCode string `json:"code"`
+	}
+	_ = json.Unmarshal(b, &body)
+
+	oauthErr := body.Error
+	oauthErr.StatusCode = res.StatusCode
+	if oauthErr.Code == "" {
+		oauthErr.Code = body.NotionCode
+	}
+	if oauthErr.Description == "" {
+		oauthErr.Description = body.Message
+	}
+	if oauthErr.Code == "" {
+		oauthErr.Code = http.StatusText(res.StatusCode)
+	}
+	if requestID := res.Header.Get("X-Request-Id"); requestID != "" {
+		oauthErr.RequestID = requestID
+	}
+
+	return &oauthErr
+}
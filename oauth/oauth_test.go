@@ -0,0 +1,203 @@
+package oauth_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/oauth"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAuthCodeURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		cfg    oauth.Config
+		state  string
+		expURL string
+	}{
+		{
+			name:   "defaults",
+			cfg:    oauth.Config{ClientID: "client-id"},
+			expURL: "https://api.notion.com/v1/oauth/authorize?client_id=client-id&owner=user&response_type=code",
+		},
+		{
+			name: "redirect URI and state",
+			cfg: oauth.Config{
+				ClientID:    "client-id",
+				RedirectURI: "https://example.com/callback",
+				BaseURL:     "http://localhost:8080/v1/",
+			},
+			state:  "csrf-token",
+			expURL: "http://localhost:8080/v1/oauth/authorize?client_id=client-id&owner=user&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&state=csrf-token",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.cfg.AuthCodeURL(tt.state); got != tt.expURL {
+				t.Fatalf("URL not equal (expected: %v, got: %v)", tt.expURL, got)
+			}
+		})
+	}
+}
+
+func TestExchange(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "client-id" || clientSecret != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"Invalid client credentials.","request_id":"req-1"}`))
+			return
+		}
+
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		exp := map[string]string{
+			"grant_type":   "authorization_code",
+			"code":         "valid-code",
+			"redirect_uri": "https://example.com/callback",
+		}
+		if body["code"] != "valid-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"Invalid code."}`))
+			return
+		}
+		if diff := cmp.Diff(exp, body); diff != "" {
+			t.Errorf("body not equal (-exp, +got):\n%v", diff)
+		}
+
+		_, _ = w.Write([]byte(`{
+			"access_token": "secret_access_token",
+			"token_type": "bearer",
+			"bot_id": "bot-id",
+			"workspace_id": "workspace-id",
+			"workspace_name": "Acme",
+			"workspace_icon": "https://example.com/icon.png",
+			"owner": {
+				"type": "user",
+				"user": {"object": "user", "id": "user-id", "type": "person", "name": "Jane"}
+			},
+			"duplicated_template_id": null
+		}`))
+	})
+	mux.HandleFunc("/v1/users/me", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret_access_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"object": "user", "id": "bot-id", "type": "bot", "bot": {}}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	baseCfg := oauth.Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURI:  "https://example.com/callback",
+		BaseURL:      srv.URL + "/v1",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := baseCfg
+		token, err := cfg.Exchange(context.Background(), "valid-code")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		exp := oauth.Token{
+			AccessToken:   "secret_access_token",
+			TokenType:     "bearer",
+			BotID:         "bot-id",
+			WorkspaceID:   "workspace-id",
+			WorkspaceName: "Acme",
+			WorkspaceIcon: "https://example.com/icon.png",
+			Owner: notion.BotOwner{
+				Type: notion.BotOwnerTypeUser,
+				User: &notion.User{
+					BaseUser: notion.BaseUser{ID: "user-id"},
+					Type:     notion.UserTypePerson,
+					Name:     "Jane",
+				},
+			},
+		}
+		if diff := cmp.Diff(exp, token); diff != "" {
+			t.Fatalf("token not equal (-exp, +got):\n%v", diff)
+		}
+
+		user, err := cfg.Client(token).FindCurrentUser(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if user.ID != "bot-id" {
+			t.Fatalf("user ID not equal (expected: bot-id, got: %v)", user.ID)
+		}
+	})
+
+	errTests := []struct {
+		name     string
+		cfg      oauth.Config
+		code     string
+		expError *oauth.Error
+	}{
+		{
+			name: "invalid client credentials",
+			cfg: func() oauth.Config {
+				cfg := baseCfg
+				cfg.ClientSecret = "wrong"
+				return cfg
+			}(),
+			code: "valid-code",
+			expError: &oauth.Error{
+				StatusCode:  http.StatusUnauthorized,
+				Code:        "invalid_client",
+				Description: "Invalid client credentials.",
+				RequestID:   "req-1",
+			},
+		},
+		{
+			name: "invalid code",
+			cfg:  baseCfg,
+			code: "invalid-code",
+			expError: &oauth.Error{
+				StatusCode:  http.StatusBadRequest,
+				Code:        "invalid_grant",
+				Description: "Invalid code.",
+			},
+		},
+	}
+
+	for _, tt := range errTests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tt.cfg.Exchange(context.Background(), tt.code)
+
+			var oauthErr *oauth.Error
+			if !errors.As(err, &oauthErr) {
+				t.Fatalf("expected *oauth.Error, got: %v", err)
+			}
+			if diff := cmp.Diff(tt.expError, oauthErr); diff != "" {
+				t.Fatalf("error not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
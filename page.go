@@ -64,6 +64,14 @@ type DatabasePageProperty struct {
 	CreatedBy      *User           `json:"created_by,omitempty"`
 	LastEditedTime *time.Time      `json:"last_edited_time,omitempty"`
 	LastEditedBy   *User           `json:"last_edited_by,omitempty"`
+	UniqueID       *UniqueID       `json:"unique_id,omitempty"`
+}
+
+// UniqueID is the value of a `unique_id` property, e.g. `TASK-12`. It's
+// assigned by Notion, and can't be set.
+type UniqueID struct {
+	Prefix *string `json:"prefix"`
+	Number int     `json:"number"`
 }
 
 // CreatePageParams are the params used for creating a page.
@@ -185,6 +193,8 @@ func (prop DatabasePageProperty) Value() interface{} {
 		return prop.LastEditedTime
 	case DBPropTypeLastEditedBy:
 		return prop.LastEditedBy
+	case DBPropTypeUniqueID:
+		return prop.UniqueID
 	default:
 		return nil
 	}
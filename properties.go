@@ -0,0 +1,697 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// PropertyTypeError is returned when a database page property can't be mapped
+// to or from a struct field, because their types don't match.
+type PropertyTypeError struct {
+	Property string
+	PropType DatabasePropertyType
+	Field    string
+	GoType   reflect.Type
+	Reason   string
+}
+
+// Error implements `error`.
+func (err *PropertyTypeError) Error() string {
+	msg := fmt.Sprintf("notion: cannot map property %q of type %q to field %v of type %v",
+		err.Property, err.PropType, err.Field, err.GoType)
+	if err.Reason != "" {
+		msg += ": " + err.Reason
+	}
+	return msg
+}
+
+var (
+	timeType         = reflect.TypeOf(time.Time{})
+	dateTimeType     = reflect.TypeOf(DateTime{})
+	dateType         = reflect.TypeOf(Date{})
+	richTextsType    = reflect.TypeOf([]RichText{})
+	selectOptionType = reflect.TypeOf(SelectOptions{})
+	selectOptsType   = reflect.TypeOf([]SelectOptions{})
+	usersType        = reflect.TypeOf([]User{})
+	filesType        = reflect.TypeOf([]File{})
+	relationsType    = reflect.TypeOf([]Relation{})
+	pagePropType     = reflect.TypeOf(DatabasePageProperty{})
+)
+
+// propField is a struct field mapped to a database page property, using the
+// `notion` struct tag, e.g. `notion:"Name,title"`. The property type is
+// optional, and can be followed by `omitempty`.
+type propField struct {
+	index     []int
+	field     string
+	name      string
+	propType  DatabasePropertyType
+	omitEmpty bool
+}
+
+func propFields(t reflect.Type) []propField {
+	var fields []propField
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("notion")
+
+		if !ok && sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			for _, f := range propFields(sf.Type) {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if !ok || tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		f := propField{
+			index: []int{i},
+			field: sf.Name,
+			name:  parts[0],
+		}
+		if t.Name() != "" {
+			f.field = t.Name() + "." + sf.Name
+		}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		for _, opt := range parts[1:] {
+			if opt == "omitempty" {
+				f.omitEmpty = true
+			} else if opt != "" {
+				f.propType = DatabasePropertyType(opt)
+			}
+		}
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+func structValue(v interface{}, fn string) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, fmt.Errorf("notion: %v: nil pointer", fn)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("notion: %v: expected struct or pointer to struct, got %v", fn, rv.Type())
+	}
+	return rv, nil
+}
+
+// UnmarshalProperties copies the database page properties of page into the
+// struct pointed to by dst. Struct fields are mapped to properties using the
+// `notion` struct tag, with the property name and an optional property type,
+// e.g. `notion:"Name,title"`. Properties missing from the page are skipped.
+//
+// Supported field types are strings (title, rich text, select, status, URL,
+// email, phone number and string formulas), numbers, bools (checkbox),
+// time.Time and DateTime (date, created and last edited time), Date,
+// []string (multi-select names, relation IDs, people IDs and file URLs),
+// and the corresponding types of this package, e.g. []RichText or []User.
+// Pointer fields are set to nil when a property has no value.
+func UnmarshalProperties(page Page, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("notion: UnmarshalProperties: dst must be a non-nil pointer to a struct")
+	}
+	rv, err := structValue(dst, "UnmarshalProperties")
+	if err != nil {
+		return err
+	}
+
+	props, ok := page.Properties.(DatabasePageProperties)
+	if !ok {
+		return errors.New("notion: UnmarshalProperties: page parent is not a database")
+	}
+
+	for _, f := range propFields(rv.Type()) {
+		prop, ok := props[f.name]
+		if !ok {
+			continue
+		}
+		if f.propType != "" && f.propType != prop.Type {
+			return &PropertyTypeError{
+				Property: f.name,
+				PropType: prop.Type,
+				Field:    f.field,
+				GoType:   rv.FieldByIndex(f.index).Type(),
+				Reason:   fmt.Sprintf("struct tag has property type %q", f.propType),
+			}
+		}
+
+		if err := unmarshalProperty(prop, rv.FieldByIndex(f.index)); err != nil {
+			var typeErr *PropertyTypeError
+			if errors.As(err, &typeErr) {
+				typeErr.Property = f.name
+				typeErr.Field = f.field
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func unmarshalProperty(prop DatabasePageProperty, v reflect.Value) error {
+	mismatch := func(reason string) error {
+		return &PropertyTypeError{PropType: prop.Type, GoType: v.Type(), Reason: reason}
+	}
+
+	if v.Type() == pagePropType {
+		v.Set(reflect.ValueOf(prop))
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if propIsEmpty(prop) {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := unmarshalProperty(prop, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	switch v.Type() {
+	case timeType, dateTimeType:
+		t, hasTime, ok := propTime(prop)
+		if !ok {
+			return mismatch("")
+		}
+		if v.Type() == timeType {
+			v.Set(reflect.ValueOf(t))
+		} else {
+			v.Set(reflect.ValueOf(NewDateTime(t, hasTime)))
+		}
+		return nil
+	case dateType:
+		if prop.Type != DBPropTypeDate {
+			return mismatch("")
+		}
+		if prop.Date != nil {
+			v.Set(reflect.ValueOf(*prop.Date))
+		}
+		return nil
+	case richTextsType:
+		switch prop.Type {
+		case DBPropTypeTitle:
+			v.Set(reflect.ValueOf(prop.Title))
+		case DBPropTypeRichText:
+			v.Set(reflect.ValueOf(prop.RichText))
+		default:
+			return mismatch("")
+		}
+		return nil
+	case selectOptionType:
+		switch {
+		case prop.Type == DBPropTypeSelect && prop.Select != nil:
+			v.Set(reflect.ValueOf(*prop.Select))
+		case prop.Type == DBPropTypeStatus && prop.Status != nil:
+			v.Set(reflect.ValueOf(*prop.Status))
+		case prop.Type != DBPropTypeSelect && prop.Type != DBPropTypeStatus:
+			return mismatch("")
+		}
+		return nil
+	case selectOptsType, usersType, filesType, relationsType:
+		value := reflect.ValueOf(prop.Value())
+		if !value.IsValid() || value.Type() != v.Type() {
+			return mismatch("")
+		}
+		v.Set(value)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		s, ok := propString(prop)
+		if !ok {
+			return mismatch("")
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		switch {
+		case prop.Type == DBPropTypeCheckbox:
+			v.SetBool(prop.Checkbox != nil && *prop.Checkbox)
+		case prop.Type == DBPropTypeFormula && prop.Formula != nil && prop.Formula.Type == FormulaResultTypeBoolean:
+			v.SetBool(prop.Formula.Boolean != nil && *prop.Formula.Boolean)
+		default:
+			return mismatch("")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		n, ok := propNumber(prop)
+		if !ok {
+			return mismatch("")
+		}
+		return setNumber(v, n, mismatch)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return mismatch("")
+		}
+		strs, ok := propStrings(prop)
+		if !ok {
+			return mismatch("")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(strs), len(strs))
+		for i, s := range strs {
+			slice.Index(i).SetString(s)
+		}
+		v.Set(slice)
+	default:
+		return mismatch("unsupported field type")
+	}
+
+	return nil
+}
+
+func setNumber(v reflect.Value, n float64, mismatch func(string) error) error {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if v.OverflowFloat(n) {
+			return mismatch(fmt.Sprintf("number %v overflows field", n))
+		}
+		v.SetFloat(n)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// The range is checked before converting, as conversions of floats
+		// that are out of range don't fail.
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 || v.OverflowInt(int64(n)) {
+			return mismatch(fmt.Sprintf("number %v can't be represented by field", n))
+		}
+		v.SetInt(int64(n))
+	default:
+		if n < 0 || n != math.Trunc(n) || n >= math.MaxUint64 || v.OverflowUint(uint64(n)) {
+			return mismatch(fmt.Sprintf("number %v can't be represented by field", n))
+		}
+		v.SetUint(uint64(n))
+	}
+	return nil
+}
+
+// propIsEmpty reports whether a property has no value.
+func propIsEmpty(prop DatabasePageProperty) bool {
+	value := reflect.ValueOf(prop.Value())
+	if !value.IsValid() {
+		return true
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		return value.IsNil()
+	}
+	return false
+}
+
+func propString(prop DatabasePageProperty) (string, bool) {
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+
+	switch prop.Type {
+	case DBPropTypeTitle:
+		return PlainText(prop.Title), true
+	case DBPropTypeRichText:
+		return PlainText(prop.RichText), true
+	case DBPropTypeSelect:
+		if prop.Select == nil {
+			return "", true
+		}
+		return prop.Select.Name, true
+	case DBPropTypeStatus:
+		if prop.Status == nil {
+			return "", true
+		}
+		return prop.Status.Name, true
+	case DBPropTypeURL:
+		return deref(prop.URL), true
+	case DBPropTypeEmail:
+		return deref(prop.Email), true
+	case DBPropTypePhoneNumber:
+		return deref(prop.PhoneNumber), true
+	case DBPropTypeFormula:
+		if prop.Formula != nil && prop.Formula.Type == FormulaResultTypeString {
+			return deref(prop.Formula.String), true
+		}
+	}
+
+	return "", false
+}
+
+func propNumber(prop DatabasePageProperty) (float64, bool) {
+	var n *float64
+
+	switch {
+	case prop.Type == DBPropTypeNumber:
+		n = prop.Number
+	case prop.Type == DBPropTypeFormula && prop.Formula != nil && prop.Formula.Type == FormulaResultTypeNumber:
+		n = prop.Formula.Number
+	case prop.Type == DBPropTypeRollup && prop.Rollup != nil && prop.Rollup.Type == RollupResultTypeNumber:
+		n = prop.Rollup.Number
+	default:
+		return 0, false
+	}
+
+	if n == nil {
+		return 0, true
+	}
+	return *n, true
+}
+
+func propStrings(prop DatabasePageProperty) ([]string, bool) {
+	var strs []string
+
+	switch prop.Type {
+	case DBPropTypeMultiSelect:
+		for _, opt := range prop.MultiSelect {
+			strs = append(strs, opt.Name)
+		}
+	case DBPropTypeRelation:
+		for _, rel := range prop.Relation {
+			strs = append(strs, rel.ID)
+		}
+	case DBPropTypePeople:
+		for _, user := range prop.People {
+			strs = append(strs, user.ID)
+		}
+	case DBPropTypeFiles:
+		for _, file := range prop.Files {
+			switch {
+			case file.File != nil:
+				strs = append(strs, file.File.URL)
+			case file.External != nil:
+				strs = append(strs, file.External.URL)
+			}
+		}
+	default:
+		return nil, false
+	}
+
+	return strs, true
+}
+
+func propTime(prop DatabasePageProperty) (t time.Time, hasTime bool, ok bool) {
+	var date *Date
+
+	switch prop.Type {
+	case DBPropTypeDate:
+		date = prop.Date
+	case DBPropTypeFormula:
+		if prop.Formula == nil || prop.Formula.Type != FormulaResultTypeDate {
+			return time.Time{}, false, false
+		}
+		date = prop.Formula.Date
+	case DBPropTypeCreatedTime:
+		if prop.CreatedTime != nil {
+			return *prop.CreatedTime, true, true
+		}
+		return time.Time{}, true, true
+	case DBPropTypeLastEditedTime:
+		if prop.LastEditedTime != nil {
+			return *prop.LastEditedTime, true, true
+		}
+		return time.Time{}, true, true
+	default:
+		return time.Time{}, false, false
+	}
+
+	if date == nil {
+		return time.Time{}, false, true
+	}
+	return date.Start.Time, date.Start.HasTime(), true
+}
+
+// MarshalProperties returns database page properties for the struct (or
+// pointer to struct) src, for use with CreatePageParams and UpdatePageParams.
+// See UnmarshalProperties for the `notion` struct tag format.
+//
+// When the property type is omitted from the struct tag, it's inferred from
+// the field type: strings map to rich text, numbers to number, bools to
+// checkbox, time.Time, DateTime and Date to date, and []string to
+// multi-select. Nil pointer fields, read-only properties (formula, rollup,
+// created and last edited time and user) and fields tagged with `omitempty`
+// that have a zero value are skipped.
+func MarshalProperties(src interface{}) (DatabasePageProperties, error) {
+	rv, err := structValue(src, "MarshalProperties")
+	if err != nil {
+		return nil, err
+	}
+
+	props := make(DatabasePageProperties)
+
+	for _, f := range propFields(rv.Type()) {
+		v := rv.FieldByIndex(f.index)
+
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				continue
+			}
+			v = v.Elem()
+		}
+		if f.omitEmpty && v.IsZero() {
+			continue
+		}
+		if v.Type() == pagePropType {
+			prop := v.Interface().(DatabasePageProperty)
+			if !isReadOnlyPropType(f.propType) && !isReadOnlyPropType(prop.Type) {
+				props[f.name] = prop
+			}
+			continue
+		}
+
+		propType := f.propType
+		if propType == "" {
+			propType = inferPropType(v.Type())
+		}
+		if isReadOnlyPropType(propType) {
+			continue
+		}
+
+		prop, err := marshalProperty(propType, v)
+		if err != nil {
+			return nil, &PropertyTypeError{
+				Property: f.name,
+				PropType: propType,
+				Field:    f.field,
+				GoType:   rv.FieldByIndex(f.index).Type(),
+				Reason:   err.Error(),
+			}
+		}
+		props[f.name] = prop
+	}
+
+	return props, nil
+}
+
+func inferPropType(t reflect.Type) DatabasePropertyType {
+	switch t {
+	case timeType, dateTimeType, dateType:
+		return DBPropTypeDate
+	case richTextsType:
+		return DBPropTypeRichText
+	case selectOptionType:
+		return DBPropTypeSelect
+	case selectOptsType:
+		return DBPropTypeMultiSelect
+	case usersType:
+		return DBPropTypePeople
+	case filesType:
+		return DBPropTypeFiles
+	case relationsType:
+		return DBPropTypeRelation
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return DBPropTypeRichText
+	case reflect.Bool:
+		return DBPropTypeCheckbox
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return DBPropTypeNumber
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			return DBPropTypeMultiSelect
+		}
+	}
+
+	return ""
+}
+
+func isReadOnlyPropType(propType DatabasePropertyType) bool {
+	switch propType {
+	case DBPropTypeFormula, DBPropTypeRollup, DBPropTypeCreatedTime, DBPropTypeCreatedBy,
+		DBPropTypeLastEditedTime, DBPropTypeLastEditedBy, DBPropTypeUniqueID:
+		return true
+	}
+	return false
+}
+
+var errUnsupportedFieldType = errors.New("unsupported field type")
+
+func marshalProperty(propType DatabasePropertyType, v reflect.Value) (DatabasePageProperty, error) {
+	prop := DatabasePageProperty{Type: propType}
+
+	switch propType {
+	case DBPropTypeTitle, DBPropTypeRichText:
+		var richText []RichText
+		switch {
+		case v.Type() == richTextsType:
+			richText = v.Interface().([]RichText)
+		case v.Kind() == reflect.String:
+			richText = TextRichText(v.String())
+		default:
+			return prop, errUnsupportedFieldType
+		}
+		if propType == DBPropTypeTitle {
+			prop.Title = richText
+		} else {
+			prop.RichText = richText
+		}
+	case DBPropTypeNumber:
+		var n float64
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			n = v.Float()
+		default:
+			return prop, errUnsupportedFieldType
+		}
+		prop.Number = &n
+	case DBPropTypeSelect, DBPropTypeStatus:
+		var opt SelectOptions
+		switch {
+		case v.Type() == selectOptionType:
+			opt = v.Interface().(SelectOptions)
+		case v.Kind() == reflect.String:
+			opt = SelectOptions{Name: v.String()}
+		default:
+			return prop, errUnsupportedFieldType
+		}
+		if propType == DBPropTypeSelect {
+			prop.Select = &opt
+		} else {
+			prop.Status = &opt
+		}
+	case DBPropTypeMultiSelect:
+		switch {
+		case v.Type() == selectOptsType:
+			prop.MultiSelect = v.Interface().([]SelectOptions)
+		case isStringSlice(v):
+			prop.MultiSelect = []SelectOptions{}
+			for _, s := range stringSlice(v) {
+				prop.MultiSelect = append(prop.MultiSelect, SelectOptions{Name: s})
+			}
+		default:
+			return prop, errUnsupportedFieldType
+		}
+	case DBPropTypeDate:
+		switch v.Type() {
+		case dateType:
+			date := v.Interface().(Date)
+			prop.Date = &date
+		case dateTimeType:
+			prop.Date = &Date{Start: v.Interface().(DateTime)}
+		case timeType:
+			prop.Date = &Date{Start: NewDateTime(v.Interface().(time.Time), true)}
+		default:
+			return prop, errUnsupportedFieldType
+		}
+	case DBPropTypeCheckbox:
+		if v.Kind() != reflect.Bool {
+			return prop, errUnsupportedFieldType
+		}
+		prop.Checkbox = BoolPtr(v.Bool())
+	case DBPropTypeURL, DBPropTypeEmail, DBPropTypePhoneNumber:
+		if v.Kind() != reflect.String {
+			return prop, errUnsupportedFieldType
+		}
+		s := v.String()
+		switch propType {
+		case DBPropTypeURL:
+			prop.URL = &s
+		case DBPropTypeEmail:
+			prop.Email = &s
+		default:
+			prop.PhoneNumber = &s
+		}
+	case DBPropTypeRelation:
+		switch {
+		case v.Type() == relationsType:
+			prop.Relation = v.Interface().([]Relation)
+		case isStringSlice(v):
+			prop.Relation = []Relation{}
+			for _, id := range stringSlice(v) {
+				prop.Relation = append(prop.Relation, Relation{ID: id})
+			}
+		default:
+			return prop, errUnsupportedFieldType
+		}
+	case DBPropTypePeople:
+		switch {
+		case v.Type() == usersType:
+			prop.People = v.Interface().([]User)
+		case isStringSlice(v):
+			prop.People = []User{}
+			for _, id := range stringSlice(v) {
+				prop.People = append(prop.People, User{BaseUser: BaseUser{ID: id}})
+			}
+		default:
+			return prop, errUnsupportedFieldType
+		}
+	case DBPropTypeFiles:
+		switch {
+		case v.Type() == filesType:
+			prop.Files = v.Interface().([]File)
+		case isStringSlice(v):
+			prop.Files = []File{}
+			for _, url := range stringSlice(v) {
+				prop.Files = append(prop.Files, File{
+					Name:     url,
+					Type:     FileTypeExternal,
+					External: &FileExternal{URL: url},
+				})
+			}
+		default:
+			return prop, errUnsupportedFieldType
+		}
+	case "":
+		return prop, errUnsupportedFieldType
+	default:
+		return prop, fmt.Errorf("unsupported property type %q", propType)
+	}
+
+	return prop, nil
+}
+
+func isStringSlice(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String
+}
+
+func stringSlice(v reflect.Value) []string {
+	strs := make([]string, v.Len())
+	for i := range strs {
+		strs[i] = v.Index(i).String()
+	}
+	return strs
+}
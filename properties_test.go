@@ -0,0 +1,382 @@
+package notion_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/google/go-cmp/cmp"
+)
+
+type task struct {
+	Name      string            `notion:"Name,title"`
+	Notes     *string           `notion:"Notes,rich_text"`
+	Status    string            `notion:"Status,status"`
+	Priority  string            `notion:"Priority,select"`
+	Tags      []string          `notion:"Tags,multi_select"`
+	Estimate  int               `notion:"Estimate,number"`
+	Done      bool              `notion:"Done,checkbox"`
+	Due       time.Time         `notion:"Due,date"`
+	Day       notion.DateTime   `notion:"Day,date"`
+	Blockers  []string          `notion:"Blockers,relation"`
+	Assignees []string          `notion:"Assignees,people"`
+	Link      string            `notion:"Link,url"`
+	Files     []string          `notion:"Files,files"`
+	Created   time.Time         `notion:"Created,created_time"`
+	Score     float64           `notion:"Score,formula"`
+	Raw       []notion.RichText `notion:"Name"`
+	Ignored   string            `notion:"-"`
+}
+
+const taskPageJSON = `{
+	"object": "page",
+	"id": "page-id",
+	"parent": {"type": "database_id", "database_id": "db-id"},
+	"properties": {
+		"Name": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "Write docs"}, "plain_text": "Write docs"}]},
+		"Notes": {"id": "a", "type": "rich_text", "rich_text": []},
+		"Status": {"id": "b", "type": "status", "status": {"name": "In progress"}},
+		"Priority": {"id": "c", "type": "select", "select": {"name": "High"}},
+		"Tags": {"id": "d", "type": "multi_select", "multi_select": [{"name": "docs"}, {"name": "api"}]},
+		"Estimate": {"id": "e", "type": "number", "number": 3},
+		"Done": {"id": "f", "type": "checkbox", "checkbox": true},
+		"Due": {"id": "g", "type": "date", "date": {"start": "2021-05-18T12:49:00.000-05:00"}},
+		"Day": {"id": "h", "type": "date", "date": {"start": "2021-05-18"}},
+		"Blockers": {"id": "i", "type": "relation", "relation": [{"id": "rel-1"}]},
+		"Assignees": {"id": "j", "type": "people", "people": [{"object": "user", "id": "user-1"}]},
+		"Link": {"id": "k", "type": "url", "url": "https://example.com"},
+		"Files": {"id": "l", "type": "files", "files": [{"name": "a", "type": "external", "external": {"url": "https://example.com/a.png"}}]},
+		"Created": {"id": "m", "type": "created_time", "created_time": "2021-05-14T09:15:00.000Z"},
+		"Score": {"id": "n", "type": "formula", "formula": {"type": "number", "number": 4.5}}
+	}
+}`
+
+func TestUnmarshalProperties(t *testing.T) {
+	t.Parallel()
+
+	var page notion.Page
+	if err := json.Unmarshal([]byte(taskPageJSON), &page); err != nil {
+		t.Fatal(err)
+	}
+
+	var got task
+	if err := notion.UnmarshalProperties(page, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	due, _ := time.Parse(time.RFC3339, "2021-05-18T12:49:00-05:00")
+	day, _ := notion.ParseDateTime("2021-05-18")
+	created, _ := time.Parse(time.RFC3339, "2021-05-14T09:15:00Z")
+
+	exp := task{
+		Name:      "Write docs",
+		Notes:     notion.StringPtr(""),
+		Status:    "In progress",
+		Priority:  "High",
+		Tags:      []string{"docs", "api"},
+		Estimate:  3,
+		Done:      true,
+		Due:       due,
+		Day:       day,
+		Blockers:  []string{"rel-1"},
+		Assignees: []string{"user-1"},
+		Link:      "https://example.com",
+		Files:     []string{"https://example.com/a.png"},
+		Created:   created,
+		Score:     4.5,
+		Raw: []notion.RichText{
+			{Type: notion.RichTextTypeText, Text: &notion.Text{Content: "Write docs"}, PlainText: "Write docs"},
+		},
+	}
+
+	opts := cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })
+	if diff := cmp.Diff(exp, got, opts, cmp.Comparer(func(a, b notion.DateTime) bool { return a.Equal(b) })); diff != "" {
+		t.Fatalf("struct not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestUnmarshalPropertiesErrors(t *testing.T) {
+	t.Parallel()
+
+	var page notion.Page
+	if err := json.Unmarshal([]byte(taskPageJSON), &page); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		dst    interface{}
+		expErr string
+	}{
+		{
+			name: "type mismatch",
+			dst: &struct {
+				Status int `notion:"Status"`
+			}{},
+			expErr: `notion: cannot map property "Status" of type "status" to field Status of type int`,
+		},
+		{
+			name: "struct tag type mismatch",
+			dst: &struct {
+				Status string `notion:"Status,select"`
+			}{},
+			expErr: `notion: cannot map property "Status" of type "status" to field Status of type string: struct tag has property type "select"`,
+		},
+		{
+			name: "non integer number",
+			dst: &struct {
+				Score int `notion:"Score"`
+			}{},
+			expErr: `notion: cannot map property "Score" of type "formula" to field Score of type int: number 4.5 can't be represented by field`,
+		},
+		{
+			name:   "not a pointer",
+			dst:    task{},
+			expErr: "notion: UnmarshalProperties: dst must be a non-nil pointer to a struct",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := notion.UnmarshalProperties(page, tt.dst)
+			if err == nil || err.Error() != tt.expErr {
+				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+			}
+		})
+	}
+
+	var typeErr *notion.PropertyTypeError
+	err := notion.UnmarshalProperties(page, &struct {
+		Done string `notion:"Done"`
+	}{})
+	if !errors.As(err, &typeErr) || typeErr.PropType != notion.DBPropTypeCheckbox {
+		t.Fatalf("expected property type error, got: %v", err)
+	}
+}
+
+func TestUnmarshalPropertiesNumbers(t *testing.T) {
+	t.Parallel()
+
+	type (
+		intFields struct {
+			N int64 `notion:"N"`
+		}
+		int8Fields struct {
+			N int8 `notion:"N"`
+		}
+		uintFields struct {
+			N uint64 `notion:"N"`
+		}
+	)
+
+	tests := []struct {
+		name   string
+		number float64
+		dst    interface{}
+		exp    interface{}
+		expErr string
+	}{
+		{
+			name:   "min int64",
+			number: math.MinInt64,
+			dst:    &intFields{},
+			exp:    &intFields{N: math.MinInt64},
+		},
+		{
+			name:   "int64 overflow",
+			number: 1e19,
+			dst:    &intFields{},
+			expErr: `notion: cannot map property "N" of type "number" to field intFields.N of type int64: number 1e+19 can't be represented by field`,
+		},
+		{
+			name:   "int8 overflow",
+			number: 300,
+			dst:    &int8Fields{},
+			expErr: `notion: cannot map property "N" of type "number" to field int8Fields.N of type int8: number 300 can't be represented by field`,
+		},
+		{
+			name:   "uint64",
+			number: 1e19,
+			dst:    &uintFields{},
+			exp:    &uintFields{N: 1e19},
+		},
+		{
+			name:   "uint64 overflow",
+			number: 1e20,
+			dst:    &uintFields{},
+			expErr: `notion: cannot map property "N" of type "number" to field uintFields.N of type uint64: number 1e+20 can't be represented by field`,
+		},
+		{
+			name:   "negative uint64",
+			number: -1,
+			dst:    &uintFields{},
+			expErr: `notion: cannot map property "N" of type "number" to field uintFields.N of type uint64: number -1 can't be represented by field`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			page := notion.Page{Properties: notion.DatabasePageProperties{
+				"N": {Type: notion.DBPropTypeNumber, Number: notion.Float64Ptr(tt.number)},
+			}}
+
+			err := notion.UnmarshalProperties(page, tt.dst)
+			if tt.expErr != "" {
+				if err == nil || err.Error() != tt.expErr {
+					t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.exp, tt.dst); diff != "" {
+				t.Fatalf("struct not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestMarshalProperties(t *testing.T) {
+	t.Parallel()
+
+	type row struct {
+		Name     string          `notion:"Name,title"`
+		Notes    *string         `notion:"Notes"`
+		Status   string          `notion:"Status,status"`
+		Tags     []string        `notion:"Tags"`
+		Estimate int             `notion:"Estimate"`
+		Done     bool            `notion:"Done"`
+		Day      notion.DateTime `notion:"Day"`
+		Blockers []string        `notion:"Blockers,relation"`
+		Link     string          `notion:"Link,url,omitempty"`
+		Created  time.Time       `notion:"Created,created_time"`
+
+		Score notion.DatabasePageProperty `notion:"Score,formula"`
+	}
+
+	day, _ := notion.ParseDateTime("2021-05-18")
+
+	props, err := notion.MarshalProperties(row{
+		Name:     "Write docs",
+		Status:   "Done",
+		Tags:     []string{"docs"},
+		Estimate: 3,
+		Done:     true,
+		Day:      day,
+		Blockers: []string{"rel-1"},
+		Score: notion.DatabasePageProperty{
+			Type:    notion.DBPropTypeFormula,
+			Formula: &notion.FormulaResult{Type: notion.FormulaResultTypeNumber, Number: notion.Float64Ptr(42)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := json.Marshal(props)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `{
+		"Blockers": {"type": "relation", "relation": [{"id": "rel-1"}]},
+		"Day": {"type": "date", "date": {"start": "2021-05-18"}},
+		"Done": {"type": "checkbox", "checkbox": true},
+		"Estimate": {"type": "number", "number": 3},
+		"Name": {"type": "title", "title": [{"text": {"content": "Write docs"}}]},
+		"Status": {"type": "status", "status": {"name": "Done"}},
+		"Tags": {"type": "multi_select", "multi_select": [{"name": "docs"}]}
+	}`
+
+	var expMap, gotMap map[string]interface{}
+	if err := json.Unmarshal([]byte(exp), &expMap); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(got, &gotMap); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expMap, gotMap); diff != "" {
+		t.Fatalf("properties not equal (-exp, +got):\n%v", diff)
+	}
+
+	t.Run("splits long text", func(t *testing.T) {
+		props, err := notion.MarshalProperties(struct {
+			Notes string `notion:"Notes"`
+		}{Notes: strings.Repeat("a", 2500)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n := len(props["Notes"].RichText); n != 2 {
+			t.Fatalf("expected 2 rich text objects, got %v", n)
+		}
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		_, err := notion.MarshalProperties(struct {
+			Done string `notion:"Done,checkbox"`
+		}{})
+		expErr := `notion: cannot map property "Done" of type "checkbox" to field Done of type string: unsupported field type`
+		if err == nil || err.Error() != expErr {
+			t.Fatalf("error not equal (expected: %v, got: %v)", expErr, err)
+		}
+	})
+}
+
+func TestTextRichText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    string
+		exp  []string
+	}{
+		{
+			name: "empty",
+			s:    "",
+			exp:  nil,
+		},
+		{
+			name: "short",
+			s:    "Foobar",
+			exp:  []string{"Foobar"},
+		},
+		{
+			name: "split at 2000 characters",
+			s:    strings.Repeat("a", 2001),
+			exp:  []string{strings.Repeat("a", 2000), "a"},
+		},
+		{
+			name: "surrogate pairs count double and aren't split",
+			s:    "a" + strings.Repeat("😀", 1000),
+			exp:  []string{"a" + strings.Repeat("😀", 999), "😀"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, rt := range notion.TextRichText(tt.s) {
+				got = append(got, rt.Text.Content)
+			}
+
+			if diff := cmp.Diff(tt.exp, got); diff != "" {
+				t.Fatalf("chunks not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,684 @@
+package notion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QueryError is returned by QueryPages, MatchFilter and SortPages when a query
+// is invalid, e.g. when it refers to an unknown property. Its message matches
+// the validation error the API returns for the same query.
+type QueryError struct {
+	Message string
+}
+
+// Error implements `error`.
+func (err *QueryError) Error() string {
+	return "notion: invalid query: " + err.Message
+}
+
+func errQuery(format string, a ...interface{}) *QueryError {
+	return &QueryError{Message: fmt.Sprintf(format, a...)}
+}
+
+// QueryPages evaluates a database query locally, without calling the API. It
+// returns the pages that match the query filter, sorted by the query sorts.
+// Properties are resolved by name or ID in schema, and relative date filters
+// (e.g. `past_week`) are relative to now. The start cursor and page size of
+// the query are ignored, and pages isn't modified.
+// See: https://developers.notion.com/reference/post-database-query
+func QueryPages(pages []Page, query DatabaseQuery, schema DatabaseProperties, now time.Time) ([]Page, error) {
+	result := make([]Page, 0, len(pages))
+	for _, page := range pages {
+		if query.Filter != nil {
+			ok, err := MatchFilter(*query.Filter, page, schema, now)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		result = append(result, page)
+	}
+
+	if err := SortPages(result, query.Sorts, schema); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// MatchFilter reports whether a database page matches a query filter. Text
+// conditions are case insensitive, except for `equals` and `does_not_equal`.
+// Dates without time are compared by day, in UTC.
+// See: https://developers.notion.com/reference/post-database-query-filter
+func MatchFilter(filter DatabaseQueryFilter, page Page, schema DatabaseProperties, now time.Time) (bool, error) {
+	switch {
+	case len(filter.And) > 0:
+		for _, f := range filter.And {
+			ok, err := MatchFilter(f, page, schema, now)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case len(filter.Or) > 0:
+		for _, f := range filter.Or {
+			ok, err := MatchFilter(f, page, schema, now)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case filter.Timestamp != "":
+		return matchTimestamp(filter, page, now)
+	case filter.Property == "":
+		return false, errQuery("body failed validation: body.filter should define a property, timestamp, `or` or `and` filter.")
+	}
+
+	name, prop, ok := findProperty(schema, filter.Property)
+	if !ok {
+		return false, errQuery("Could not find property with name or id: %v", filter.Property)
+	}
+
+	kind := filterKind(filter.DatabaseQueryPropertyFilter)
+	if kind == "" {
+		return false, errQuery("body failed validation: body.filter should define a condition for property %v.", filter.Property)
+	}
+	if kind != prop.Type {
+		return false, errQuery("database property %v does not match filter %v", prop.Type, kind)
+	}
+
+	props, _ := page.Properties.(DatabasePageProperties)
+
+	return matchProperty(filter.DatabaseQueryPropertyFilter, props[name], now), nil
+}
+
+func matchTimestamp(filter DatabaseQueryFilter, page Page, now time.Time) (bool, error) {
+	switch filter.Timestamp {
+	case TimestampCreatedTime:
+		if filter.CreatedTime == nil {
+			return false, errQuery("body failed validation: body.filter.created_time should be defined, instead was `undefined`.")
+		}
+		return matchDate(*filter.CreatedTime, &page.CreatedTime, true, now), nil
+	case TimestampLastEditedTime:
+		if filter.LastEditedTime == nil {
+			return false, errQuery("body failed validation: body.filter.last_edited_time should be defined, instead was `undefined`.")
+		}
+		return matchDate(*filter.LastEditedTime, &page.LastEditedTime, true, now), nil
+	}
+
+	return false, errQuery("body failed validation: body.filter.timestamp should be `\"created_time\"` or `\"last_edited_time\"`, instead was `%q`.", filter.Timestamp)
+}
+
+// filterKind returns the property type that a property filter is for.
+func filterKind(f DatabaseQueryPropertyFilter) DatabasePropertyType {
+	switch {
+	case f.Title != nil:
+		return DBPropTypeTitle
+	case f.RichText != nil:
+		return DBPropTypeRichText
+	case f.URL != nil:
+		return DBPropTypeURL
+	case f.Email != nil:
+		return DBPropTypeEmail
+	case f.PhoneNumber != nil:
+		return DBPropTypePhoneNumber
+	case f.Number != nil:
+		return DBPropTypeNumber
+	case f.Checkbox != nil:
+		return DBPropTypeCheckbox
+	case f.Select != nil:
+		return DBPropTypeSelect
+	case f.MultiSelect != nil:
+		return DBPropTypeMultiSelect
+	case f.Status != nil:
+		return DBPropTypeStatus
+	case f.Date != nil:
+		return DBPropTypeDate
+	case f.People != nil:
+		return DBPropTypePeople
+	case f.Files != nil:
+		return DBPropTypeFiles
+	case f.Relation != nil:
+		return DBPropTypeRelation
+	case f.Formula != nil:
+		return DBPropTypeFormula
+	case f.Rollup != nil:
+		return DBPropTypeRollup
+	case f.CreatedTime != nil:
+		return DBPropTypeCreatedTime
+	case f.LastEditedTime != nil:
+		return DBPropTypeLastEditedTime
+	case f.CreatedBy != nil:
+		return DBPropTypeCreatedBy
+	case f.LastEditedBy != nil:
+		return DBPropTypeLastEditedBy
+	case f.UniqueID != nil:
+		return DBPropTypeUniqueID
+	}
+
+	return ""
+}
+
+// matchProperty reports whether a property value matches the condition of a
+// property filter.
+func matchProperty(f DatabaseQueryPropertyFilter, value DatabasePageProperty, now time.Time) bool {
+	switch {
+	case f.Title != nil:
+		return matchText(*f.Title, PlainText(value.Title))
+	case f.RichText != nil:
+		return matchText(*f.RichText, PlainText(value.RichText))
+	case f.URL != nil:
+		return matchText(*f.URL, stringValue(value.URL))
+	case f.Email != nil:
+		return matchText(*f.Email, stringValue(value.Email))
+	case f.PhoneNumber != nil:
+		return matchText(*f.PhoneNumber, stringValue(value.PhoneNumber))
+	case f.Number != nil:
+		return matchNumber(*f.Number, value.Number)
+	case f.Checkbox != nil:
+		return matchCheckbox(*f.Checkbox, value.Checkbox != nil && *value.Checkbox)
+	case f.Select != nil:
+		return matchSelect(f.Select.Equals, f.Select.DoesNotEqual, f.Select.IsEmpty, f.Select.IsNotEmpty, value.Select)
+	case f.Status != nil:
+		return matchSelect(f.Status.Equals, f.Status.DoesNotEqual, f.Status.IsEmpty, f.Status.IsNotEmpty, value.Status)
+	case f.MultiSelect != nil:
+		names := make([]string, len(value.MultiSelect))
+		for i, option := range value.MultiSelect {
+			names[i] = option.Name
+		}
+		return matchContains(f.MultiSelect.Contains, f.MultiSelect.DoesNotContain, f.MultiSelect.IsEmpty, f.MultiSelect.IsNotEmpty, names)
+	case f.People != nil:
+		return matchPeople(*f.People, value.People)
+	case f.CreatedBy != nil:
+		return matchPeople(*f.CreatedBy, userSlice(value.CreatedBy))
+	case f.LastEditedBy != nil:
+		return matchPeople(*f.LastEditedBy, userSlice(value.LastEditedBy))
+	case f.Relation != nil:
+		ids := make([]string, len(value.Relation))
+		for i, relation := range value.Relation {
+			ids[i] = relation.ID
+		}
+		return matchContains(f.Relation.Contains, f.Relation.DoesNotContain, f.Relation.IsEmpty, f.Relation.IsNotEmpty, ids)
+	case f.UniqueID != nil:
+		var number *float64
+		if value.UniqueID != nil {
+			n := float64(value.UniqueID.Number)
+			number = &n
+		}
+		return matchNumber(*f.UniqueID, number)
+	case f.Files != nil:
+		return (!f.Files.IsEmpty || len(value.Files) == 0) && (!f.Files.IsNotEmpty || len(value.Files) > 0)
+	case f.Date != nil:
+		return matchDateValue(*f.Date, value.Date, now)
+	case f.CreatedTime != nil:
+		return matchDate(*f.CreatedTime, value.CreatedTime, true, now)
+	case f.LastEditedTime != nil:
+		return matchDate(*f.LastEditedTime, value.LastEditedTime, true, now)
+	case f.Formula != nil:
+		return matchFormula(*f.Formula, value.Formula, now)
+	case f.Rollup != nil:
+		return matchRollup(*f.Rollup, value.Rollup, now)
+	}
+
+	return false
+}
+
+// matchText matches text case-insensitively, except for `equals` and
+// `does_not_equal` conditions.
+func matchText(f TextPropertyFilter, value string) bool {
+	lower := strings.ToLower(value)
+
+	switch {
+	case f.Equals != "":
+		return value == f.Equals
+	case f.DoesNotEqual != "":
+		return value != f.DoesNotEqual
+	case f.Contains != "":
+		return strings.Contains(lower, strings.ToLower(f.Contains))
+	case f.DoesNotContain != "":
+		return !strings.Contains(lower, strings.ToLower(f.DoesNotContain))
+	case f.StartsWith != "":
+		return strings.HasPrefix(lower, strings.ToLower(f.StartsWith))
+	case f.EndsWith != "":
+		return strings.HasSuffix(lower, strings.ToLower(f.EndsWith))
+	case f.IsEmpty:
+		return value == ""
+	case f.IsNotEmpty:
+		return value != ""
+	}
+
+	return true
+}
+
+// matchNumber matches a number value. Empty values only match `is_empty` and
+// `does_not_equal` conditions.
+func matchNumber(f NumberDatabaseQueryFilter, value *float64) bool {
+	switch {
+	case f.IsEmpty:
+		return value == nil
+	case f.IsNotEmpty:
+		return value != nil
+	case value == nil:
+		return f.DoesNotEqual != nil
+	case f.Equals != nil:
+		return *value == float64(*f.Equals)
+	case f.DoesNotEqual != nil:
+		return *value != float64(*f.DoesNotEqual)
+	case f.GreaterThan != nil:
+		return *value > float64(*f.GreaterThan)
+	case f.LessThan != nil:
+		return *value < float64(*f.LessThan)
+	case f.GreaterThanOrEqualTo != nil:
+		return *value >= float64(*f.GreaterThanOrEqualTo)
+	case f.LessThanOrEqualTo != nil:
+		return *value <= float64(*f.LessThanOrEqualTo)
+	}
+
+	return true
+}
+
+func matchCheckbox(f CheckboxDatabaseQueryFilter, value bool) bool {
+	switch {
+	case f.Equals != nil:
+		return value == *f.Equals
+	case f.DoesNotEqual != nil:
+		return value != *f.DoesNotEqual
+	}
+
+	return true
+}
+
+func matchSelect(equals, doesNotEqual string, isEmpty, isNotEmpty bool, value *SelectOptions) bool {
+	var name string
+	if value != nil {
+		name = value.Name
+	}
+
+	switch {
+	case equals != "":
+		return name == equals
+	case doesNotEqual != "":
+		return name != doesNotEqual
+	case isEmpty:
+		return name == ""
+	case isNotEmpty:
+		return name != ""
+	}
+
+	return true
+}
+
+func matchContains(contains, doesNotContain string, isEmpty, isNotEmpty bool, values []string) bool {
+	has := func(s string) bool {
+		for _, v := range values {
+			if v == s {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case contains != "":
+		return has(contains)
+	case doesNotContain != "":
+		return !has(doesNotContain)
+	case isEmpty:
+		return len(values) == 0
+	case isNotEmpty:
+		return len(values) > 0
+	}
+
+	return true
+}
+
+func matchPeople(f PeopleDatabaseQueryFilter, users []User) bool {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+
+	return matchContains(f.Contains, f.DoesNotContain, f.IsEmpty, f.IsNotEmpty, ids)
+}
+
+// matchDateValue matches the start of a date (range) value.
+func matchDateValue(f DatePropertyFilter, value *Date, now time.Time) bool {
+	if value == nil {
+		return matchDate(f, nil, false, now)
+	}
+	return matchDate(f, &value.Start.Time, value.Start.HasTime(), now)
+}
+
+// matchDate matches a date value. Dates without time are compared by day, in
+// UTC, so e.g. today's date is within both the past and the next week.
+func matchDate(f DatePropertyFilter, value *time.Time, hasTime bool, now time.Time) bool {
+	switch {
+	case f.IsEmpty:
+		return value == nil
+	case f.IsNotEmpty:
+		return value != nil
+	case value == nil:
+		return false
+	}
+
+	cmp := func(t time.Time) int {
+		a, b := *value, t
+		if !hasTime {
+			a, b = truncateDay(a), truncateDay(b)
+		}
+		switch {
+		case a.Before(b):
+			return -1
+		case a.After(b):
+			return 1
+		}
+		return 0
+	}
+	within := func(from, to time.Time) bool {
+		return cmp(from) >= 0 && cmp(to) <= 0
+	}
+
+	switch {
+	case f.Equals != nil:
+		return cmp(*f.Equals) == 0
+	case f.Before != nil:
+		return cmp(*f.Before) < 0
+	case f.After != nil:
+		return cmp(*f.After) > 0
+	case f.OnOrBefore != nil:
+		return cmp(*f.OnOrBefore) <= 0
+	case f.OnOrAfter != nil:
+		return cmp(*f.OnOrAfter) >= 0
+	case f.PastWeek != nil:
+		return within(now.AddDate(0, 0, -7), now)
+	case f.PastMonth != nil:
+		return within(now.AddDate(0, -1, 0), now)
+	case f.PastYear != nil:
+		return within(now.AddDate(-1, 0, 0), now)
+	case f.NextWeek != nil:
+		return within(now, now.AddDate(0, 0, 7))
+	case f.NextMonth != nil:
+		return within(now, now.AddDate(0, 1, 0))
+	case f.NextYear != nil:
+		return within(now, now.AddDate(1, 0, 0))
+	}
+
+	return true
+}
+
+// matchFormula matches the result of a formula. A missing result matches like
+// an empty value.
+func matchFormula(f FormulaDatabaseQueryFilter, value *FormulaResult, now time.Time) bool {
+	if value == nil {
+		value = &FormulaResult{}
+	}
+
+	switch {
+	case f.String != nil:
+		return matchText(*f.String, stringValue(value.String))
+	case f.Checkbox != nil:
+		return matchCheckbox(*f.Checkbox, value.Boolean != nil && *value.Boolean)
+	case f.Number != nil:
+		return matchNumber(*f.Number, value.Number)
+	case f.Date != nil:
+		return matchDateValue(*f.Date, value.Date, now)
+	}
+
+	return true
+}
+
+// matchRollup matches the result of a rollup. The `any`, `every` and `none`
+// conditions apply a property filter to the items of an array rollup; like
+// in Notion, `every` and `none` match empty arrays.
+func matchRollup(f RollupDatabaseQueryFilter, value *RollupResult, now time.Time) bool {
+	if value == nil {
+		value = &RollupResult{}
+	}
+
+	switch {
+	case f.Any != nil:
+		for _, item := range value.Array {
+			if matchProperty(*f.Any, item, now) {
+				return true
+			}
+		}
+		return false
+	case f.Every != nil:
+		for _, item := range value.Array {
+			if !matchProperty(*f.Every, item, now) {
+				return false
+			}
+		}
+		return true
+	case f.None != nil:
+		for _, item := range value.Array {
+			if matchProperty(*f.None, item, now) {
+				return false
+			}
+		}
+		return true
+	case f.Number != nil:
+		return matchNumber(*f.Number, value.Number)
+	case f.Date != nil:
+		return matchDateValue(*f.Date, value.Date, now)
+	}
+
+	return true
+}
+
+// SortPages sorts database pages in place, by the sorts of a database query.
+// Later sorts break ties of earlier ones, and the order of pages that compare
+// equal is kept. Like in Notion, pages with empty values are sorted last,
+// regardless of sort direction.
+// See: https://developers.notion.com/reference/post-database-query-sort
+func SortPages(pages []Page, sorts []DatabaseQuerySort, schema DatabaseProperties) error {
+	names := make([]string, len(sorts))
+	for i, s := range sorts {
+		switch {
+		case s.Timestamp != "":
+			if s.Timestamp != SortTimeStampCreatedTime && s.Timestamp != SortTimeStampLastEditedTime {
+				return errQuery("body failed validation: body.sorts[%v].timestamp should be `\"created_time\"` or `\"last_edited_time\"`, instead was `%q`.", i, s.Timestamp)
+			}
+		case s.Property != "":
+			name, _, ok := findProperty(schema, s.Property)
+			if !ok {
+				return errQuery("Could not find sort property with name or id: %v", s.Property)
+			}
+			names[i] = name
+		default:
+			return errQuery("body failed validation: body.sorts[%v] should define a property or timestamp.", i)
+		}
+	}
+
+	sort.SliceStable(pages, func(i, j int) bool {
+		for k, s := range sorts {
+			var a, b sortValue
+			switch s.Timestamp {
+			case SortTimeStampCreatedTime:
+				a, b = sortValue{t: pages[i].CreatedTime}, sortValue{t: pages[j].CreatedTime}
+			case SortTimeStampLastEditedTime:
+				a, b = sortValue{t: pages[i].LastEditedTime}, sortValue{t: pages[j].LastEditedTime}
+			default:
+				a = propertySortValue(pageProperty(pages[i], names[k]))
+				b = propertySortValue(pageProperty(pages[j], names[k]))
+			}
+
+			switch {
+			case a.empty && b.empty:
+				continue
+			case a.empty || b.empty:
+				return b.empty
+			}
+
+			c := a.compare(b)
+			if c == 0 {
+				continue
+			}
+			if s.Direction == SortDirDesc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+
+	return nil
+}
+
+// sortValue is a comparable representation of a property value.
+type sortValue struct {
+	empty bool
+	s     string
+	n     float64
+	t     time.Time
+}
+
+func (v sortValue) compare(other sortValue) int {
+	switch {
+	case v.s != other.s:
+		return strings.Compare(v.s, other.s)
+	case v.n < other.n:
+		return -1
+	case v.n > other.n:
+		return 1
+	case v.t.Before(other.t):
+		return -1
+	case v.t.After(other.t):
+		return 1
+	}
+	return 0
+}
+
+func propertySortValue(value DatabasePageProperty) sortValue {
+	var v sortValue
+
+	switch value.Type {
+	case DBPropTypeTitle:
+		v.s = strings.ToLower(PlainText(value.Title))
+		v.empty = v.s == ""
+	case DBPropTypeRichText:
+		v.s = strings.ToLower(PlainText(value.RichText))
+		v.empty = v.s == ""
+	case DBPropTypeURL, DBPropTypeEmail, DBPropTypePhoneNumber:
+		v.s = strings.ToLower(stringValue(value.URL) + stringValue(value.Email) + stringValue(value.PhoneNumber))
+		v.empty = v.s == ""
+	case DBPropTypeNumber:
+		v.empty = value.Number == nil
+		if value.Number != nil {
+			v.n = *value.Number
+		}
+	case DBPropTypeCheckbox:
+		if value.Checkbox != nil && *value.Checkbox {
+			v.n = 1
+		}
+	case DBPropTypeSelect:
+		v.empty = value.Select == nil
+		if value.Select != nil {
+			v.s = value.Select.Name
+		}
+	case DBPropTypeStatus:
+		v.empty = value.Status == nil
+		if value.Status != nil {
+			v.s = value.Status.Name
+		}
+	case DBPropTypeMultiSelect:
+		v.empty = len(value.MultiSelect) == 0
+		if !v.empty {
+			v.s = value.MultiSelect[0].Name
+		}
+	case DBPropTypeDate:
+		v.empty = value.Date == nil
+		if value.Date != nil {
+			v.t = value.Date.Start.Time
+		}
+	case DBPropTypeCreatedTime:
+		v.empty = value.CreatedTime == nil
+		if value.CreatedTime != nil {
+			v.t = *value.CreatedTime
+		}
+	case DBPropTypeLastEditedTime:
+		v.empty = value.LastEditedTime == nil
+		if value.LastEditedTime != nil {
+			v.t = *value.LastEditedTime
+		}
+	case DBPropTypePeople:
+		v.empty = len(value.People) == 0
+		if !v.empty {
+			v.s = value.People[0].Name
+		}
+	case DBPropTypeFormula:
+		v.empty = value.Formula == nil
+		if value.Formula != nil {
+			v.s = stringValue(value.Formula.String)
+			if value.Formula.Number != nil {
+				v.n = *value.Formula.Number
+			}
+			if value.Formula.Boolean != nil && *value.Formula.Boolean {
+				v.n = 1
+			}
+			if value.Formula.Date != nil {
+				v.t = value.Formula.Date.Start.Time
+			}
+		}
+	case DBPropTypeRollup:
+		v.empty = value.Rollup == nil || (value.Rollup.Number == nil && value.Rollup.Date == nil)
+		if value.Rollup != nil {
+			if value.Rollup.Number != nil {
+				v.n = *value.Rollup.Number
+			}
+			if value.Rollup.Date != nil {
+				v.t = value.Rollup.Date.Start.Time
+			}
+		}
+	default:
+		v.empty = true
+	}
+
+	return v
+}
+
+// findProperty finds a database property by name or by ID, as both can be
+// used to refer to properties.
+func findProperty(props DatabaseProperties, nameOrID string) (string, DatabaseProperty, bool) {
+	if prop, ok := props[nameOrID]; ok {
+		return nameOrID, prop, true
+	}
+
+	// Iterate in a stable order, in case of duplicate IDs.
+	for _, name := range sortedPropertyNames(props) {
+		if props[name].ID == nameOrID {
+			return name, props[name], true
+		}
+	}
+
+	return "", DatabaseProperty{}, false
+}
+
+func pageProperty(page Page, name string) DatabasePageProperty {
+	props, _ := page.Properties.(DatabasePageProperties)
+	return props[name]
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func userSlice(user *User) []User {
+	if user == nil {
+		return nil
+	}
+	return []User{*user}
+}
@@ -0,0 +1,397 @@
+package query
+
+import (
+	"math"
+	"strings"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/filter"
+)
+
+type operator string
+
+const (
+	opEquals         operator = "="
+	opDoesNotEqual   operator = "!="
+	opLessThan       operator = "<"
+	opLessOrEqual    operator = "<="
+	opGreaterThan    operator = ">"
+	opGreaterOrEqual operator = ">="
+	opContains       operator = "contains"
+	opDoesNotContain operator = "not contains"
+	opStartsWith     operator = "starts with"
+	opEndsWith       operator = "ends with"
+	opIsEmpty        operator = "is empty"
+	opIsNotEmpty     operator = "is not empty"
+	opWithin         operator = "within"
+)
+
+// condition is a parsed condition, before it's compiled for a property type.
+type condition struct {
+	op    operator
+	opTok token
+
+	// value is the value of comparison, contains, starts and ends with
+	// conditions.
+	value token
+
+	// period is the relative date range of `within` conditions, e.g.
+	// "past week".
+	period string
+}
+
+func (p *parser) parseOperator() (condition, error) {
+	tok := p.next()
+	cond := condition{opTok: tok}
+
+	switch {
+	case tok.kind == tokOperator:
+		cond.op = operator(tok.text)
+	case tok.isKeyword("contains"):
+		cond.op = opContains
+	case tok.isKeyword("not"):
+		if err := p.expectKeyword("contains"); err != nil {
+			return condition{}, err
+		}
+		cond.op = opDoesNotContain
+	case tok.isKeyword("starts"):
+		if err := p.expectKeyword("with"); err != nil {
+			return condition{}, err
+		}
+		cond.op = opStartsWith
+	case tok.isKeyword("ends"):
+		if err := p.expectKeyword("with"); err != nil {
+			return condition{}, err
+		}
+		cond.op = opEndsWith
+	case tok.isKeyword("is"):
+		cond.op = opIsEmpty
+		if p.acceptKeyword("not") {
+			cond.op = opIsNotEmpty
+		}
+		if err := p.expectKeyword("empty"); err != nil {
+			return condition{}, err
+		}
+		return cond, nil
+	case tok.isKeyword("within"):
+		cond.op = opWithin
+		switch when := p.next(); {
+		case when.isKeyword("past"), when.isKeyword("next"):
+			cond.period = when.text
+		default:
+			return condition{}, p.errorf(when, "expected \"past\" or \"next\", found %v", when)
+		}
+		switch unit := p.next(); {
+		case unit.isKeyword("week"), unit.isKeyword("month"), unit.isKeyword("year"):
+			cond.period += " " + unit.text
+		default:
+			return condition{}, p.errorf(unit, "expected \"week\", \"month\" or \"year\", found %v", unit)
+		}
+		return cond, nil
+	default:
+		return condition{}, p.errorf(tok, "expected operator, found %v", tok)
+	}
+
+	cond.value = p.next()
+	switch {
+	case cond.value.kind == tokString, cond.value.kind == tokNumber, cond.value.kind == tokDate,
+		cond.value.isKeyword("true"), cond.value.isKeyword("false"):
+	default:
+		return condition{}, p.errorf(cond.value, "expected value, found %v", cond.value)
+	}
+
+	return cond, nil
+}
+
+// compile compiles a condition to the filter for the type of a property.
+func (p *parser) compile(ref propertyRef, cond condition) (filter.Filter, error) {
+	if ref.timestamp != "" {
+		if ref.timestamp == notion.TimestampCreatedTime {
+			return p.dateFilter(ref, filter.CreatedTime(), cond)
+		}
+		return p.dateFilter(ref, filter.LastEditedTime(), cond)
+	}
+
+	prop := filter.Prop(ref.name)
+
+	switch ref.prop.Type {
+	case notion.DBPropTypeTitle:
+		return p.textFilter(ref, prop.Title(), cond)
+	case notion.DBPropTypeRichText:
+		return p.textFilter(ref, prop.RichText(), cond)
+	case notion.DBPropTypeURL:
+		return p.textFilter(ref, prop.URL(), cond)
+	case notion.DBPropTypeEmail:
+		return p.textFilter(ref, prop.Email(), cond)
+	case notion.DBPropTypePhoneNumber:
+		return p.textFilter(ref, prop.PhoneNumber(), cond)
+	case notion.DBPropTypeNumber:
+		return p.numberFilter(ref, prop.Number(), cond)
+	case notion.DBPropTypeCheckbox:
+		return p.checkboxFilter(ref, prop.Checkbox(), cond)
+	case notion.DBPropTypeSelect:
+		return p.selectFilter(ref, prop.Select(), cond)
+	case notion.DBPropTypeStatus:
+		return p.selectFilter(ref, prop.Status(), cond)
+	case notion.DBPropTypeMultiSelect:
+		return p.containsFilter(ref, prop.MultiSelect(), cond)
+	case notion.DBPropTypePeople:
+		return p.containsFilter(ref, prop.People(), cond)
+	case notion.DBPropTypeCreatedBy:
+		return p.containsFilter(ref, prop.CreatedBy(), cond)
+	case notion.DBPropTypeLastEditedBy:
+		return p.containsFilter(ref, prop.LastEditedBy(), cond)
+	case notion.DBPropTypeRelation:
+		return p.containsFilter(ref, prop.Relation(), cond)
+	case notion.DBPropTypeFiles:
+		return p.filesFilter(ref, prop.Files(), cond)
+	case notion.DBPropTypeDate:
+		return p.dateFilter(ref, prop.Date(), cond)
+	case notion.DBPropTypeCreatedTime:
+		return p.dateFilter(ref, prop.CreatedTime(), cond)
+	case notion.DBPropTypeLastEditedTime:
+		return p.dateFilter(ref, prop.LastEditedTime(), cond)
+	case notion.DBPropTypeFormula:
+		return p.formulaFilter(ref, prop.Formula(), cond)
+	case notion.DBPropTypeRollup:
+		return p.rollupFilter(ref, prop.Rollup(), cond)
+	}
+
+	return filter.Filter{}, p.errorf(ref.tok, "%v can't be filtered", ref.describe())
+}
+
+func (p *parser) textFilter(ref propertyRef, c filter.TextCondition, cond condition) (filter.Filter, error) {
+	switch cond.op {
+	case opIsEmpty:
+		return c.IsEmpty(), nil
+	case opIsNotEmpty:
+		return c.IsNotEmpty(), nil
+	case opLessThan, opLessOrEqual, opGreaterThan, opGreaterOrEqual, opWithin:
+		return filter.Filter{}, p.unsupported(ref, cond)
+	}
+
+	value, err := p.stringValue(ref, cond)
+	if err != nil {
+		return filter.Filter{}, err
+	}
+
+	switch cond.op {
+	case opEquals:
+		return c.Equals(value), nil
+	case opDoesNotEqual:
+		return c.DoesNotEqual(value), nil
+	case opContains:
+		return c.Contains(value), nil
+	case opDoesNotContain:
+		return c.DoesNotContain(value), nil
+	case opStartsWith:
+		return c.StartsWith(value), nil
+	default:
+		return c.EndsWith(value), nil
+	}
+}
+
+func (p *parser) numberFilter(ref propertyRef, c filter.NumberCondition, cond condition) (filter.Filter, error) {
+	switch cond.op {
+	case opIsEmpty:
+		return c.IsEmpty(), nil
+	case opIsNotEmpty:
+		return c.IsNotEmpty(), nil
+	case opContains, opDoesNotContain, opStartsWith, opEndsWith, opWithin:
+		return filter.Filter{}, p.unsupported(ref, cond)
+	}
+
+	if cond.value.kind != tokNumber {
+		return filter.Filter{}, p.errorf(cond.value, "expected number for %v, found %v", ref.describe(), cond.value)
+	}
+	// Values of notion.NumberDatabaseQueryFilter are ints.
+	n := cond.value.num
+	if n != math.Trunc(n) || n < math.MinInt || n >= math.MaxInt {
+		return filter.Filter{}, p.errorf(cond.value, "expected integer for %v, found %v", ref.describe(), cond.value)
+	}
+	value := int(n)
+
+	switch cond.op {
+	case opEquals:
+		return c.Equals(value), nil
+	case opDoesNotEqual:
+		return c.DoesNotEqual(value), nil
+	case opLessThan:
+		return c.LessThan(value), nil
+	case opLessOrEqual:
+		return c.LessThanOrEqualTo(value), nil
+	case opGreaterThan:
+		return c.GreaterThan(value), nil
+	default:
+		return c.GreaterThanOrEqualTo(value), nil
+	}
+}
+
+func (p *parser) checkboxFilter(ref propertyRef, c filter.CheckboxCondition, cond condition) (filter.Filter, error) {
+	if cond.op != opEquals && cond.op != opDoesNotEqual {
+		return filter.Filter{}, p.unsupported(ref, cond)
+	}
+
+	var value bool
+	switch {
+	case cond.value.isKeyword("true"):
+		value = true
+	case cond.value.isKeyword("false"):
+	default:
+		return filter.Filter{}, p.errorf(cond.value, "expected true or false for %v, found %v", ref.describe(), cond.value)
+	}
+
+	if cond.op == opEquals {
+		return c.Equals(value), nil
+	}
+	return c.DoesNotEqual(value), nil
+}
+
+func (p *parser) selectFilter(ref propertyRef, c filter.SelectCondition, cond condition) (filter.Filter, error) {
+	switch cond.op {
+	case opIsEmpty:
+		return c.IsEmpty(), nil
+	case opIsNotEmpty:
+		return c.IsNotEmpty(), nil
+	case opEquals, opDoesNotEqual:
+	default:
+		return filter.Filter{}, p.unsupported(ref, cond)
+	}
+
+	value, err := p.stringValue(ref, cond)
+	if err != nil {
+		return filter.Filter{}, err
+	}
+
+	if cond.op == opEquals {
+		return c.Equals(value), nil
+	}
+	return c.DoesNotEqual(value), nil
+}
+
+func (p *parser) containsFilter(ref propertyRef, c filter.ContainsCondition, cond condition) (filter.Filter, error) {
+	switch cond.op {
+	case opIsEmpty:
+		return c.IsEmpty(), nil
+	case opIsNotEmpty:
+		return c.IsNotEmpty(), nil
+	case opContains, opDoesNotContain:
+	default:
+		return filter.Filter{}, p.unsupported(ref, cond)
+	}
+
+	value, err := p.stringValue(ref, cond)
+	if err != nil {
+		return filter.Filter{}, err
+	}
+
+	if cond.op == opContains {
+		return c.Contains(value), nil
+	}
+	return c.DoesNotContain(value), nil
+}
+
+func (p *parser) filesFilter(ref propertyRef, c filter.FilesCondition, cond condition) (filter.Filter, error) {
+	switch cond.op {
+	case opIsEmpty:
+		return c.IsEmpty(), nil
+	case opIsNotEmpty:
+		return c.IsNotEmpty(), nil
+	}
+
+	return filter.Filter{}, p.unsupported(ref, cond)
+}
+
+func (p *parser) dateFilter(ref propertyRef, c filter.DateCondition, cond condition) (filter.Filter, error) {
+	switch cond.op {
+	case opIsEmpty:
+		return c.IsEmpty(), nil
+	case opIsNotEmpty:
+		return c.IsNotEmpty(), nil
+	case opWithin:
+		return relativeDateFilter(c, cond.period), nil
+	case opDoesNotEqual, opContains, opDoesNotContain, opStartsWith, opEndsWith:
+		return filter.Filter{}, p.unsupported(ref, cond)
+	}
+
+	if cond.value.kind != tokDate {
+		return filter.Filter{}, p.errorf(cond.value, "expected date for %v, found %v", ref.describe(), cond.value)
+	}
+	t := cond.value.date
+
+	switch cond.op {
+	case opEquals:
+		return c.Equals(t), nil
+	case opLessThan:
+		return c.Before(t), nil
+	case opLessOrEqual:
+		return c.OnOrBefore(t), nil
+	case opGreaterThan:
+		return c.After(t), nil
+	default:
+		return c.OnOrAfter(t), nil
+	}
+}
+
+func relativeDateFilter(c filter.DateCondition, period string) filter.Filter {
+	switch strings.ToLower(period) {
+	case "past week":
+		return c.PastWeek()
+	case "past month":
+		return c.PastMonth()
+	case "past year":
+		return c.PastYear()
+	case "next week":
+		return c.NextWeek()
+	case "next month":
+		return c.NextMonth()
+	default:
+		return c.NextYear()
+	}
+}
+
+// formulaFilter compiles a formula condition by the type of its value, as the
+// result type of formulas isn't part of the database schema.
+func (p *parser) formulaFilter(ref propertyRef, c filter.FormulaCondition, cond condition) (filter.Filter, error) {
+	switch {
+	case cond.op == opWithin || cond.value.kind == tokDate:
+		return p.dateFilter(ref, c.Date(), cond)
+	case cond.value.kind == tokNumber:
+		return p.numberFilter(ref, c.Number(), cond)
+	case cond.value.isKeyword("true"), cond.value.isKeyword("false"):
+		return p.checkboxFilter(ref, c.Checkbox(), cond)
+	case cond.value.kind == tokString:
+		return p.textFilter(ref, c.String(), cond)
+	}
+
+	return filter.Filter{}, p.errorf(cond.opTok, "can't use %q with %v, as its result type is unknown", cond.op, ref.describe())
+}
+
+// rollupFilter compiles a rollup condition by the type of its value. Only
+// number and date rollups are supported.
+func (p *parser) rollupFilter(ref propertyRef, c filter.RollupCondition, cond condition) (filter.Filter, error) {
+	switch {
+	case cond.op == opWithin || cond.value.kind == tokDate:
+		return p.dateFilter(ref, c.Date(), cond)
+	case cond.value.kind == tokNumber:
+		return p.numberFilter(ref, c.Number(), cond)
+	case cond.value.text == "":
+		return filter.Filter{}, p.errorf(cond.opTok, "can't use %q with %v, as its result type is unknown", cond.op, ref.describe())
+	}
+
+	return filter.Filter{}, p.errorf(cond.value, "expected number or date for %v, found %v", ref.describe(), cond.value)
+}
+
+func (p *parser) stringValue(ref propertyRef, cond condition) (string, error) {
+	if cond.value.kind != tokString {
+		return "", p.errorf(cond.value, "expected string for %v, found %v", ref.describe(), cond.value)
+	}
+	if cond.value.str == "" {
+		return "", p.errorf(cond.value, "empty string for %v, use \"is empty\" or \"is not empty\" instead", ref.describe())
+	}
+	return cond.value.str, nil
+}
+
+func (p *parser) unsupported(ref propertyRef, cond condition) error {
+	return p.errorf(cond.opTok, "can't use %q with %v", cond.op, ref.describe())
+}
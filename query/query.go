@@ -0,0 +1,320 @@
+// Package query parses a small query language into database queries, e.g. for
+// command line tools:
+//
+//	q, err := query.Parse(`status = "Done" and due < 2024-01-01 order by due desc`, db)
+//	if err != nil {
+//		// Handle error...
+//	}
+//
+//	pages, err := client.QueryDatabaseAll(ctx, db.ID, &q)
+//
+// A query is an optional filter, followed by an optional `order by` clause:
+//
+//	query     = [ filter ] [ "order" "by" sort { "," sort } ]
+//	filter    = and { "or" and }
+//	and       = primary { "and" primary }
+//	primary   = "(" filter ")" | property condition
+//	condition = ( "=" | "!=" | "<" | "<=" | ">" | ">=" ) value
+//	          | [ "not" ] "contains" value
+//	          | ( "starts" | "ends" ) "with" value
+//	          | "is" [ "not" ] "empty"
+//	          | "within" ( "past" | "next" ) ( "week" | "month" | "year" )
+//	sort      = property [ "asc" | "desc" ]
+//
+// Keywords are case insensitive. Values are strings ("Done"), numbers (3),
+// booleans (true, false) and dates (2024-01-01 or 2024-01-01T09:00:00Z).
+//
+// Properties are referred to by name or ID, ignoring case if there is no
+// exact match. Names that aren't a single word, or that are keywords, are
+// quoted with backticks: `Due date`. The `created_time` and `last_edited_time`
+// timestamps of pages can be used like properties, unless the database has
+// properties with those names.
+//
+// Conditions are compiled to the filter for the property type, and must be
+// valid for it; e.g. `contains` can be used with text, multi-select, people
+// and relation properties, but not with select properties. Formula and rollup
+// conditions are compiled by the type of their value.
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/filter"
+)
+
+// Error is a syntax or type error in a query, at a position in its source.
+type Error struct {
+	// Column is the 1-based position of the error in the query, in runes.
+	Column  int
+	Message string
+}
+
+// Error implements `error`.
+func (err *Error) Error() string {
+	return fmt.Sprintf("query: column %v: %v", err.Column, err.Message)
+}
+
+func newError(src string, pos int, format string, a ...interface{}) *Error {
+	return &Error{
+		Column:  utf8.RuneCountInString(src[:pos]) + 1,
+		Message: fmt.Sprintf(format, a...),
+	}
+}
+
+// Parse parses a query, and compiles it to a database query for db. Property
+// names are resolved against the database properties. Errors are of type
+// *Error.
+func Parse(src string, db notion.Database) (notion.DatabaseQuery, error) {
+	tokens, err := scan(src)
+	if err != nil {
+		return notion.DatabaseQuery{}, err
+	}
+
+	p := &parser{src: src, props: db.Properties, tokens: tokens}
+
+	return p.parseQuery()
+}
+
+type parser struct {
+	src    string
+	props  notion.DatabaseProperties
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.i]
+	if tok.kind != tokEOF {
+		p.i++
+	}
+	return tok
+}
+
+// acceptKeyword consumes the next token if it's the keyword kw.
+func (p *parser) acceptKeyword(kw string) bool {
+	if p.peek().isKeyword(kw) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(kw string) error {
+	if !p.acceptKeyword(kw) {
+		return p.errorf(p.peek(), "expected %q, found %v", kw, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) errorf(tok token, format string, a ...interface{}) *Error {
+	return newError(p.src, tok.pos, format, a...)
+}
+
+func (p *parser) column(tok token) int {
+	return utf8.RuneCountInString(p.src[:tok.pos]) + 1
+}
+
+func (p *parser) parseQuery() (notion.DatabaseQuery, error) {
+	var q notion.DatabaseQuery
+
+	if p.peek().kind != tokEOF && !p.peek().isKeyword("order") {
+		f, err := p.parseOr()
+		if err != nil {
+			return notion.DatabaseQuery{}, err
+		}
+		q.Filter = f.MustBuild()
+	}
+
+	if p.acceptKeyword("order") {
+		if err := p.expectKeyword("by"); err != nil {
+			return notion.DatabaseQuery{}, err
+		}
+		for {
+			sort, err := p.parseSort()
+			if err != nil {
+				return notion.DatabaseQuery{}, err
+			}
+			q.Sorts = append(q.Sorts, sort)
+
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if tok := p.peek(); tok.kind != tokEOF {
+		return notion.DatabaseQuery{}, p.errorf(tok, "unexpected %v", tok)
+	}
+
+	return q, nil
+}
+
+func (p *parser) parseOr() (filter.Filter, error) {
+	f, err := p.parseAnd()
+	if err != nil {
+		return filter.Filter{}, err
+	}
+
+	for p.peek().isKeyword("or") {
+		op := p.next()
+		other, err := p.parseAnd()
+		if err != nil {
+			return filter.Filter{}, err
+		}
+		if f = f.Or(other); f.Err() != nil {
+			return filter.Filter{}, p.errorf(op, "%v", strings.TrimPrefix(f.Err().Error(), "filter: "))
+		}
+	}
+
+	return f, nil
+}
+
+func (p *parser) parseAnd() (filter.Filter, error) {
+	f, err := p.parsePrimary()
+	if err != nil {
+		return filter.Filter{}, err
+	}
+
+	for p.peek().isKeyword("and") {
+		op := p.next()
+		other, err := p.parsePrimary()
+		if err != nil {
+			return filter.Filter{}, err
+		}
+		if f = f.And(other); f.Err() != nil {
+			return filter.Filter{}, p.errorf(op, "%v", strings.TrimPrefix(f.Err().Error(), "filter: "))
+		}
+	}
+
+	return f, nil
+}
+
+func (p *parser) parsePrimary() (filter.Filter, error) {
+	if p.peek().kind != tokLParen {
+		return p.parseCondition()
+	}
+
+	open := p.next()
+	f, err := p.parseOr()
+	if err != nil {
+		return filter.Filter{}, err
+	}
+	if tok := p.next(); tok.kind != tokRParen {
+		return filter.Filter{}, p.errorf(tok, "expected \")\" to close \"(\" at column %v, found %v", p.column(open), tok)
+	}
+
+	return f, nil
+}
+
+func (p *parser) parseSort() (notion.DatabaseQuerySort, error) {
+	ref, err := p.parseProperty()
+	if err != nil {
+		return notion.DatabaseQuerySort{}, err
+	}
+
+	sort := notion.DatabaseQuerySort{Property: ref.name, Timestamp: notion.SortTimestamp(ref.timestamp), Direction: notion.SortDirAsc}
+
+	switch {
+	case p.acceptKeyword("asc"), p.acceptKeyword("ascending"):
+	case p.acceptKeyword("desc"), p.acceptKeyword("descending"):
+		sort.Direction = notion.SortDirDesc
+	}
+
+	return sort, nil
+}
+
+// propertyRef is a resolved property, or a page timestamp.
+type propertyRef struct {
+	tok       token
+	name      string
+	prop      notion.DatabaseProperty
+	timestamp string
+}
+
+// describe returns a description of the property for error messages.
+func (ref propertyRef) describe() string {
+	if ref.timestamp != "" {
+		return fmt.Sprintf("timestamp %v", ref.timestamp)
+	}
+	return fmt.Sprintf("%v property %q", ref.prop.Type, ref.name)
+}
+
+func (p *parser) parseProperty() (propertyRef, error) {
+	tok := p.next()
+
+	var name string
+	switch tok.kind {
+	case tokWord:
+		name = tok.text
+	case tokQuotedName:
+		name = tok.str
+	default:
+		return propertyRef{}, p.errorf(tok, "expected property name, found %v", tok)
+	}
+
+	resolved, prop, err := findProperty(p.props, name)
+	if err != nil {
+		return propertyRef{}, p.errorf(tok, "%v", err)
+	}
+	if resolved != "" {
+		return propertyRef{tok: tok, name: resolved, prop: prop}, nil
+	}
+
+	for _, ts := range []string{notion.TimestampCreatedTime, notion.TimestampLastEditedTime} {
+		if strings.EqualFold(name, ts) {
+			return propertyRef{tok: tok, timestamp: ts}, nil
+		}
+	}
+
+	return propertyRef{}, p.errorf(tok, "unknown property %q", name)
+}
+
+// findProperty finds a property by name or ID. If neither matches exactly,
+// names are compared ignoring case. It returns an empty name if there's no
+// match.
+func findProperty(props notion.DatabaseProperties, nameOrID string) (string, notion.DatabaseProperty, error) {
+	if prop, ok := props[nameOrID]; ok {
+		return nameOrID, prop, nil
+	}
+
+	var matches []string
+	for name, prop := range props {
+		if prop.ID == nameOrID {
+			return name, prop, nil
+		}
+		if strings.EqualFold(name, nameOrID) {
+			matches = append(matches, name)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", notion.DatabaseProperty{}, nil
+	case 1:
+		return matches[0], props[matches[0]], nil
+	}
+
+	return "", notion.DatabaseProperty{}, fmt.Errorf("ambiguous property %q, quote the exact name with backticks", nameOrID)
+}
+
+func (p *parser) parseCondition() (filter.Filter, error) {
+	ref, err := p.parseProperty()
+	if err != nil {
+		return filter.Filter{}, err
+	}
+
+	cond, err := p.parseOperator()
+	if err != nil {
+		return filter.Filter{}, err
+	}
+
+	return p.compile(ref, cond)
+}
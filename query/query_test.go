@@ -0,0 +1,251 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/query"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	db := notion.Database{
+		ID: "db-id",
+		Properties: notion.DatabaseProperties{
+			"Name":     {ID: "title", Type: notion.DBPropTypeTitle},
+			"Status":   {ID: "status", Type: notion.DBPropTypeStatus},
+			"Due":      {ID: "due", Type: notion.DBPropTypeDate},
+			"Due date": {ID: "due-date", Type: notion.DBPropTypeDate},
+			"Points":   {ID: "points", Type: notion.DBPropTypeNumber},
+			"Priority": {ID: "priority", Type: notion.DBPropTypeSelect},
+			"Tags":     {ID: "tags", Type: notion.DBPropTypeMultiSelect},
+			"Urgent":   {ID: "urgent", Type: notion.DBPropTypeCheckbox},
+			"Assignee": {ID: "assignee", Type: notion.DBPropTypePeople},
+			"Score":    {ID: "score", Type: notion.DBPropTypeFormula},
+			"Total":    {ID: "total", Type: notion.DBPropTypeRollup},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		src    string
+		exp    notion.DatabaseQuery
+		expErr string
+	}{
+		{
+			name: "empty",
+			src:  "  ",
+			exp:  notion.DatabaseQuery{},
+		},
+		{
+			name: "filter and sort",
+			src:  `status = "Done" and due < 2024-01-01 order by due desc`,
+			exp: notion.DatabaseQuery{
+				Filter: &notion.DatabaseQueryFilter{And: []notion.DatabaseQueryFilter{
+					{
+						Property:                    "Status",
+						DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Status: &notion.StatusDatabaseQueryFilter{Equals: "Done"}},
+					},
+					{
+						Property: "Due",
+						DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Date: &notion.DatePropertyFilter{
+							Before: notion.TimePtr(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)),
+						}},
+					},
+				}},
+				Sorts: []notion.DatabaseQuerySort{{Property: "Due", Direction: notion.SortDirDesc}},
+			},
+		},
+		{
+			name: "or and precedence",
+			src:  `tags contains "bug" OR points >= 3 AND urgent = true`,
+			exp: notion.DatabaseQuery{
+				Filter: &notion.DatabaseQueryFilter{Or: []notion.DatabaseQueryFilter{
+					{
+						Property:                    "Tags",
+						DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{MultiSelect: &notion.MultiSelectDatabaseQueryFilter{Contains: "bug"}},
+					},
+					{And: []notion.DatabaseQueryFilter{
+						{
+							Property:                    "Points",
+							DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Number: &notion.NumberDatabaseQueryFilter{GreaterThanOrEqualTo: notion.IntPtr(3)}},
+						},
+						{
+							Property:                    "Urgent",
+							DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Checkbox: &notion.CheckboxDatabaseQueryFilter{Equals: notion.BoolPtr(true)}},
+						},
+					}},
+				}},
+			},
+		},
+		{
+			name: "parentheses",
+			src:  `(priority = "High" or priority is empty) and name starts with "Fix"`,
+			exp: notion.DatabaseQuery{
+				Filter: &notion.DatabaseQueryFilter{And: []notion.DatabaseQueryFilter{
+					{Or: []notion.DatabaseQueryFilter{
+						{
+							Property:                    "Priority",
+							DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Select: &notion.SelectDatabaseQueryFilter{Equals: "High"}},
+						},
+						{
+							Property:                    "Priority",
+							DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Select: &notion.SelectDatabaseQueryFilter{IsEmpty: true}},
+						},
+					}},
+					{
+						Property:                    "Name",
+						DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Title: &notion.TextPropertyFilter{StartsWith: "Fix"}},
+					},
+				}},
+			},
+		},
+		{
+			name: "quoted name, relative date and people",
+			src:  "`Due date` within next week and assignee not contains \"user-id\"",
+			exp: notion.DatabaseQuery{
+				Filter: &notion.DatabaseQueryFilter{And: []notion.DatabaseQueryFilter{
+					{
+						Property:                    "Due date",
+						DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Date: &notion.DatePropertyFilter{NextWeek: &struct{}{}}},
+					},
+					{
+						Property:                    "Assignee",
+						DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{People: &notion.PeopleDatabaseQueryFilter{DoesNotContain: "user-id"}},
+					},
+				}},
+			},
+		},
+		{
+			name: "formula and rollup by value type",
+			src:  `score > 50 and total <= 2021-05-18T09:30:00+02:00`,
+			exp: notion.DatabaseQuery{
+				Filter: &notion.DatabaseQueryFilter{And: []notion.DatabaseQueryFilter{
+					{
+						Property: "Score",
+						DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Formula: &notion.FormulaDatabaseQueryFilter{
+							Number: &notion.NumberDatabaseQueryFilter{GreaterThan: notion.IntPtr(50)},
+						}},
+					},
+					{
+						Property: "Total",
+						DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Rollup: &notion.RollupDatabaseQueryFilter{
+							Date: &notion.DatePropertyFilter{
+								OnOrBefore: notion.TimePtr(time.Date(2021, time.May, 18, 9, 30, 0, 0, time.FixedZone("", 2*60*60))),
+							},
+						}},
+					},
+				}},
+			},
+		},
+		{
+			name: "timestamps and multiple sorts",
+			src:  `created_time within past month order by points, last_edited_time desc`,
+			exp: notion.DatabaseQuery{
+				Filter: &notion.DatabaseQueryFilter{
+					Timestamp:                   notion.TimestampCreatedTime,
+					DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{CreatedTime: &notion.DatePropertyFilter{PastMonth: &struct{}{}}},
+				},
+				Sorts: []notion.DatabaseQuerySort{
+					{Property: "Points", Direction: notion.SortDirAsc},
+					{Timestamp: notion.SortTimeStampLastEditedTime, Direction: notion.SortDirDesc},
+				},
+			},
+		},
+		{
+			name:   "unknown property",
+			src:    `status = "Done" and estimate > 3`,
+			expErr: `query: column 21: unknown property "estimate"`,
+		},
+		{
+			name:   "operator not supported by property type",
+			src:    `priority contains "High"`,
+			expErr: `query: column 10: can't use "contains" with select property "Priority"`,
+		},
+		{
+			name:   "value of wrong type",
+			src:    `points = "3"`,
+			expErr: `query: column 10: expected number for number property "Points", found "\"3\""`,
+		},
+		{
+			name:   "non-integer number",
+			src:    `points < 2.5`,
+			expErr: `query: column 10: expected integer for number property "Points", found "2.5"`,
+		},
+		{
+			name: "integer beyond 32 bits",
+			src:  `points > 3000000000`,
+			exp: notion.DatabaseQuery{
+				Filter: &notion.DatabaseQueryFilter{
+					Property:                    "Points",
+					DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Number: &notion.NumberDatabaseQueryFilter{GreaterThan: notion.IntPtr(3000000000)}},
+				},
+			},
+		},
+		{
+			name:   "empty string",
+			src:    `status = "Done" or name = ""`,
+			expErr: `query: column 27: empty string for title property "Name", use "is empty" or "is not empty" instead`,
+		},
+		{
+			name:   "empty contains string",
+			src:    `tags not contains ""`,
+			expErr: `query: column 19: empty string for multi_select property "Tags", use "is empty" or "is not empty" instead`,
+		},
+		{
+			name:   "unterminated string",
+			src:    `name = "Fix`,
+			expErr: `query: column 8: unterminated string`,
+		},
+		{
+			name:   "missing closing parenthesis",
+			src:    `(urgent = true or points > 1`,
+			expErr: `query: column 29: expected ")" to close "(" at column 1, found end of query`,
+		},
+		{
+			name:   "trailing tokens",
+			src:    `urgent = true order by due sideways`,
+			expErr: `query: column 28: unexpected "sideways"`,
+		},
+		{
+			name:   "nesting too deep",
+			src:    `urgent = true or (points > 1 and (tags is empty or due is empty))`,
+			expErr: `query: column 15: compound filters can be nested at most 2 levels deep`,
+		},
+		{
+			name:   "formula result type unknown",
+			src:    `score is empty`,
+			expErr: `query: column 7: can't use "is empty" with formula property "Score", as its result type is unknown`,
+		},
+		{
+			name:   "columns count runes",
+			src:    `name = "é" and ünknown = 1`,
+			expErr: `query: column 16: unknown property "ünknown"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := query.Parse(tt.src, db)
+			if tt.expErr != "" {
+				if err == nil || err.Error() != tt.expErr {
+					t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.exp, q); diff != "" {
+				t.Fatalf("query not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
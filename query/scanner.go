@@ -0,0 +1,180 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokQuotedName
+	tokString
+	tokNumber
+	tokDate
+	tokOperator
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+
+	// Parsed values of strings, quoted names, numbers and dates.
+	str  string
+	num  float64
+	date time.Time
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of query"
+	}
+	return strconv.Quote(t.text)
+}
+
+// isKeyword reports whether t is the (case insensitive) keyword kw.
+func (t token) isKeyword(kw string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, kw)
+}
+
+// scan splits src into tokens. The last token is always tokEOF.
+func scan(src string) ([]token, error) {
+	var tokens []token
+
+	for pos := 0; ; {
+		r, size := utf8.DecodeRuneInString(src[pos:])
+		if size > 0 && unicode.IsSpace(r) {
+			pos += size
+			continue
+		}
+
+		start := pos
+		switch {
+		case pos == len(src):
+			return append(tokens, token{kind: tokEOF, pos: pos}), nil
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: pos})
+			pos++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: pos})
+			pos++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: pos})
+			pos++
+		case strings.ContainsRune("=!<>", r):
+			op := src[pos : pos+1]
+			if pos+1 < len(src) && src[pos+1] == '=' {
+				op = src[pos : pos+2]
+			}
+			if op == "!" {
+				return nil, newError(src, pos, "unexpected %q, expected \"!=\"", op)
+			}
+			tokens = append(tokens, token{kind: tokOperator, text: op, pos: pos})
+			pos += len(op)
+		case r == '"':
+			end, err := scanQuoted(src, pos, '"')
+			if err != nil {
+				return nil, err
+			}
+			s, err := strconv.Unquote(src[pos:end])
+			if err != nil {
+				return nil, newError(src, pos, "invalid string %v", src[pos:end])
+			}
+			tokens = append(tokens, token{kind: tokString, text: src[pos:end], pos: pos, str: s})
+			pos = end
+		case r == '`':
+			end, err := scanQuoted(src, pos, '`')
+			if err != nil {
+				return nil, err
+			}
+			name := src[pos+1 : end-1]
+			if name == "" {
+				return nil, newError(src, pos, "empty property name")
+			}
+			tokens = append(tokens, token{kind: tokQuotedName, text: src[pos:end], pos: pos, str: name})
+			pos = end
+		case isDigit(r) || (r == '-' && pos+1 < len(src) && isDigit(rune(src[pos+1]))):
+			pos++
+			for pos < len(src) && isLiteralByte(src[pos]) {
+				pos++
+			}
+			tok, err := scanLiteral(src, start, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+		case isWordRune(r):
+			for pos < len(src) {
+				r, size := utf8.DecodeRuneInString(src[pos:])
+				if !isWordRune(r) {
+					break
+				}
+				pos += size
+			}
+			tokens = append(tokens, token{kind: tokWord, text: src[start:pos], pos: start})
+		default:
+			return nil, newError(src, pos, "unexpected character %q", r)
+		}
+	}
+}
+
+// scanQuoted returns the end of the string or quoted name starting at pos.
+func scanQuoted(src string, pos int, quote byte) (int, error) {
+	for i := pos + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return i + 1, nil
+		}
+	}
+
+	if quote == '`' {
+		return 0, newError(src, pos, "unterminated property name")
+	}
+	return 0, newError(src, pos, "unterminated string")
+}
+
+// scanLiteral parses a number or date.
+func scanLiteral(src string, start, end int) (token, error) {
+	text := src[start:end]
+	tok := token{text: text, pos: start}
+
+	if n, err := strconv.ParseFloat(text, 64); err == nil {
+		tok.kind, tok.num = tokNumber, n
+		return tok, nil
+	}
+	if dt, err := notion.ParseDateTime(text); err == nil {
+		tok.kind, tok.date = tokDate, dt.Time
+		return tok, nil
+	}
+
+	return token{}, newError(src, start, "invalid number or date %q", text)
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isLiteralByte reports whether b can be part of a number or date, e.g.
+// `-1.5e3` or `2021-05-18T12:00:00+02:00`.
+func isLiteralByte(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || strings.IndexByte("-+.:", b) >= 0
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,342 @@
+package notion_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestQueryPages(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, time.May, 20, 12, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) *notion.Date {
+		return &notion.Date{Start: notion.NewDateTime(time.Date(year, month, day, 0, 0, 0, 0, time.UTC), false)}
+	}
+	checkboxes := func(values ...bool) *notion.RollupResult {
+		result := &notion.RollupResult{Type: notion.RollupResultTypeArray}
+		for _, v := range values {
+			result.Array = append(result.Array, notion.DatabasePageProperty{Type: notion.DBPropTypeCheckbox, Checkbox: notion.BoolPtr(v)})
+		}
+		return result
+	}
+
+	schema := notion.DatabaseProperties{
+		"Name":       {ID: "title", Type: notion.DBPropTypeTitle},
+		"Status":     {ID: "status", Type: notion.DBPropTypeStatus},
+		"Due":        {ID: "due", Type: notion.DBPropTypeDate},
+		"Points":     {ID: "points", Type: notion.DBPropTypeNumber},
+		"Tags":       {ID: "tags", Type: notion.DBPropTypeMultiSelect},
+		"Urgent":     {ID: "urgent", Type: notion.DBPropTypeCheckbox},
+		"Assignee":   {ID: "assignee", Type: notion.DBPropTypePeople},
+		"Blocked by": {ID: "blocked", Type: notion.DBPropTypeRelation},
+		"Score":      {ID: "score", Type: notion.DBPropTypeFormula},
+		"Subtasks":   {ID: "subtasks", Type: notion.DBPropTypeRollup},
+		"ID":         {ID: "id", Type: notion.DBPropTypeUniqueID},
+	}
+
+	pages := []notion.Page{
+		{
+			ID:          "write-docs",
+			CreatedTime: time.Date(2021, time.May, 1, 9, 0, 0, 0, time.UTC),
+			Properties: notion.DatabasePageProperties{
+				"Name":       {Type: notion.DBPropTypeTitle, Title: notion.TextRichText("Write docs")},
+				"Status":     {Type: notion.DBPropTypeStatus, Status: &notion.SelectOptions{Name: "Done"}},
+				"Due":        {Type: notion.DBPropTypeDate, Date: date(2021, time.May, 14)},
+				"Points":     {Type: notion.DBPropTypeNumber, Number: notion.Float64Ptr(3)},
+				"Tags":       {Type: notion.DBPropTypeMultiSelect, MultiSelect: []notion.SelectOptions{{Name: "docs"}}},
+				"Urgent":     {Type: notion.DBPropTypeCheckbox, Checkbox: notion.BoolPtr(false)},
+				"Assignee":   {Type: notion.DBPropTypePeople, People: []notion.User{{BaseUser: notion.BaseUser{ID: "alice"}}}},
+				"Blocked by": {Type: notion.DBPropTypeRelation},
+				"Score":      {Type: notion.DBPropTypeFormula, Formula: &notion.FormulaResult{Type: notion.FormulaResultTypeNumber, Number: notion.Float64Ptr(30)}},
+				"Subtasks":   {Type: notion.DBPropTypeRollup, Rollup: checkboxes(true, true)},
+				"ID":         {Type: notion.DBPropTypeUniqueID, UniqueID: &notion.UniqueID{Number: 1}},
+			},
+		},
+		{
+			ID:          "fix-bug",
+			CreatedTime: time.Date(2021, time.May, 2, 9, 0, 0, 0, time.UTC),
+			Properties: notion.DatabasePageProperties{
+				"Name":       {Type: notion.DBPropTypeTitle, Title: notion.TextRichText("Fix login bug")},
+				"Status":     {Type: notion.DBPropTypeStatus, Status: &notion.SelectOptions{Name: "In progress"}},
+				"Due":        {Type: notion.DBPropTypeDate, Date: date(2021, time.May, 20)},
+				"Points":     {Type: notion.DBPropTypeNumber, Number: notion.Float64Ptr(5)},
+				"Tags":       {Type: notion.DBPropTypeMultiSelect, MultiSelect: []notion.SelectOptions{{Name: "bug"}, {Name: "auth"}}},
+				"Urgent":     {Type: notion.DBPropTypeCheckbox, Checkbox: notion.BoolPtr(true)},
+				"Assignee":   {Type: notion.DBPropTypePeople, People: []notion.User{{BaseUser: notion.BaseUser{ID: "bob"}}}},
+				"Blocked by": {Type: notion.DBPropTypeRelation, Relation: []notion.Relation{{ID: "write-docs"}}},
+				"Score":      {Type: notion.DBPropTypeFormula, Formula: &notion.FormulaResult{Type: notion.FormulaResultTypeNumber, Number: notion.Float64Ptr(80)}},
+				"Subtasks":   {Type: notion.DBPropTypeRollup, Rollup: checkboxes(true, false)},
+				"ID":         {Type: notion.DBPropTypeUniqueID, UniqueID: &notion.UniqueID{Number: 2}},
+			},
+		},
+		{
+			ID:          "plan-release",
+			CreatedTime: time.Date(2021, time.May, 3, 9, 0, 0, 0, time.UTC),
+			Properties: notion.DatabasePageProperties{
+				"Name":     {Type: notion.DBPropTypeTitle, Title: notion.TextRichText("Plan release")},
+				"Status":   {Type: notion.DBPropTypeStatus},
+				"Due":      {Type: notion.DBPropTypeDate},
+				"Points":   {Type: notion.DBPropTypeNumber},
+				"Urgent":   {Type: notion.DBPropTypeCheckbox, Checkbox: notion.BoolPtr(false)},
+				"Score":    {Type: notion.DBPropTypeFormula},
+				"Subtasks": {Type: notion.DBPropTypeRollup, Rollup: checkboxes()},
+				"ID":       {Type: notion.DBPropTypeUniqueID, UniqueID: &notion.UniqueID{Number: 3}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		query  notion.DatabaseQuery
+		expIDs []string
+		expErr error
+	}{
+		{
+			name:   "no filter or sorts",
+			query:  notion.DatabaseQuery{},
+			expIDs: []string{"write-docs", "fix-bug", "plan-release"},
+		},
+		{
+			name: "text contains is case insensitive",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "Name",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Title: &notion.TextPropertyFilter{Contains: "BUG"}},
+			}},
+			expIDs: []string{"fix-bug"},
+		},
+		{
+			name: "text equals is case sensitive",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "Name",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Title: &notion.TextPropertyFilter{Equals: "write docs"}},
+			}},
+			expIDs: []string{},
+		},
+		{
+			name: "property by ID",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "status",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Status: &notion.StatusDatabaseQueryFilter{Equals: "Done"}},
+			}},
+			expIDs: []string{"write-docs"},
+		},
+		{
+			name: "empty status",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "Status",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Status: &notion.StatusDatabaseQueryFilter{IsEmpty: true}},
+			}},
+			expIDs: []string{"plan-release"},
+		},
+		{
+			name: "number does not equal matches empty values",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "Points",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Number: &notion.NumberDatabaseQueryFilter{DoesNotEqual: notion.IntPtr(5)}},
+			}},
+			expIDs: []string{"write-docs", "plan-release"},
+		},
+		{
+			name: "unique ID",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "ID",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{UniqueID: &notion.NumberDatabaseQueryFilter{GreaterThan: notion.IntPtr(1)}},
+			}},
+			expIDs: []string{"fix-bug", "plan-release"},
+		},
+		{
+			name: "and",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{And: []notion.DatabaseQueryFilter{
+				{
+					Property:                    "Points",
+					DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Number: &notion.NumberDatabaseQueryFilter{GreaterThanOrEqualTo: notion.IntPtr(3)}},
+				},
+				{
+					Property:                    "Urgent",
+					DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Checkbox: &notion.CheckboxDatabaseQueryFilter{Equals: notion.BoolPtr(false)}},
+				},
+			}}},
+			expIDs: []string{"write-docs"},
+		},
+		{
+			name: "or",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{Or: []notion.DatabaseQueryFilter{
+				{
+					Property:                    "Tags",
+					DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{MultiSelect: &notion.MultiSelectDatabaseQueryFilter{Contains: "auth"}},
+				},
+				{
+					Property:                    "Tags",
+					DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{MultiSelect: &notion.MultiSelectDatabaseQueryFilter{IsEmpty: true}},
+				},
+			}}},
+			expIDs: []string{"fix-bug", "plan-release"},
+		},
+		{
+			name: "people",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "Assignee",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{People: &notion.PeopleDatabaseQueryFilter{DoesNotContain: "alice"}},
+			}},
+			expIDs: []string{"fix-bug", "plan-release"},
+		},
+		{
+			name: "relation",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "Blocked by",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Relation: &notion.RelationDatabaseQueryFilter{Contains: "write-docs"}},
+			}},
+			expIDs: []string{"fix-bug"},
+		},
+		{
+			name: "date before",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property: "Due",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Date: &notion.DatePropertyFilter{
+					Before: notion.TimePtr(time.Date(2021, time.May, 20, 18, 0, 0, 0, time.UTC)),
+				}},
+			}},
+			expIDs: []string{"write-docs"},
+		},
+		{
+			name: "past week includes today",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "Due",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Date: &notion.DatePropertyFilter{PastWeek: &struct{}{}}},
+			}},
+			expIDs: []string{"write-docs", "fix-bug"},
+		},
+		{
+			name: "next week includes today",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "Due",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Date: &notion.DatePropertyFilter{NextWeek: &struct{}{}}},
+			}},
+			expIDs: []string{"fix-bug"},
+		},
+		{
+			name: "created time",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Timestamp: notion.TimestampCreatedTime,
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{CreatedTime: &notion.DatePropertyFilter{
+					OnOrAfter: notion.TimePtr(time.Date(2021, time.May, 2, 9, 0, 0, 0, time.UTC)),
+				}},
+			}},
+			expIDs: []string{"fix-bug", "plan-release"},
+		},
+		{
+			name: "formula",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property: "Score",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Formula: &notion.FormulaDatabaseQueryFilter{
+					Number: &notion.NumberDatabaseQueryFilter{LessThan: notion.IntPtr(50)},
+				}},
+			}},
+			expIDs: []string{"write-docs"},
+		},
+		{
+			name: "rollup every matches empty arrays",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property: "Subtasks",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Rollup: &notion.RollupDatabaseQueryFilter{
+					Every: &notion.DatabaseQueryPropertyFilter{Checkbox: &notion.CheckboxDatabaseQueryFilter{Equals: notion.BoolPtr(true)}},
+				}},
+			}},
+			expIDs: []string{"write-docs", "plan-release"},
+		},
+		{
+			name: "rollup any",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property: "Subtasks",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Rollup: &notion.RollupDatabaseQueryFilter{
+					Any: &notion.DatabaseQueryPropertyFilter{Checkbox: &notion.CheckboxDatabaseQueryFilter{Equals: notion.BoolPtr(false)}},
+				}},
+			}},
+			expIDs: []string{"fix-bug"},
+		},
+		{
+			name: "sort descending with empty values last",
+			query: notion.DatabaseQuery{Sorts: []notion.DatabaseQuerySort{
+				{Property: "Points", Direction: notion.SortDirDesc},
+			}},
+			expIDs: []string{"fix-bug", "write-docs", "plan-release"},
+		},
+		{
+			name: "sort by multiple properties",
+			query: notion.DatabaseQuery{Sorts: []notion.DatabaseQuerySort{
+				{Property: "Urgent", Direction: notion.SortDirAsc},
+				{Property: "title", Direction: notion.SortDirDesc},
+			}},
+			expIDs: []string{"write-docs", "plan-release", "fix-bug"},
+		},
+		{
+			name: "sort by timestamp",
+			query: notion.DatabaseQuery{Sorts: []notion.DatabaseQuerySort{
+				{Timestamp: notion.SortTimeStampCreatedTime, Direction: notion.SortDirDesc},
+			}},
+			expIDs: []string{"plan-release", "fix-bug", "write-docs"},
+		},
+		{
+			name: "unknown property",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "Priority",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Select: &notion.SelectDatabaseQueryFilter{Equals: "High"}},
+			}},
+			expErr: &notion.QueryError{Message: "Could not find property with name or id: Priority"},
+		},
+		{
+			name: "filter does not match property type",
+			query: notion.DatabaseQuery{Filter: &notion.DatabaseQueryFilter{
+				Property:                    "Status",
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{Select: &notion.SelectDatabaseQueryFilter{Equals: "Done"}},
+			}},
+			expErr: &notion.QueryError{Message: "database property status does not match filter select"},
+		},
+		{
+			name: "unknown sort property",
+			query: notion.DatabaseQuery{Sorts: []notion.DatabaseQuerySort{
+				{Property: "Priority"},
+			}},
+			expErr: &notion.QueryError{Message: "Could not find sort property with name or id: Priority"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := append([]notion.Page(nil), pages...)
+
+			result, err := notion.QueryPages(input, tt.query, schema, now)
+			if tt.expErr != nil {
+				var queryErr *notion.QueryError
+				if !errors.As(err, &queryErr) {
+					t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+				}
+				if diff := cmp.Diff(tt.expErr, queryErr); diff != "" {
+					t.Fatalf("error not equal (-exp, +got):\n%v", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ids := make([]string, len(result))
+			for i, page := range result {
+				ids[i] = page.ID
+			}
+			if diff := cmp.Diff(tt.expIDs, ids); diff != "" {
+				t.Fatalf("page IDs not equal (-exp, +got):\n%v", diff)
+			}
+			if diff := cmp.Diff(pages, input); diff != "" {
+				t.Fatalf("input pages were modified (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
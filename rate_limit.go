@@ -0,0 +1,103 @@
+package notion
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter that paces requests to the Notion
+// API. It is safe for concurrent use, and a single RateLimiter can be shared by
+// several clients that use the same integration token.
+// See: https://developers.notion.com/reference/request-limits
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter that allows `rps` requests per second on
+// average, with bursts of at most `burst` requests. If rps is zero or negative,
+// requests aren't limited.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// WithRateLimit paces all requests made by the client to `rps` requests per
+// second on average, with bursts of at most `burst` requests. If rps is zero or
+// negative, requests aren't limited.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return WithRateLimiter(NewRateLimiter(rps, burst))
+}
+
+// WithRateLimiter paces all requests made by the client using limiter. Use it
+// to share one limiter across clients that use the same integration token.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// Wait blocks until a request is allowed to be made, or until ctx is done, in
+// which case the context's error is returned.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.refill(now)
+	l.tokens--
+	tokens := l.tokens
+	l.mu.Unlock()
+
+	if tokens >= 0 {
+		return nil
+	}
+
+	delay := time.Duration(-tokens / l.rate * float64(time.Second))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		// Return the reserved token, so it can be used by other callers.
+		l.mu.Lock()
+		l.refill(time.Now())
+		l.tokens++
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// refill adds tokens for the time that elapsed since the last refill. The
+// caller must hold l.mu.
+func (l *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last)
+	if elapsed <= 0 {
+		return
+	}
+
+	l.last = now
+	l.tokens += elapsed.Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
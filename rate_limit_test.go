@@ -0,0 +1,138 @@
+package notion_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	t.Parallel()
+
+	t.Run("allows burst without waiting", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := notion.NewRateLimiter(1, 3)
+		start := time.Now()
+
+		for i := 0; i < 3; i++ {
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Fatalf("expected burst to not wait, waited %v", elapsed)
+		}
+	})
+
+	t.Run("paces requests after burst", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := notion.NewRateLimiter(50, 1)
+		start := time.Now()
+
+		for i := 0; i < 3; i++ {
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+			t.Fatalf("expected requests to be paced, waited %v", elapsed)
+		}
+	})
+
+	t.Run("zero rate is unlimited", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := notion.NewRateLimiter(0, 1)
+		start := time.Now()
+
+		for i := 0; i < 10; i++ {
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Fatalf("expected requests to not wait, waited %v", elapsed)
+		}
+	})
+
+	t.Run("respects context cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := notion.NewRateLimiter(0.1, 1)
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := limiter.Wait(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("error not equal (expected: %v, got: %v)", context.DeadlineExceeded, err)
+		}
+	})
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+
+	httpClient := &http.Client{
+		Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&requests, 1)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"object": "user",
+					"id": "be32e790-8292-46df-a248-b784fdf483cf",
+					"type": "bot",
+					"name": "Test"
+				}`)),
+			}, nil
+		}},
+	}
+
+	// Two clients sharing one limiter must share its budget.
+	limiter := notion.NewRateLimiter(50, 2)
+	clients := []*notion.Client{
+		notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient), notion.WithRateLimiter(limiter)),
+		notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient), notion.WithRateLimiter(limiter)),
+	}
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(client *notion.Client) {
+			defer wg.Done()
+			if _, err := client.FindCurrentUser(context.Background()); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(clients[i%2])
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&requests); got != 6 {
+		t.Fatalf("requests not equal (expected: 6, got: %v)", got)
+	}
+
+	// Four requests exceed the burst, at 20ms each.
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Fatalf("expected requests to be paced, waited %v", elapsed)
+	}
+}
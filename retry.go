@@ -0,0 +1,198 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how the client retries requests that failed with a
+// transient error, e.g. `rate_limited` or `service_unavailable`.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts for a request, including the
+	// first one. A value of 1 or less disables retries.
+	MaxAttempts int
+
+	// MinBackoff is the base delay used for the first retry. The delay doubles
+	// for every subsequent retry, with full jitter applied.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts. It is not applied to delays
+	// requested by the API via the `Retry-After` response header.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with sensible defaults for the
+// Notion API.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+// WithRetryPolicy enables automatic retries of requests that failed with a
+// `rate_limited`, `internal_server_error`, `service_unavailable` or
+// `conflict_error` response.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+type retryPolicyCtxKey struct{}
+
+// ContextWithRetryPolicy returns a copy of ctx that overrides the retry policy
+// of the client for calls made with it. Use a policy with MaxAttempts set to 1
+// to disable retries for a single call.
+func ContextWithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyCtxKey{}, policy)
+}
+
+// retryableErrCodes are error codes returned by the Notion API for which a
+// request can safely be retried.
+var retryableErrCodes = map[string]bool{
+	"rate_limited":          true,
+	"internal_server_error": true,
+	"service_unavailable":   true,
+	"conflict_error":        true,
+}
+
+func (c *Client) retryPolicyFor(ctx context.Context) RetryPolicy {
+	if policy, ok := ctx.Value(retryPolicyCtxKey{}).(RetryPolicy); ok {
+		return policy
+	}
+	if c.retryPolicy != nil {
+		return *c.retryPolicy
+	}
+
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// send sends an HTTP request, retrying it according to the client's retry
+// policy. The request body is replayed for every attempt, and every attempt is
+// paced by the client's rate limiter, if any.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	policy := c.retryPolicyFor(ctx)
+	reauthorized := false
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		// Retry once with a refreshed token, without counting it as an attempt.
+		if res.StatusCode == http.StatusUnauthorized && !reauthorized {
+			if token, ok := c.refreshToken(req); ok {
+				if req, err = reauthorize(req, res, token); err != nil {
+					return nil, err
+				}
+				reauthorized = true
+				attempt--
+				continue
+			}
+		}
+
+		if attempt >= policy.MaxAttempts || !isRetryableResponse(res) {
+			return res, nil
+		}
+
+		delay, ok := retryAfter(res)
+		if !ok {
+			delay = policy.backoff(attempt)
+		}
+
+		// Drain the body so the underlying connection can be reused.
+		_, _ = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetryableResponse reports whether res contains a retryable API error. The
+// response body is buffered, so it can still be read by the caller.
+func isRetryableResponse(res *http.Response) bool {
+	if res.StatusCode < http.StatusBadRequest {
+		return false
+	}
+
+	b, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return false
+	}
+
+	return newAPIError(res, b).IsRetryable()
+}
+
+// retryAfter parses the `Retry-After` header of res, which is either a number
+// of seconds or an HTTP date.
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	value := res.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		delay := time.Until(t)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// backoff returns a jittered, exponentially increasing delay for the given
+// (1-based) attempt that just failed.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.MinBackoff <= 0 {
+		return 0
+	}
+
+	delay := p.MinBackoff
+	for i := 1; i < attempt && delay < math.MaxInt64/2; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
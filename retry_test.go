@@ -0,0 +1,254 @@
+package notion_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	rateLimitedBody := `{
+		"object": "error",
+		"status": 429,
+		"code": "rate_limited",
+		"message": "You have been rate limited."
+	}`
+	validationBody := `{
+		"object": "error",
+		"status": 400,
+		"code": "validation_error",
+		"message": "foobar"
+	}`
+	pageBody := `{
+		"object": "page",
+		"id": "606ed832-7d79-46de-bbed-5b4896e5ca1f",
+		"created_time": "2021-05-19T19:34:05.068Z",
+		"last_edited_time": "2021-05-19T19:34:05.069Z",
+		"parent": {
+			"type": "page_id",
+			"page_id": "b0668f48-8d66-4733-9bdb-2f82215707f7"
+		},
+		"archived": false,
+		"url": "https://www.notion.so/Foobar-606ed8327d7946debbed5b4896e5ca1f",
+		"properties": {
+			"title": {
+				"id": "title",
+				"type": "title",
+				"title": []
+			}
+		}
+	}`
+
+	policy := notion.RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name        string
+		policy      *notion.RetryPolicy
+		ctxPolicy   *notion.RetryPolicy
+		responses   []func() *http.Response
+		expAttempts int
+		expError    error
+	}{
+		{
+			name:   "retries rate limited response with Retry-After header",
+			policy: &policy,
+			responses: []func() *http.Response{
+				func() *http.Response {
+					return &http.Response{
+						StatusCode: http.StatusTooManyRequests,
+						Header:     http.Header{"Retry-After": []string{"0"}},
+						Body:       ioutil.NopCloser(strings.NewReader(rateLimitedBody)),
+					}
+				},
+				func() *http.Response {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(strings.NewReader(pageBody)),
+					}
+				},
+			},
+			expAttempts: 2,
+		},
+		{
+			name:   "retries proxy error without JSON body",
+			policy: &policy,
+			responses: []func() *http.Response{
+				func() *http.Response {
+					return &http.Response{
+						StatusCode: http.StatusBadGateway,
+						Body:       ioutil.NopCloser(strings.NewReader("<html>Bad Gateway</html>")),
+					}
+				},
+				func() *http.Response {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(strings.NewReader(pageBody)),
+					}
+				},
+			},
+			expAttempts: 2,
+		},
+		{
+			name:   "gives up after max attempts",
+			policy: &policy,
+			responses: []func() *http.Response{
+				func() *http.Response {
+					return &http.Response{
+						StatusCode: http.StatusTooManyRequests,
+						Body:       ioutil.NopCloser(strings.NewReader(rateLimitedBody)),
+					}
+				},
+			},
+			expAttempts: 3,
+			expError:    notion.ErrRateLimited,
+		},
+		{
+			name:   "does not retry non-retryable error",
+			policy: &policy,
+			responses: []func() *http.Response{
+				func() *http.Response {
+					return &http.Response{
+						StatusCode: http.StatusBadRequest,
+						Body:       ioutil.NopCloser(strings.NewReader(validationBody)),
+					}
+				},
+			},
+			expAttempts: 1,
+			expError:    notion.ErrValidation,
+		},
+		{
+			name: "does not retry without policy",
+			responses: []func() *http.Response{
+				func() *http.Response {
+					return &http.Response{
+						StatusCode: http.StatusTooManyRequests,
+						Body:       ioutil.NopCloser(strings.NewReader(rateLimitedBody)),
+					}
+				},
+			},
+			expAttempts: 1,
+			expError:    notion.ErrRateLimited,
+		},
+		{
+			name:      "context policy overrides client policy",
+			policy:    &policy,
+			ctxPolicy: &notion.RetryPolicy{MaxAttempts: 1},
+			responses: []func() *http.Response{
+				func() *http.Response {
+					return &http.Response{
+						StatusCode: http.StatusTooManyRequests,
+						Body:       ioutil.NopCloser(strings.NewReader(rateLimitedBody)),
+					}
+				},
+			},
+			expAttempts: 1,
+			expError:    notion.ErrRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int
+			var bodies []string
+
+			httpClient := &http.Client{
+				Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+					b, err := io.ReadAll(r.Body)
+					if err != nil {
+						t.Fatal(err)
+					}
+					bodies = append(bodies, string(b))
+
+					i := attempts
+					if i >= len(tt.responses) {
+						i = len(tt.responses) - 1
+					}
+					attempts++
+
+					return tt.responses[i](), nil
+				}},
+			}
+
+			opts := []notion.ClientOption{notion.WithHTTPClient(httpClient)}
+			if tt.policy != nil {
+				opts = append(opts, notion.WithRetryPolicy(*tt.policy))
+			}
+			client := notion.NewClient("secret-api-key", opts...)
+
+			ctx := context.Background()
+			if tt.ctxPolicy != nil {
+				ctx = notion.ContextWithRetryPolicy(ctx, *tt.ctxPolicy)
+			}
+
+			_, err := client.CreatePage(ctx, notion.CreatePageParams{
+				ParentType: notion.ParentTypePage,
+				ParentID:   "b0668f48-8d66-4733-9bdb-2f82215707f7",
+				Title:      []notion.RichText{{Text: &notion.Text{Content: "Foobar"}}},
+			})
+
+			if tt.expError == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expError != nil && !errors.Is(err, tt.expError) {
+				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
+			}
+
+			if tt.expAttempts != attempts {
+				t.Fatalf("attempts not equal (expected: %v, got: %v)", tt.expAttempts, attempts)
+			}
+
+			for i, body := range bodies {
+				if body == "" || body != bodies[0] {
+					t.Fatalf("request body of attempt %v not replayed (got: %q)", i+1, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicyContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	httpClient := &http.Client{
+		Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Retry-After": []string{"60"}},
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"object": "error",
+					"status": 503,
+					"code": "service_unavailable",
+					"message": "Notion is unavailable."
+				}`)),
+			}, nil
+		}},
+	}
+	client := notion.NewClient("secret-api-key",
+		notion.WithHTTPClient(httpClient),
+		notion.WithRetryPolicy(notion.DefaultRetryPolicy()),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.FindPageByID(ctx, "606ed832-7d79-46de-bbed-5b4896e5ca1f")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error not equal (expected: %v, got: %v)", context.DeadlineExceeded, err)
+	}
+}
@@ -1,5 +1,7 @@
 package notion
 
+import "strings"
+
 type RichText struct {
 	Type        RichTextType `json:"type,omitempty"`
 	Annotations *Annotations `json:"annotations,omitempty"`
@@ -116,3 +118,49 @@ const (
 	ColorPinkBg   Color = "pink_background"
 	ColorRedBg    Color = "red_background"
 )
+
+// PlainText returns the plain text of rich text. The `plain_text` of rich text
+// objects is used if it's set, as in API responses. Otherwise, the content of
+// text objects and the expression of equations are used.
+func PlainText(richText []RichText) string {
+	var sb strings.Builder
+	for _, rt := range richText {
+		switch {
+		case rt.PlainText != "":
+			sb.WriteString(rt.PlainText)
+		case rt.Text != nil:
+			sb.WriteString(rt.Text.Content)
+		case rt.Equation != nil:
+			sb.WriteString(rt.Equation.Expression)
+		}
+	}
+	return sb.String()
+}
+
+// maxTextLength is the maximum length of text content of a rich text object.
+const maxTextLength = 2000
+
+// TextRichText returns s as unformatted rich text, split over multiple rich
+// text objects to stay within the maximum text length of 2000 characters.
+func TextRichText(s string) []RichText {
+	richText := []RichText{}
+
+	start, length := 0, 0
+	for i, r := range s {
+		// Length is measured in UTF-16 code units, like the Notion API does.
+		n := 1
+		if r >= 0x10000 {
+			n = 2
+		}
+		if length+n > maxTextLength {
+			richText = append(richText, RichText{Text: &Text{Content: s[start:i]}})
+			start, length = i, 0
+		}
+		length += n
+	}
+	if start < len(s) {
+		richText = append(richText, RichText{Text: &Text{Content: s[start:]}})
+	}
+
+	return richText
+}
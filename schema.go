@@ -0,0 +1,512 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Schema is the desired schema of a database, used with DiffSchema and
+// Client.ApplySchema.
+type Schema struct {
+	// Properties are the desired properties, keyed by name. Each property must
+	// have a type, and metadata where the API requires it, e.g. the database
+	// of a relation. Exactly one property must be of type title. A property
+	// with an ID is matched to the current property with that ID.
+	Properties DatabaseProperties
+
+	// Renames maps names of desired properties to the names of the current
+	// properties they replace. Renames of which only the new name exists are
+	// considered applied. Properties that aren't renamed are matched by ID or
+	// name, and the title property is always matched to the current title
+	// property.
+	Renames map[string]string
+}
+
+// SchemaChangeType is the type of a schema change.
+type SchemaChangeType string
+
+const (
+	SchemaChangeAdd           SchemaChangeType = "add"
+	SchemaChangeRemove        SchemaChangeType = "remove"
+	SchemaChangeRename        SchemaChangeType = "rename"
+	SchemaChangeRetype        SchemaChangeType = "retype"
+	SchemaChangeUpdateOptions SchemaChangeType = "update_options"
+)
+
+// SchemaChange is a change of a database property. A property can have more
+// than one change, e.g. when it's renamed and retyped.
+type SchemaChange struct {
+	Type SchemaChangeType
+
+	// Property is the desired name of the property, or the current name for
+	// removals.
+	Property string
+
+	// PropertyID is the ID of the current property. It's empty for additions.
+	PropertyID string
+
+	// OldName is the current name of a renamed property.
+	OldName string
+
+	// OldType and NewType are the current and desired type of a retyped
+	// property. For additions, NewType is the type of the new property.
+	OldType DatabasePropertyType
+	NewType DatabasePropertyType
+
+	// AddedOptions and RemovedOptions are the names of select or multi-select
+	// options that are added or removed.
+	AddedOptions   []string
+	RemovedOptions []string
+}
+
+// Destructive reports whether the change can cause data loss: removing a
+// property, changing its type, or removing select options.
+func (c SchemaChange) Destructive() bool {
+	switch c.Type {
+	case SchemaChangeRemove, SchemaChangeRetype:
+		return true
+	case SchemaChangeUpdateOptions:
+		return len(c.RemovedOptions) > 0
+	}
+	return false
+}
+
+// String returns a human readable description of the change.
+func (c SchemaChange) String() string {
+	switch c.Type {
+	case SchemaChangeAdd:
+		return fmt.Sprintf("add %q (%v)", c.Property, c.NewType)
+	case SchemaChangeRemove:
+		return fmt.Sprintf("remove %q", c.Property)
+	case SchemaChangeRename:
+		return fmt.Sprintf("rename %q to %q", c.OldName, c.Property)
+	case SchemaChangeRetype:
+		return fmt.Sprintf("retype %q from %v to %v", c.Property, c.OldType, c.NewType)
+	case SchemaChangeUpdateOptions:
+		var parts []string
+		if len(c.AddedOptions) > 0 {
+			parts = append(parts, "add "+quoteJoin(c.AddedOptions))
+		}
+		if len(c.RemovedOptions) > 0 {
+			parts = append(parts, "remove "+quoteJoin(c.RemovedOptions))
+		}
+		return fmt.Sprintf("update options of %q: %v", c.Property, strings.Join(parts, ", "))
+	}
+	return fmt.Sprintf("%v %q", c.Type, c.Property)
+}
+
+// SchemaDiff is the difference between a current database schema and a
+// desired Schema.
+type SchemaDiff struct {
+	// Changes are sorted by property name, with removals last.
+	Changes []SchemaChange
+
+	// updates and additions are the properties of the update params. They're
+	// kept apart, because an added property can reuse the name of a property
+	// that's renamed or removed.
+	updates   map[string]*DatabaseProperty
+	additions map[string]*DatabaseProperty
+	conflicts bool
+}
+
+// Destructive returns the changes that can cause data loss.
+func (d SchemaDiff) Destructive() []SchemaChange {
+	var changes []SchemaChange
+	for _, change := range d.Changes {
+		if change.Destructive() {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// Params returns the params for the UpdateDatabase calls that apply the diff.
+// Usually a single call suffices, but if an added property reuses the name of
+// a current property that's renamed or removed, the addition is done in a
+// second call. It returns nil if there are no changes.
+func (d SchemaDiff) Params() []UpdateDatabaseParams {
+	if len(d.Changes) == 0 {
+		return nil
+	}
+
+	if !d.conflicts {
+		props := make(map[string]*DatabaseProperty, len(d.updates)+len(d.additions))
+		for key, prop := range d.updates {
+			props[key] = prop
+		}
+		for key, prop := range d.additions {
+			props[key] = prop
+		}
+		return []UpdateDatabaseParams{{Properties: props}}
+	}
+
+	var params []UpdateDatabaseParams
+	for _, props := range []map[string]*DatabaseProperty{d.updates, d.additions} {
+		if len(props) > 0 {
+			params = append(params, UpdateDatabaseParams{Properties: props})
+		}
+	}
+	return params
+}
+
+// DestructiveSchemaChangeError is returned by Client.ApplySchema when the
+// desired schema requires destructive changes that aren't allowed.
+type DestructiveSchemaChangeError struct {
+	DatabaseID string
+	Changes    []SchemaChange
+}
+
+// Error implements `error`.
+func (err *DestructiveSchemaChangeError) Error() string {
+	changes := make([]string, len(err.Changes))
+	for i, change := range err.Changes {
+		changes[i] = change.String()
+	}
+	return fmt.Sprintf("notion: refusing destructive schema changes of database %v: %v",
+		err.DatabaseID, strings.Join(changes, "; "))
+}
+
+// DiffSchema compares the schema of a database with a desired schema. It
+// returns an error if the desired schema is invalid, or can't be applied, e.g.
+// because it changes the type of the title property.
+//
+// Select and multi-select options are compared by name. Options that are kept
+// retain their ID and color, and the options of status properties aren't
+// compared, as they can't be changed with the API.
+func DiffSchema(current Database, desired Schema) (SchemaDiff, error) {
+	if err := desired.validate(current.Properties); err != nil {
+		return SchemaDiff{}, fmt.Errorf("notion: invalid schema: %w", err)
+	}
+
+	matches := matchSchemaProperties(current.Properties, desired)
+
+	diff := SchemaDiff{
+		updates:   make(map[string]*DatabaseProperty),
+		additions: make(map[string]*DatabaseProperty),
+	}
+
+	for _, name := range sortedPropertyNames(desired.Properties) {
+		want := desired.Properties[name]
+
+		curName, ok := matches[name]
+		if !ok {
+			prop := want
+			prop.ID = ""
+			prop.Name = ""
+			diff.additions[name] = &prop
+			diff.Changes = append(diff.Changes, SchemaChange{
+				Type:     SchemaChangeAdd,
+				Property: name,
+				NewType:  want.Type,
+			})
+			if _, exists := current.Properties[name]; exists {
+				diff.conflicts = true
+			}
+			continue
+		}
+
+		cur := current.Properties[curName]
+		if (cur.Type == DBPropTypeTitle) != (want.Type == DBPropTypeTitle) {
+			return SchemaDiff{}, fmt.Errorf("notion: can't change type of property %q from %v to %v", name, cur.Type, want.Type)
+		}
+
+		// The type is only sent when it changes, as the API doesn't require
+		// it for renames and option updates.
+		var (
+			changes []SchemaChange
+			update  = &DatabaseProperty{}
+		)
+
+		if curName != name {
+			update.Name = name
+			changes = append(changes, SchemaChange{
+				Type:       SchemaChangeRename,
+				Property:   name,
+				PropertyID: cur.ID,
+				OldName:    curName,
+			})
+		}
+
+		if cur.Type != want.Type {
+			*update = want
+			update.ID = ""
+			if curName != name {
+				update.Name = name
+			} else {
+				update.Name = ""
+			}
+			changes = append(changes, SchemaChange{
+				Type:       SchemaChangeRetype,
+				Property:   name,
+				PropertyID: cur.ID,
+				OldType:    cur.Type,
+				NewType:    want.Type,
+			})
+		} else if change, meta, ok := diffSelectOptions(cur, want); ok {
+			change.Property = name
+			change.PropertyID = cur.ID
+			switch want.Type {
+			case DBPropTypeSelect:
+				update.Select = meta
+			case DBPropTypeMultiSelect:
+				update.MultiSelect = meta
+			}
+			changes = append(changes, change)
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		diff.updates[propertyKey(curName, cur)] = update
+		diff.Changes = append(diff.Changes, changes...)
+	}
+
+	matched := make(map[string]bool, len(matches))
+	for _, curName := range matches {
+		matched[curName] = true
+	}
+	for _, name := range sortedPropertyNames(current.Properties) {
+		if matched[name] {
+			continue
+		}
+		cur := current.Properties[name]
+		diff.updates[propertyKey(name, cur)] = nil
+		diff.Changes = append(diff.Changes, SchemaChange{
+			Type:       SchemaChangeRemove,
+			Property:   name,
+			PropertyID: cur.ID,
+			OldType:    cur.Type,
+		})
+	}
+
+	return diff, nil
+}
+
+// ApplySchemaOptions configures Client.ApplySchema.
+type ApplySchemaOptions struct {
+	// AllowDestructive allows changes that can cause data loss. See
+	// SchemaChange.Destructive.
+	AllowDestructive bool
+}
+
+// ApplySchema updates the properties of a database to match a desired schema,
+// with as few UpdateDatabase calls as possible (none, if the schema is up to
+// date). Unless destructive changes are allowed with opts, a
+// DestructiveSchemaChangeError is returned if any change can cause data loss,
+// and no changes are made. The applied diff is returned. Options may be nil.
+func (c *Client) ApplySchema(ctx context.Context, databaseID string, desired Schema, opts *ApplySchemaOptions) (SchemaDiff, error) {
+	if opts == nil {
+		opts = &ApplySchemaOptions{}
+	}
+
+	db, err := c.FindDatabaseByID(ctx, databaseID)
+	if err != nil {
+		return SchemaDiff{}, err
+	}
+
+	diff, err := DiffSchema(db, desired)
+	if err != nil {
+		return SchemaDiff{}, err
+	}
+
+	if destructive := diff.Destructive(); len(destructive) > 0 && !opts.AllowDestructive {
+		return SchemaDiff{}, &DestructiveSchemaChangeError{
+			DatabaseID: databaseID,
+			Changes:    destructive,
+		}
+	}
+
+	for _, params := range diff.Params() {
+		if _, err := c.UpdateDatabase(ctx, databaseID, params); err != nil {
+			return SchemaDiff{}, err
+		}
+	}
+
+	return diff, nil
+}
+
+func (s Schema) validate(current DatabaseProperties) error {
+	var titles int
+	for name, prop := range s.Properties {
+		if name == "" {
+			return errors.New("property name is required")
+		}
+		if prop.Type == "" {
+			return fmt.Errorf("type of property %q is required", name)
+		}
+		if prop.Type == DBPropTypeTitle {
+			titles++
+		}
+	}
+	if titles != 1 {
+		return fmt.Errorf("schema must have exactly one title property, got %v", titles)
+	}
+
+	renamed := make(map[string]string, len(s.Renames))
+	for name, oldName := range s.Renames {
+		if _, ok := s.Properties[name]; !ok {
+			return fmt.Errorf("renamed property %q is not in schema", name)
+		}
+		if _, ok := current[oldName]; !ok {
+			if _, ok := current[name]; ok {
+				// Already renamed.
+				continue
+			}
+			return fmt.Errorf("property %q renamed to %q does not exist", oldName, name)
+		}
+		if other, ok := renamed[oldName]; ok {
+			return fmt.Errorf("property %q is renamed to both %q and %q", oldName, other, name)
+		}
+		renamed[oldName] = name
+	}
+
+	return nil
+}
+
+// matchSchemaProperties maps names of desired properties to the names of
+// current properties they correspond to. Desired properties without a match
+// are new. Matches are made by explicit rename, ID, title type and name, in
+// that order.
+func matchSchemaProperties(current DatabaseProperties, desired Schema) map[string]string {
+	matches := make(map[string]string)
+	matched := make(map[string]bool)
+
+	match := func(name, curName string) {
+		matches[name] = curName
+		matched[curName] = true
+	}
+
+	names := sortedPropertyNames(desired.Properties)
+	curNames := sortedPropertyNames(current)
+
+	for _, name := range names {
+		if oldName, ok := desired.Renames[name]; ok {
+			if _, ok := current[oldName]; ok {
+				match(name, oldName)
+			}
+		}
+	}
+
+	for _, name := range names {
+		id := desired.Properties[name].ID
+		if _, ok := matches[name]; ok || id == "" {
+			continue
+		}
+		for _, curName := range curNames {
+			if !matched[curName] && current[curName].ID == id {
+				match(name, curName)
+				break
+			}
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := matches[name]; ok || desired.Properties[name].Type != DBPropTypeTitle {
+			continue
+		}
+		for _, curName := range curNames {
+			if !matched[curName] && current[curName].Type == DBPropTypeTitle {
+				match(name, curName)
+				break
+			}
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := matches[name]; ok {
+			continue
+		}
+		if _, ok := current[name]; ok && !matched[name] {
+			match(name, name)
+		}
+	}
+
+	return matches
+}
+
+// diffSelectOptions compares the options of select and multi-select
+// properties. It reports false if they're equal. The returned metadata has
+// the desired options, with the ID and color of current options that are
+// kept.
+func diffSelectOptions(cur, want DatabaseProperty) (SchemaChange, *SelectMetadata, bool) {
+	var curMeta, wantMeta *SelectMetadata
+	switch want.Type {
+	case DBPropTypeSelect:
+		curMeta, wantMeta = cur.Select, want.Select
+	case DBPropTypeMultiSelect:
+		curMeta, wantMeta = cur.MultiSelect, want.MultiSelect
+	default:
+		return SchemaChange{}, nil, false
+	}
+
+	if wantMeta == nil {
+		// Options aren't managed by the schema.
+		return SchemaChange{}, nil, false
+	}
+
+	curOptions := make(map[string]SelectOptions)
+	if curMeta != nil {
+		for _, opt := range curMeta.Options {
+			curOptions[opt.Name] = opt
+		}
+	}
+
+	change := SchemaChange{Type: SchemaChangeUpdateOptions}
+	meta := &SelectMetadata{Options: make([]SelectOptions, 0, len(wantMeta.Options))}
+	kept := make(map[string]bool, len(wantMeta.Options))
+
+	for _, opt := range wantMeta.Options {
+		if curOpt, ok := curOptions[opt.Name]; ok {
+			meta.Options = append(meta.Options, curOpt)
+			kept[opt.Name] = true
+			continue
+		}
+		opt.ID = ""
+		meta.Options = append(meta.Options, opt)
+		change.AddedOptions = append(change.AddedOptions, opt.Name)
+	}
+
+	if curMeta != nil {
+		for _, opt := range curMeta.Options {
+			if !kept[opt.Name] {
+				change.RemovedOptions = append(change.RemovedOptions, opt.Name)
+			}
+		}
+	}
+
+	if len(change.AddedOptions) == 0 && len(change.RemovedOptions) == 0 {
+		return SchemaChange{}, nil, false
+	}
+
+	return change, meta, true
+}
+
+// propertyKey returns the key used to refer to a current property in update
+// params. IDs are preferred, as names can be reused by other changes.
+func propertyKey(name string, prop DatabaseProperty) string {
+	if prop.ID != "" {
+		return prop.ID
+	}
+	return name
+}
+
+func sortedPropertyNames(props DatabaseProperties) []string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func quoteJoin(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,346 @@
+package notion_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/notiontest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffSchema(t *testing.T) {
+	t.Parallel()
+
+	current := notion.Database{
+		ID: "db-id",
+		Properties: notion.DatabaseProperties{
+			"Name":     {ID: "title", Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+			"Notes":    {ID: "notes", Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}},
+			"Estimate": {ID: "estimate", Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}},
+			"Priority": {
+				ID:   "priority",
+				Type: notion.DBPropTypeSelect,
+				Select: &notion.SelectMetadata{Options: []notion.SelectOptions{
+					{ID: "opt-1", Name: "Low", Color: notion.ColorGray},
+					{ID: "opt-2", Name: "High", Color: notion.ColorRed},
+				}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		desired        notion.Schema
+		expChanges     []notion.SchemaChange
+		expDestructive bool
+		expParams      []notion.UpdateDatabaseParams
+		expErr         error
+	}{
+		{
+			name: "no changes",
+			desired: notion.Schema{Properties: notion.DatabaseProperties{
+				"Name":     {Type: notion.DBPropTypeTitle},
+				"Notes":    {Type: notion.DBPropTypeRichText},
+				"Estimate": {Type: notion.DBPropTypeRichText},
+				"Priority": {Type: notion.DBPropTypeSelect},
+			}},
+			expChanges: nil,
+			expParams:  nil,
+		},
+		{
+			name: "add, rename and retype",
+			desired: notion.Schema{
+				Properties: notion.DatabaseProperties{
+					"Title":       {Type: notion.DBPropTypeTitle},
+					"Description": {Type: notion.DBPropTypeRichText},
+					"Estimate":    {Type: notion.DBPropTypeNumber, Number: &notion.NumberMetadata{Format: notion.NumberFormatNumber}},
+					"Priority":    {Type: notion.DBPropTypeSelect},
+					"Due":         {Type: notion.DBPropTypeDate, Date: &notion.EmptyMetadata{}},
+				},
+				Renames: map[string]string{"Description": "Notes"},
+			},
+			expChanges: []notion.SchemaChange{
+				{Type: notion.SchemaChangeRename, Property: "Description", PropertyID: "notes", OldName: "Notes"},
+				{Type: notion.SchemaChangeAdd, Property: "Due", NewType: notion.DBPropTypeDate},
+				{
+					Type:       notion.SchemaChangeRetype,
+					Property:   "Estimate",
+					PropertyID: "estimate",
+					OldType:    notion.DBPropTypeRichText,
+					NewType:    notion.DBPropTypeNumber,
+				},
+				{Type: notion.SchemaChangeRename, Property: "Title", PropertyID: "title", OldName: "Name"},
+			},
+			expDestructive: true,
+			expParams: []notion.UpdateDatabaseParams{{Properties: map[string]*notion.DatabaseProperty{
+				"notes": {Name: "Description"},
+				"Due":   {Type: notion.DBPropTypeDate, Date: &notion.EmptyMetadata{}},
+				"estimate": {
+					Type:   notion.DBPropTypeNumber,
+					Number: &notion.NumberMetadata{Format: notion.NumberFormatNumber},
+				},
+				"title": {Name: "Title"},
+			}}},
+		},
+		{
+			name: "update options",
+			desired: notion.Schema{Properties: notion.DatabaseProperties{
+				"Name":     {Type: notion.DBPropTypeTitle},
+				"Notes":    {Type: notion.DBPropTypeRichText},
+				"Estimate": {Type: notion.DBPropTypeRichText},
+				"Priority": {
+					Type: notion.DBPropTypeSelect,
+					Select: &notion.SelectMetadata{Options: []notion.SelectOptions{
+						{Name: "High"},
+						{Name: "Urgent", Color: notion.ColorPurple},
+					}},
+				},
+			}},
+			expChanges: []notion.SchemaChange{
+				{
+					Type:           notion.SchemaChangeUpdateOptions,
+					Property:       "Priority",
+					PropertyID:     "priority",
+					AddedOptions:   []string{"Urgent"},
+					RemovedOptions: []string{"Low"},
+				},
+			},
+			expDestructive: true,
+			expParams: []notion.UpdateDatabaseParams{{Properties: map[string]*notion.DatabaseProperty{
+				"priority": {
+					Select: &notion.SelectMetadata{Options: []notion.SelectOptions{
+						{ID: "opt-2", Name: "High", Color: notion.ColorRed},
+						{Name: "Urgent", Color: notion.ColorPurple},
+					}},
+				},
+			}}},
+		},
+		{
+			name: "remove and reuse name",
+			desired: notion.Schema{
+				Properties: notion.DatabaseProperties{
+					"Name":     {Type: notion.DBPropTypeTitle},
+					"Summary":  {Type: notion.DBPropTypeRichText},
+					"Notes":    {Type: notion.DBPropTypeURL, URL: &notion.EmptyMetadata{}},
+					"Priority": {Type: notion.DBPropTypeSelect},
+				},
+				Renames: map[string]string{"Summary": "Notes"},
+			},
+			expChanges: []notion.SchemaChange{
+				{Type: notion.SchemaChangeAdd, Property: "Notes", NewType: notion.DBPropTypeURL},
+				{Type: notion.SchemaChangeRename, Property: "Summary", PropertyID: "notes", OldName: "Notes"},
+				{Type: notion.SchemaChangeRemove, Property: "Estimate", PropertyID: "estimate", OldType: notion.DBPropTypeRichText},
+			},
+			expDestructive: true,
+			expParams: []notion.UpdateDatabaseParams{
+				{Properties: map[string]*notion.DatabaseProperty{
+					"notes":    {Name: "Summary"},
+					"estimate": nil,
+				}},
+				{Properties: map[string]*notion.DatabaseProperty{
+					"Notes": {Type: notion.DBPropTypeURL, URL: &notion.EmptyMetadata{}},
+				}},
+			},
+		},
+		{
+			name: "missing title",
+			desired: notion.Schema{Properties: notion.DatabaseProperties{
+				"Notes": {Type: notion.DBPropTypeRichText},
+			}},
+			expErr: errors.New("notion: invalid schema: schema must have exactly one title property, got 0"),
+		},
+		{
+			name: "rename of unknown property",
+			desired: notion.Schema{
+				Properties: notion.DatabaseProperties{
+					"Name":    {Type: notion.DBPropTypeTitle},
+					"Summary": {Type: notion.DBPropTypeRichText},
+				},
+				Renames: map[string]string{"Summary": "Description"},
+			},
+			expErr: errors.New(`notion: invalid schema: property "Description" renamed to "Summary" does not exist`),
+		},
+		{
+			name: "retype title",
+			desired: notion.Schema{
+				Properties: notion.DatabaseProperties{
+					"Name":  {Type: notion.DBPropTypeRichText},
+					"Notes": {Type: notion.DBPropTypeTitle},
+				},
+				Renames: map[string]string{"Notes": "Notes"},
+			},
+			expErr: errors.New(`notion: can't change type of property "Name" from title to rich_text`),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			diff, err := notion.DiffSchema(current, tt.desired)
+
+			if tt.expErr != nil {
+				if err == nil || err.Error() != tt.expErr.Error() {
+					t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.expChanges, diff.Changes); diff != "" {
+				t.Fatalf("changes not equal (-exp, +got):\n%v", diff)
+			}
+			if destructive := len(diff.Destructive()) > 0; destructive != tt.expDestructive {
+				t.Fatalf("destructive not equal (expected: %v, got: %v)", tt.expDestructive, destructive)
+			}
+			if diff := cmp.Diff(tt.expParams, diff.Params()); diff != "" {
+				t.Fatalf("params not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestDiffSchemaRenamePayload(t *testing.T) {
+	t.Parallel()
+
+	current := notion.Database{
+		ID: "db-id",
+		Properties: notion.DatabaseProperties{
+			"Name": {ID: "title", Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+			"Blockers": {
+				ID:       "blockers",
+				Type:     notion.DBPropTypeRelation,
+				Relation: &notion.RelationMetadata{DatabaseID: "db-id"},
+			},
+		},
+	}
+	desired := notion.Schema{
+		Properties: notion.DatabaseProperties{
+			"Name":       {Type: notion.DBPropTypeTitle},
+			"Blocked by": {Type: notion.DBPropTypeRelation, Relation: &notion.RelationMetadata{DatabaseID: "db-id"}},
+		},
+		Renames: map[string]string{"Blocked by": "Blockers"},
+	}
+
+	diff, err := notion.DiffSchema(current, desired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Renames only send the new name, without the type and its metadata.
+	got, err := json.Marshal(diff.Params())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exp := `[{"properties":{"blockers":{"name":"Blocked by"}}}]`
+	if string(got) != exp {
+		t.Fatalf("payload not equal (expected: %v, got: %s)", exp, got)
+	}
+}
+
+func TestApplySchema(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+
+	var operations []string
+	client := srv.Client(notion.WithMiddleware(func(next notion.Doer) notion.Doer {
+		return notion.DoerFunc(func(req *notion.Request) (*notion.Response, error) {
+			operations = append(operations, req.Operation)
+			return next.Do(req)
+		})
+	}))
+
+	root := srv.AddPage("Root")
+	db, err := client.CreateDatabase(ctx, notion.CreateDatabaseParams{
+		ParentPageID: root.ID,
+		Title:        []notion.RichText{{Text: &notion.Text{Content: "Tasks"}}},
+		Properties: notion.DatabaseProperties{
+			"Name":     {Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+			"Notes":    {Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}},
+			"Obsolete": {Type: notion.DBPropTypeCheckbox, Checkbox: &notion.EmptyMetadata{}},
+			"Tags": {Type: notion.DBPropTypeMultiSelect, MultiSelect: &notion.SelectMetadata{
+				Options: []notion.SelectOptions{{Name: "bug"}},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	desired := notion.Schema{
+		Properties: notion.DatabaseProperties{
+			"Name":        {Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+			"Description": {Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}},
+			"Tags": {Type: notion.DBPropTypeMultiSelect, MultiSelect: &notion.SelectMetadata{
+				Options: []notion.SelectOptions{{Name: "bug"}, {Name: "feature"}},
+			}},
+		},
+		Renames: map[string]string{"Description": "Notes"},
+	}
+
+	operations = nil
+	_, err = client.ApplySchema(ctx, db.ID, desired, nil)
+	var destructiveErr *notion.DestructiveSchemaChangeError
+	if !errors.As(err, &destructiveErr) {
+		t.Fatalf("expected DestructiveSchemaChangeError, got: %v", err)
+	}
+	if exp := `notion: refusing destructive schema changes of database ` + db.ID + `: remove "Obsolete"`; err.Error() != exp {
+		t.Fatalf("error not equal (expected: %v, got: %v)", exp, err)
+	}
+	if diff := cmp.Diff([]string{"FindDatabaseByID"}, operations); diff != "" {
+		t.Fatalf("operations not equal (-exp, +got):\n%v", diff)
+	}
+
+	operations = nil
+	diff, err := client.ApplySchema(ctx, db.ID, desired, &notion.ApplySchemaOptions{AllowDestructive: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(diff.Changes) != 3 {
+		t.Fatalf("expected 3 changes, got: %v", diff.Changes)
+	}
+	if diff := cmp.Diff([]string{"FindDatabaseByID", "UpdateDatabase"}, operations); diff != "" {
+		t.Fatalf("operations not equal (-exp, +got):\n%v", diff)
+	}
+
+	updated, err := client.FindDatabaseByID(ctx, db.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for name := range updated.Properties {
+		names = append(names, name)
+	}
+	var tags []string
+	for _, opt := range updated.Properties["Tags"].MultiSelect.Options {
+		tags = append(tags, opt.Name)
+	}
+	if diff := cmp.Diff([]string{"bug", "feature"}, tags); diff != "" {
+		t.Fatalf("tags not equal (-exp, +got):\n%v", diff)
+	}
+	if _, ok := updated.Properties["Description"]; !ok || len(names) != 3 {
+		t.Fatalf("unexpected properties: %v", names)
+	}
+
+	// Applying the schema again is a no-op.
+	operations = nil
+	diff, err = client.ApplySchema(ctx, db.ID, desired, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(diff.Changes) != 0 {
+		t.Fatalf("expected no changes, got: %v", diff.Changes)
+	}
+	if diff := cmp.Diff([]string{"FindDatabaseByID"}, operations); diff != "" {
+		t.Fatalf("operations not equal (-exp, +got):\n%v", diff)
+	}
+}
@@ -26,6 +26,11 @@ func ParseDateTime(value string) (DateTime, error) {
 	}
 
 	t, err := time.Parse(DateTimeFormat[:len(value)], value)
+	if err != nil && len(value) > dateLength {
+		// Times without fractional seconds, e.g. as encoded by MarshalJSON,
+		// don't match a prefix of DateTimeFormat.
+		t, err = time.Parse(time.RFC3339, value)
+	}
 	if err != nil {
 		return DateTime{}, err
 	}
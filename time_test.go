@@ -72,6 +72,13 @@ func TestTimeUnmarshalJSON(t *testing.T) {
 			expHasTime:  true,
 			expError:    nil,
 		},
+		{
+			name:        "date and time without fractional seconds",
+			timeString:  "2021-05-23T11:11:50+02:00",
+			expDateTime: notion.NewDateTime(mustParseTime(time.RFC3339Nano, "2021-05-23T11:11:50+02:00"), true),
+			expHasTime:  true,
+			expError:    nil,
+		},
 		{
 			name:        "date without time",
 			timeString:  "2021-05-23",
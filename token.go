@@ -0,0 +1,140 @@
+package notion
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource provides the integration token used to authenticate requests.
+// It's consulted for every request, so tokens can be rotated without creating
+// a new client. It must be safe for concurrent use.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc is an adapter to allow the use of ordinary functions as a
+// TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f(ctx).
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource returns a TokenSource that always returns token. It's used
+// for the API key passed to NewClient.
+func StaticTokenSource(token string) TokenSource {
+	return staticTokenSource(token)
+}
+
+type staticTokenSource string
+
+func (s staticTokenSource) Token(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// CachingTokenSource wraps a TokenSource, e.g. one that reads a token from a
+// secrets manager, and caches its token for a fixed duration.
+type CachingTokenSource struct {
+	src TokenSource
+	ttl time.Duration
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewCachingTokenSource returns a CachingTokenSource that caches tokens of src
+// for ttl. A ttl of zero or less caches tokens until Invalidate is called.
+func NewCachingTokenSource(src TokenSource, ttl time.Duration) *CachingTokenSource {
+	return &CachingTokenSource{src: src, ttl: ttl}
+}
+
+// Token implements TokenSource.
+func (s *CachingTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.ttl <= 0 || time.Now().Before(s.expires)) {
+		return s.token, nil
+	}
+
+	token, err := s.src.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token
+	s.expires = time.Now().Add(s.ttl)
+
+	return token, nil
+}
+
+// Invalidate clears the cached token, so the next call to Token gets a fresh
+// token from the wrapped source. The client calls it when a request fails
+// with ErrUnauthorized.
+func (s *CachingTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = ""
+}
+
+// WithTokenSource authenticates requests with tokens of src, instead of the
+// API key passed to NewClient. When a request fails with ErrUnauthorized, the
+// token source is invalidated (if it has an `Invalidate()` method) and asked
+// for a token again. If it returns a different token, the request is retried
+// once with it.
+func WithTokenSource(src TokenSource) ClientOption {
+	return func(c *Client) {
+		c.tokenSource = src
+	}
+}
+
+// refreshToken returns a new token after a request authenticated with the
+// bearer token of req failed with ErrUnauthorized. It reports false if the
+// token source has no other token available.
+func (c *Client) refreshToken(req *http.Request) (string, bool) {
+	if inv, ok := c.tokenSource.(interface{ Invalidate() }); ok {
+		inv.Invalidate()
+	}
+
+	token, err := c.tokenSource.Token(req.Context())
+	if err != nil || token == "" {
+		return "", false
+	}
+
+	if token == bearerToken(req) {
+		return "", false
+	}
+
+	return token, true
+}
+
+// reauthorize returns a clone of req that's authenticated with token, with
+// its body replayed. The response of the previous attempt is discarded.
+func reauthorize(req *http.Request, res *http.Response, token string) (*http.Request, error) {
+	_, _ = io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	clone.Header.Set("Authorization", "Bearer "+token)
+
+	return clone, nil
+}
+
+// bearerToken returns the token of the `Authorization` header of req.
+func bearerToken(req *http.Request) string {
+	return strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+}
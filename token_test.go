@@ -0,0 +1,124 @@
+package notion_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cryptowizard0/go-notion"
+)
+
+// secretStore mimics a secrets manager that rotates integration tokens.
+type secretStore struct {
+	mu    sync.Mutex
+	token string
+	reads int
+}
+
+func (s *secretStore) Token(context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reads++
+	return s.token, nil
+}
+
+func (s *secretStore) rotate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+func newTokenServer(t *testing.T, validToken *atomic.Value, requests *int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if r.Header.Get("Authorization") != "Bearer "+validToken.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"object":"error","status":401,"code":"unauthorized","message":"API token is invalid."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"object":"user","id":"bot-id","type":"bot","bot":{}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestTokenSource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("refresh once after rotation", func(t *testing.T) {
+		t.Parallel()
+
+		var validToken atomic.Value
+		validToken.Store("token-1")
+		var requests int32
+		srv := newTokenServer(t, &validToken, &requests)
+
+		store := &secretStore{token: "token-1"}
+		client := notion.NewClient("",
+			notion.WithBaseURL(srv.URL),
+			notion.WithTokenSource(notion.NewCachingTokenSource(store, 0)),
+		)
+
+		for i := 0; i < 2; i++ {
+			if _, err := client.FindCurrentUser(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if store.reads != 1 {
+			t.Fatalf("expected cached token to be used (reads: %v)", store.reads)
+		}
+
+		store.rotate("token-2")
+		validToken.Store("token-2")
+
+		if _, err := client.FindCurrentUser(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := atomic.LoadInt32(&requests); got != 4 {
+			t.Fatalf("requests not equal (expected: 4, got: %v)", got)
+		}
+		if store.reads != 2 {
+			t.Fatalf("expected token to be refreshed once (reads: %v)", store.reads)
+		}
+	})
+
+	t.Run("surface error if token didn't change", func(t *testing.T) {
+		t.Parallel()
+
+		var validToken atomic.Value
+		validToken.Store("token-2")
+		var requests int32
+		srv := newTokenServer(t, &validToken, &requests)
+
+		client := notion.NewClient("token-1", notion.WithBaseURL(srv.URL))
+
+		_, err := client.FindCurrentUser(context.Background())
+		if !errors.Is(err, notion.ErrUnauthorized) {
+			t.Fatalf("expected error to wrap notion.ErrUnauthorized, got: %v", err)
+		}
+		if got := atomic.LoadInt32(&requests); got != 1 {
+			t.Fatalf("requests not equal (expected: 1, got: %v)", got)
+		}
+	})
+
+	t.Run("token source error", func(t *testing.T) {
+		t.Parallel()
+
+		errSecrets := errors.New("secrets manager unavailable")
+		client := notion.NewClient("", notion.WithTokenSource(notion.TokenSourceFunc(
+			func(context.Context) (string, error) {
+				return "", errSecrets
+			},
+		)))
+
+		_, err := client.FindCurrentUser(context.Background())
+		if !errors.Is(err, errSecrets) {
+			t.Fatalf("expected error to wrap token source error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,541 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cryptowizard0/go-notion/internal/pageprop"
+)
+
+// UpsertAction is the action taken by Client.UpsertDatabasePage.
+type UpsertAction string
+
+const (
+	UpsertActionCreated   UpsertAction = "created"
+	UpsertActionUpdated   UpsertAction = "updated"
+	UpsertActionUnchanged UpsertAction = "unchanged"
+)
+
+// UpsertResult is the result of Client.UpsertDatabasePage.
+type UpsertResult struct {
+	Page   Page
+	Action UpsertAction
+}
+
+// DuplicateKeyError is returned by Client.UpsertDatabasePage when more than one
+// page in the database has the key value.
+type DuplicateKeyError struct {
+	DatabaseID string
+	Property   string
+	Value      interface{}
+	PageIDs    []string
+}
+
+// Error implements `error`.
+func (err *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("notion: multiple pages in database %v have %q value %v (pages: %v)",
+		err.DatabaseID, err.Property, err.Value, strings.Join(err.PageIDs, ", "))
+}
+
+// UpsertDatabasePage creates or updates the page in a database that's
+// identified by the value of a unique key property. The key property must be
+// a title, rich text, number, unique ID, URL, email or phone number property.
+// Its value is a string, or an integer for number properties. If props doesn't
+// contain the key property, it's added when a page is created.
+//
+// Unique IDs are assigned by Notion, so pages with a unique ID key can only be
+// updated; ErrObjectNotFound is returned if no page has the key value. The
+// value is the number of the unique ID, or a string with an optional prefix
+// (e.g. "TASK-12"), which isn't matched.
+//
+// An existing page is only updated if any of props differ from its current
+// values, and only the properties that differ are sent. Read-only properties
+// in props are ignored when comparing. A DuplicateKeyError is returned if more
+// than one page has the key value.
+func (c *Client) UpsertDatabasePage(
+	ctx context.Context,
+	databaseID, keyProp string,
+	keyValue interface{},
+	props DatabasePageProperties,
+) (UpsertResult, error) {
+	keyType, err := c.upsertKeyType(ctx, databaseID, keyProp, props)
+	if err != nil {
+		return UpsertResult{}, err
+	}
+
+	keyFilter, keyProperty, err := upsertKey(keyProp, keyType, keyValue)
+	if err != nil {
+		return UpsertResult{}, err
+	}
+
+	res, err := c.QueryDatabase(ctx, databaseID, &DatabaseQuery{
+		Filter:   &keyFilter,
+		PageSize: 2,
+	})
+	if err != nil {
+		return UpsertResult{}, err
+	}
+
+	if len(res.Results) > 1 {
+		pageIDs := make([]string, len(res.Results))
+		for i, page := range res.Results {
+			pageIDs[i] = page.ID
+		}
+
+		return UpsertResult{}, &DuplicateKeyError{
+			DatabaseID: databaseID,
+			Property:   keyProp,
+			Value:      keyValue,
+			PageIDs:    pageIDs,
+		}
+	}
+
+	if len(res.Results) == 0 {
+		if keyType == DBPropTypeUniqueID {
+			return UpsertResult{}, fmt.Errorf("notion: no page in database %v has %q value %v, and unique IDs can't be set: %w",
+				databaseID, keyProp, keyValue, ErrObjectNotFound)
+		}
+
+		createProps := DatabasePageProperties{keyProp: keyProperty}
+		for name, prop := range props {
+			createProps[name] = prop
+		}
+
+		page, err := c.CreatePage(ctx, CreatePageParams{
+			ParentType:             ParentTypeDatabase,
+			ParentID:               databaseID,
+			DatabasePageProperties: &createProps,
+		})
+		if err != nil {
+			return UpsertResult{}, err
+		}
+
+		return UpsertResult{Page: page, Action: UpsertActionCreated}, nil
+	}
+
+	page := res.Results[0]
+	current, _ := page.Properties.(DatabasePageProperties)
+
+	changed := DatabasePageProperties{}
+	for name, prop := range props {
+		if !propertyValuesEqual(current[name], prop) {
+			changed[name] = prop
+		}
+	}
+
+	if len(changed) == 0 {
+		return UpsertResult{Page: page, Action: UpsertActionUnchanged}, nil
+	}
+
+	page, err = c.UpdatePage(ctx, page.ID, UpdatePageParams{DatabasePageProperties: changed})
+	if err != nil {
+		return UpsertResult{}, err
+	}
+
+	return UpsertResult{Page: page, Action: UpsertActionUpdated}, nil
+}
+
+// upsertKeyType returns the type of the key property. It's taken from props if
+// possible, to save a request for the database schema.
+func (c *Client) upsertKeyType(ctx context.Context, databaseID, keyProp string, props DatabasePageProperties) (DatabasePropertyType, error) {
+	if prop, ok := props[keyProp]; ok {
+		if propType := inferPagePropertyType(prop); propType != "" {
+			return propType, nil
+		}
+	}
+
+	db, err := c.FindDatabaseByID(ctx, databaseID)
+	if err != nil {
+		return "", err
+	}
+
+	prop, ok := db.Properties[keyProp]
+	if !ok {
+		return "", fmt.Errorf("notion: database %v has no property %q", databaseID, keyProp)
+	}
+
+	return prop.Type, nil
+}
+
+// upsertKey returns the filter that matches pages by key value, and the key
+// property of new pages. For unique IDs, which can't be set, the returned key
+// property is empty.
+func upsertKey(keyProp string, keyType DatabasePropertyType, keyValue interface{}) (DatabaseQueryFilter, DatabasePageProperty, error) {
+	filter := DatabaseQueryFilter{Property: keyProp}
+
+	if keyType == DBPropTypeUniqueID {
+		n, ok := uniqueIDNumber(keyValue)
+		if !ok {
+			return DatabaseQueryFilter{}, DatabasePageProperty{},
+				fmt.Errorf("notion: key value of unique ID property %q must be an integer or a string like \"TASK-12\", got %v", keyProp, keyValue)
+		}
+		filter.UniqueID = &NumberDatabaseQueryFilter{Equals: &n}
+
+		return filter, DatabasePageProperty{}, nil
+	}
+
+	if !pageprop.IsKeyType(string(keyType)) {
+		return DatabaseQueryFilter{}, DatabasePageProperty{},
+			fmt.Errorf("notion: property %q of type %v can't be used as key", keyProp, keyType)
+	}
+
+	var key string
+
+	if keyType == DBPropTypeNumber {
+		n, ok := intValue(keyValue)
+		if !ok {
+			return DatabaseQueryFilter{}, DatabasePageProperty{},
+				fmt.Errorf("notion: key value of number property %q must be an integer, got %T", keyProp, keyValue)
+		}
+		filter.Number = &NumberDatabaseQueryFilter{Equals: &n}
+		key = strconv.Itoa(n)
+	} else {
+		s, ok := keyValue.(string)
+		if !ok {
+			return DatabaseQueryFilter{}, DatabasePageProperty{},
+				fmt.Errorf("notion: key value of %v property %q must be a string, got %T", keyType, keyProp, keyValue)
+		}
+		if s == "" {
+			return DatabaseQueryFilter{}, DatabasePageProperty{}, fmt.Errorf("notion: key value of property %q is empty", keyProp)
+		}
+		textFilter := &TextPropertyFilter{Equals: s}
+
+		switch keyType {
+		case DBPropTypeTitle:
+			filter.Title = textFilter
+		case DBPropTypeRichText:
+			filter.RichText = textFilter
+		case DBPropTypeURL:
+			filter.URL = textFilter
+		case DBPropTypeEmail:
+			filter.Email = textFilter
+		case DBPropTypePhoneNumber:
+			filter.PhoneNumber = textFilter
+		}
+		key = s
+	}
+
+	prop, err := keyProperty(keyType, key)
+	if err != nil {
+		return DatabaseQueryFilter{}, DatabasePageProperty{}, err
+	}
+
+	return filter, prop, nil
+}
+
+// keyProperty returns the value of a key property for new pages, given the key
+// as string. Number keys are formatted without exponent, e.g. "42" or "4.5".
+func keyProperty(propType DatabasePropertyType, key string) (DatabasePageProperty, error) {
+	prop := DatabasePageProperty{Type: propType}
+
+	switch propType {
+	case DBPropTypeTitle:
+		prop.Title = TextRichText(key)
+	case DBPropTypeRichText:
+		prop.RichText = TextRichText(key)
+	case DBPropTypeNumber:
+		n, err := strconv.ParseFloat(key, 64)
+		if err != nil {
+			return DatabasePageProperty{}, fmt.Errorf("notion: invalid number key %q", key)
+		}
+		prop.Number = &n
+	case DBPropTypeURL:
+		prop.URL = &key
+	case DBPropTypeEmail:
+		prop.Email = &key
+	case DBPropTypePhoneNumber:
+		prop.PhoneNumber = &key
+	default:
+		return DatabasePageProperty{}, fmt.Errorf("notion: property type %v can't be used as key", propType)
+	}
+
+	return prop, nil
+}
+
+func intValue(v interface{}) (int, bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := rv.Int()
+		if n < math.MinInt || n > math.MaxInt {
+			return 0, false
+		}
+		return int(n), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt {
+			return 0, false
+		}
+		return int(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != math.Trunc(f) || f < math.MinInt || f >= math.MaxInt {
+			return 0, false
+		}
+		return int(f), true
+	}
+
+	return 0, false
+}
+
+// uniqueIDNumber returns the number of a unique ID key value: an integer, or a
+// string with an optional prefix, e.g. "TASK-12".
+func uniqueIDNumber(v interface{}) (int, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return intValue(v)
+	}
+
+	if i := strings.LastIndex(s, "-"); i >= 0 {
+		s = s[i+1:]
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// inferPagePropertyType returns the type of a page property, inferred from its
+// value if its type isn't set.
+func inferPagePropertyType(prop DatabasePageProperty) DatabasePropertyType {
+	if prop.Type != "" {
+		return prop.Type
+	}
+
+	switch {
+	case prop.Title != nil:
+		return DBPropTypeTitle
+	case prop.RichText != nil:
+		return DBPropTypeRichText
+	case prop.Number != nil:
+		return DBPropTypeNumber
+	case prop.Select != nil:
+		return DBPropTypeSelect
+	case prop.MultiSelect != nil:
+		return DBPropTypeMultiSelect
+	case prop.Status != nil:
+		return DBPropTypeStatus
+	case prop.Date != nil:
+		return DBPropTypeDate
+	case prop.Relation != nil:
+		return DBPropTypeRelation
+	case prop.People != nil:
+		return DBPropTypePeople
+	case prop.Files != nil:
+		return DBPropTypeFiles
+	case prop.Checkbox != nil:
+		return DBPropTypeCheckbox
+	case prop.URL != nil:
+		return DBPropTypeURL
+	case prop.Email != nil:
+		return DBPropTypeEmail
+	case prop.PhoneNumber != nil:
+		return DBPropTypePhoneNumber
+	case prop.UniqueID != nil:
+		return DBPropTypeUniqueID
+	}
+
+	return ""
+}
+
+func init() {
+	pageprop.ValuesEqual = func(current, desired interface{}) bool {
+		return propertyValuesEqual(current.(DatabasePageProperty), desired.(DatabasePageProperty))
+	}
+	pageprop.KeyProperty = func(propType, key string) (interface{}, error) {
+		return keyProperty(DatabasePropertyType(propType), key)
+	}
+}
+
+// propertyValuesEqual reports whether the current value of a page property, as
+// returned by the API, equals a desired value, as used in CreatePageParams and
+// UpdatePageParams. Fields that are set by the API, e.g. the plain text of rich
+// text and the color of select options, are ignored unless they're set in
+// desired. Read-only properties are always considered equal.
+func propertyValuesEqual(current, desired DatabasePageProperty) bool {
+	propType := inferPagePropertyType(desired)
+	if isReadOnlyPropType(propType) {
+		return true
+	}
+	if propType == "" {
+		// An empty property value clears the property.
+		return propIsEmpty(current)
+	}
+
+	current.Type = inferPagePropertyType(current)
+	desired.Type = propType
+	if current.Type != desired.Type {
+		return false
+	}
+
+	switch propType {
+	case DBPropTypeTitle:
+		return richTextEqual(current.Title, desired.Title)
+	case DBPropTypeRichText:
+		return richTextEqual(current.RichText, desired.RichText)
+	case DBPropTypeNumber:
+		return (current.Number == nil) == (desired.Number == nil) &&
+			(current.Number == nil || *current.Number == *desired.Number)
+	case DBPropTypeSelect:
+		return selectOptionEqual(current.Select, desired.Select)
+	case DBPropTypeStatus:
+		return selectOptionEqual(current.Status, desired.Status)
+	case DBPropTypeMultiSelect:
+		return selectOptionSetsEqual(current.MultiSelect, desired.MultiSelect)
+	case DBPropTypeDate:
+		return dateEqual(current.Date, desired.Date)
+	case DBPropTypeRelation, DBPropTypePeople:
+		// Like multi-select options, relations and people are unordered.
+		currentValues, _ := propStrings(current)
+		desiredValues, _ := propStrings(desired)
+		return stringSetsEqual(currentValues, desiredValues)
+	case DBPropTypeFiles:
+		currentValues, _ := propStrings(current)
+		desiredValues, _ := propStrings(desired)
+		return reflect.DeepEqual(emptyIfNil(currentValues), emptyIfNil(desiredValues))
+	case DBPropTypeCheckbox:
+		return (current.Checkbox != nil && *current.Checkbox) == (desired.Checkbox != nil && *desired.Checkbox)
+	case DBPropTypeURL, DBPropTypeEmail, DBPropTypePhoneNumber:
+		currentValue, _ := propString(current)
+		desiredValue, _ := propString(desired)
+		return currentValue == desiredValue
+	}
+
+	return reflect.DeepEqual(current.Value(), desired.Value())
+}
+
+// richTextSegment is the comparable form of a rich text object.
+type richTextSegment struct {
+	kind        RichTextType
+	content     string
+	href        string
+	annotations Annotations
+}
+
+func richTextSegments(richText []RichText) []richTextSegment {
+	segments := make([]richTextSegment, 0, len(richText))
+
+	for _, rt := range richText {
+		var segment richTextSegment
+
+		switch {
+		case rt.Text != nil:
+			segment.kind = RichTextTypeText
+			segment.content = rt.Text.Content
+			if rt.Text.Link != nil {
+				segment.href = rt.Text.Link.URL
+			}
+		case rt.Equation != nil:
+			segment.kind = RichTextTypeEquation
+			segment.content = rt.Equation.Expression
+		case rt.Mention != nil:
+			segment.kind = RichTextTypeMention
+			b, _ := json.Marshal(rt.Mention)
+			segment.content = string(b)
+		default:
+			segment.kind = RichTextTypeText
+			segment.content = rt.PlainText
+		}
+		if rt.Annotations != nil {
+			segment.annotations = *rt.Annotations
+		}
+		if segment.annotations.Color == "" {
+			segment.annotations.Color = ColorDefault
+		}
+
+		// Adjacent segments with equal formatting are equivalent to one, e.g.
+		// when long text is split into multiple rich text objects.
+		if n := len(segments); n > 0 && segment.kind == RichTextTypeText && segments[n-1].kind == RichTextTypeText &&
+			segments[n-1].href == segment.href && segments[n-1].annotations == segment.annotations {
+			segments[n-1].content += segment.content
+			continue
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments
+}
+
+func richTextEqual(current, desired []RichText) bool {
+	return reflect.DeepEqual(richTextSegments(current), richTextSegments(desired))
+}
+
+func selectOptionEqual(current, desired *SelectOptions) bool {
+	if current == nil || desired == nil {
+		return current == nil && desired == nil
+	}
+	if desired.Name != "" {
+		return current.Name == desired.Name
+	}
+
+	return current.ID == desired.ID
+}
+
+// selectOptionSetsEqual reports whether two multi-select values have the same
+// options, in any order.
+func selectOptionSetsEqual(current, desired []SelectOptions) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+
+	matched := make([]bool, len(current))
+outer:
+	for i := range desired {
+		for j := range current {
+			if !matched[j] && selectOptionEqual(&current[j], &desired[i]) {
+				matched[j] = true
+				continue outer
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// stringSetsEqual reports whether a and b have the same values, in any order.
+func stringSetsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+
+	return reflect.DeepEqual(a, b)
+}
+
+func dateEqual(current, desired *Date) bool {
+	if current == nil || desired == nil {
+		return current == nil && desired == nil
+	}
+	if !current.Start.Equal(desired.Start) {
+		return false
+	}
+	if (current.End == nil) != (desired.End == nil) || (current.End != nil && !current.End.Equal(*desired.End)) {
+		return false
+	}
+	if desired.TimeZone != nil && (current.TimeZone == nil || *current.TimeZone != *desired.TimeZone) {
+		return false
+	}
+
+	return true
+}
+
+func emptyIfNil(strs []string) []string {
+	if strs == nil {
+		return []string{}
+	}
+	return strs
+}
@@ -0,0 +1,421 @@
+package notion_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cryptowizard0/go-notion"
+	"github.com/cryptowizard0/go-notion/notiontest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpsertDatabasePage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+
+	var operations []string
+	client := srv.Client(notion.WithMiddleware(func(next notion.Doer) notion.Doer {
+		return notion.DoerFunc(func(req *notion.Request) (*notion.Response, error) {
+			operations = append(operations, req.Operation)
+			return next.Do(req)
+		})
+	}))
+
+	root := srv.AddPage("Root")
+	db, err := client.CreateDatabase(ctx, notion.CreateDatabaseParams{
+		ParentPageID: root.ID,
+		Title:        []notion.RichText{{Text: &notion.Text{Content: "Tickets"}}},
+		Properties: notion.DatabaseProperties{
+			"Name":        {Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+			"External ID": {Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}},
+			"Number":      {Type: notion.DBPropTypeNumber, Number: &notion.NumberMetadata{}},
+			"Estimate":    {Type: notion.DBPropTypeNumber, Number: &notion.NumberMetadata{}},
+			"Tags":        {Type: notion.DBPropTypeMultiSelect, MultiSelect: &notion.SelectMetadata{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	props := func(name string, estimate float64) notion.DatabasePageProperties {
+		return notion.DatabasePageProperties{
+			"External ID": {RichText: []notion.RichText{{Text: &notion.Text{Content: "EXT-1"}}}},
+			"Name":        {Title: []notion.RichText{{Text: &notion.Text{Content: name}}}},
+			"Estimate":    {Number: notion.Float64Ptr(estimate)},
+			"Tags":        {MultiSelect: []notion.SelectOptions{{Name: "bug"}}},
+		}
+	}
+
+	steps := []struct {
+		name          string
+		keyProp       string
+		keyValue      interface{}
+		props         notion.DatabasePageProperties
+		expAction     notion.UpsertAction
+		expOperations []string
+	}{
+		{
+			name:          "create",
+			keyProp:       "External ID",
+			keyValue:      "EXT-1",
+			props:         props("Fix login", 3),
+			expAction:     notion.UpsertActionCreated,
+			expOperations: []string{"QueryDatabase", "CreatePage"},
+		},
+		{
+			name:          "unchanged",
+			keyProp:       "External ID",
+			keyValue:      "EXT-1",
+			props:         props("Fix login", 3),
+			expAction:     notion.UpsertActionUnchanged,
+			expOperations: []string{"QueryDatabase"},
+		},
+		{
+			name:          "update",
+			keyProp:       "External ID",
+			keyValue:      "EXT-1",
+			props:         props("Fix login", 5),
+			expAction:     notion.UpsertActionUpdated,
+			expOperations: []string{"QueryDatabase", "UpdatePage"},
+		},
+		{
+			name:     "create with key from schema",
+			keyProp:  "Number",
+			keyValue: 42,
+			props: notion.DatabasePageProperties{
+				"Name": {Title: []notion.RichText{{Text: &notion.Text{Content: "Add export"}}}},
+			},
+			expAction:     notion.UpsertActionCreated,
+			expOperations: []string{"FindDatabaseByID", "QueryDatabase", "CreatePage"},
+		},
+	}
+
+	var pageID string
+	for _, step := range steps {
+		operations = nil
+
+		res, err := client.UpsertDatabasePage(ctx, db.ID, step.keyProp, step.keyValue, step.props)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", step.name, err)
+		}
+		if res.Action != step.expAction {
+			t.Errorf("%v: action not equal (expected: %v, got: %v)", step.name, step.expAction, res.Action)
+		}
+		if diff := cmp.Diff(step.expOperations, operations); diff != "" {
+			t.Errorf("%v: operations not equal (-exp, +got):\n%v", step.name, diff)
+		}
+		if step.keyProp == "External ID" {
+			if pageID != "" && res.Page.ID != pageID {
+				t.Errorf("%v: expected page %v to be upserted, got: %v", step.name, pageID, res.Page.ID)
+			}
+			pageID = res.Page.ID
+		}
+	}
+
+	page, err := client.FindPageByID(ctx, pageID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if estimate := page.Properties.(notion.DatabasePageProperties)["Estimate"].Number; estimate == nil || *estimate != 5 {
+		t.Fatalf("expected estimate to be updated, got: %v", estimate)
+	}
+
+	created, err := client.UpsertDatabasePage(ctx, db.ID, "Number", 42, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if number := created.Page.Properties.(notion.DatabasePageProperties)["Number"].Number; number == nil || *number != 42 {
+		t.Fatalf("expected key property to be set, got: %v", number)
+	}
+
+	// Keys aren't limited to 32-bit integers.
+	created, err = client.UpsertDatabasePage(ctx, db.ID, "Number", float64(3e9), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if number := created.Page.Properties.(notion.DatabasePageProperties)["Number"].Number; number == nil || *number != 3e9 {
+		t.Fatalf("expected key property to be set, got: %v", number)
+	}
+
+	duplicate := props("Fix login (copy)", 1)
+	if _, err := client.CreatePage(ctx, notion.CreatePageParams{
+		ParentType:             notion.ParentTypeDatabase,
+		ParentID:               db.ID,
+		DatabasePageProperties: &duplicate,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = client.UpsertDatabasePage(ctx, db.ID, "External ID", "EXT-1", props("Fix login", 5))
+	var dupErr *notion.DuplicateKeyError
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("expected *notion.DuplicateKeyError, got: %v", err)
+	}
+	if len(dupErr.PageIDs) != 2 {
+		t.Fatalf("expected 2 duplicate pages, got: %v", dupErr.PageIDs)
+	}
+
+	if _, err := client.UpsertDatabasePage(ctx, db.ID, "Tags", "bug", nil); err == nil {
+		t.Fatal("expected error for unsupported key property type")
+	}
+}
+
+func TestUpsertDatabasePageUniqueID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		keyValue  interface{}
+		results   string
+		expFilter string
+		expErr    error
+	}{
+		{
+			name:      "number",
+			keyValue:  12,
+			results:   `[{"object": "page", "id": "page-id", "parent": {"type": "database_id", "database_id": "db-id"}, "properties": {"Name": {"id": "title", "type": "title", "title": []}}}]`,
+			expFilter: `{"property":"ID","unique_id":{"equals":12}}`,
+		},
+		{
+			name:      "prefixed string",
+			keyValue:  "TASK-12",
+			results:   `[]`,
+			expFilter: `{"property":"ID","unique_id":{"equals":12}}`,
+			expErr:    notion.ErrObjectNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var operations []string
+			httpClient := &http.Client{
+				Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+					operations = append(operations, r.Method+" "+r.URL.Path)
+
+					body := `{"object": "page", "id": "page-id", "parent": {"type": "database_id", "database_id": "db-id"}, "properties": {}}`
+					if strings.HasSuffix(r.URL.Path, "/query") {
+						var query struct {
+							Filter json.RawMessage `json:"filter"`
+						}
+						if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+							t.Fatal(err)
+						}
+						if diff := cmp.Diff(tt.expFilter, string(query.Filter)); diff != "" {
+							t.Errorf("filter not equal (-exp, +got):\n%v", diff)
+						}
+						body = `{"object": "list", "results": ` + tt.results + `, "has_more": false}`
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(strings.NewReader(body)),
+					}, nil
+				}},
+			}
+			client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+
+			props := notion.DatabasePageProperties{
+				"ID":   {UniqueID: &notion.UniqueID{Number: 12}},
+				"Name": {Title: notion.TextRichText("Fix login")},
+			}
+
+			_, err := client.UpsertDatabasePage(context.Background(), "db-id", "ID", tt.keyValue, props)
+			if !errors.Is(err, tt.expErr) {
+				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+			}
+
+			expOperations := []string{"POST /v1/databases/db-id/query"}
+			if tt.expErr == nil {
+				expOperations = append(expOperations, "PATCH /v1/pages/page-id")
+			}
+			if diff := cmp.Diff(expOperations, operations); diff != "" {
+				t.Fatalf("operations not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestPropertyValuesEqual(t *testing.T) {
+	t.Parallel()
+
+	date := func(s string) *notion.Date {
+		dt, err := notion.ParseDateTime(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &notion.Date{Start: dt}
+	}
+
+	tests := []struct {
+		name     string
+		current  notion.DatabasePageProperty
+		desired  notion.DatabasePageProperty
+		expEqual bool
+	}{
+		{
+			name: "rich text with API fields",
+			current: notion.DatabasePageProperty{
+				Type: notion.DBPropTypeRichText,
+				RichText: []notion.RichText{
+					{
+						Type:        notion.RichTextTypeText,
+						PlainText:   "foo",
+						Text:        &notion.Text{Content: "foo"},
+						Annotations: &notion.Annotations{Color: notion.ColorDefault},
+					},
+				},
+			},
+			desired: notion.DatabasePageProperty{
+				RichText: []notion.RichText{{Text: &notion.Text{Content: "foo"}}},
+			},
+			expEqual: true,
+		},
+		{
+			name: "rich text split into segments",
+			current: notion.DatabasePageProperty{
+				Type:     notion.DBPropTypeRichText,
+				RichText: []notion.RichText{{Text: &notion.Text{Content: "foobar"}}},
+			},
+			desired: notion.DatabasePageProperty{
+				RichText: []notion.RichText{{Text: &notion.Text{Content: "foo"}}, {Text: &notion.Text{Content: "bar"}}},
+			},
+			expEqual: true,
+		},
+		{
+			name: "rich text with different annotations",
+			current: notion.DatabasePageProperty{
+				Type:     notion.DBPropTypeRichText,
+				RichText: []notion.RichText{{Text: &notion.Text{Content: "foo"}}},
+			},
+			desired: notion.DatabasePageProperty{
+				RichText: []notion.RichText{{Text: &notion.Text{Content: "foo"}, Annotations: &notion.Annotations{Bold: true}}},
+			},
+			expEqual: false,
+		},
+		{
+			name:     "different number",
+			current:  notion.DatabasePageProperty{Type: notion.DBPropTypeNumber, Number: notion.Float64Ptr(1)},
+			desired:  notion.DatabasePageProperty{Number: notion.Float64Ptr(2)},
+			expEqual: false,
+		},
+		{
+			name:     "clear number",
+			current:  notion.DatabasePageProperty{Type: notion.DBPropTypeNumber, Number: notion.Float64Ptr(1)},
+			desired:  notion.DatabasePageProperty{Type: notion.DBPropTypeNumber},
+			expEqual: false,
+		},
+		{
+			name: "select by name",
+			current: notion.DatabasePageProperty{
+				Type:   notion.DBPropTypeSelect,
+				Select: &notion.SelectOptions{ID: "a", Name: "Foo", Color: notion.ColorBlue},
+			},
+			desired:  notion.DatabasePageProperty{Select: &notion.SelectOptions{Name: "Foo"}},
+			expEqual: true,
+		},
+		{
+			name: "multi-select order",
+			current: notion.DatabasePageProperty{
+				Type:        notion.DBPropTypeMultiSelect,
+				MultiSelect: []notion.SelectOptions{{Name: "a"}, {Name: "b"}},
+			},
+			desired: notion.DatabasePageProperty{
+				MultiSelect: []notion.SelectOptions{{Name: "b"}, {Name: "a"}},
+			},
+			expEqual: true,
+		},
+		{
+			name: "multi-select with different options",
+			current: notion.DatabasePageProperty{
+				Type:        notion.DBPropTypeMultiSelect,
+				MultiSelect: []notion.SelectOptions{{Name: "a"}, {Name: "a"}},
+			},
+			desired: notion.DatabasePageProperty{
+				MultiSelect: []notion.SelectOptions{{Name: "a"}, {Name: "b"}},
+			},
+			expEqual: false,
+		},
+		{
+			name: "relation order",
+			current: notion.DatabasePageProperty{
+				Type:     notion.DBPropTypeRelation,
+				Relation: []notion.Relation{{ID: "a"}, {ID: "b"}},
+			},
+			desired:  notion.DatabasePageProperty{Relation: []notion.Relation{{ID: "b"}, {ID: "a"}}},
+			expEqual: true,
+		},
+		{
+			name: "people order",
+			current: notion.DatabasePageProperty{
+				Type:   notion.DBPropTypePeople,
+				People: []notion.User{{BaseUser: notion.BaseUser{ID: "a"}}, {BaseUser: notion.BaseUser{ID: "b"}}},
+			},
+			desired: notion.DatabasePageProperty{
+				People: []notion.User{{BaseUser: notion.BaseUser{ID: "b"}}, {BaseUser: notion.BaseUser{ID: "a"}}},
+			},
+			expEqual: true,
+		},
+		{
+			name:     "date",
+			current:  notion.DatabasePageProperty{Type: notion.DBPropTypeDate, Date: date("2021-05-10")},
+			desired:  notion.DatabasePageProperty{Date: date("2021-05-10")},
+			expEqual: true,
+		},
+		{
+			name:     "date with time",
+			current:  notion.DatabasePageProperty{Type: notion.DBPropTypeDate, Date: date("2021-05-10")},
+			desired:  notion.DatabasePageProperty{Date: date("2021-05-10T12:00:00.000Z")},
+			expEqual: false,
+		},
+		{
+			name:     "unchecked checkbox",
+			current:  notion.DatabasePageProperty{Type: notion.DBPropTypeCheckbox, Checkbox: notion.BoolPtr(false)},
+			desired:  notion.DatabasePageProperty{Type: notion.DBPropTypeCheckbox},
+			expEqual: true,
+		},
+		{
+			name: "relation",
+			current: notion.DatabasePageProperty{
+				Type:     notion.DBPropTypeRelation,
+				Relation: []notion.Relation{{ID: "a"}},
+			},
+			desired:  notion.DatabasePageProperty{Relation: []notion.Relation{}},
+			expEqual: false,
+		},
+		{
+			name:     "read-only property",
+			current:  notion.DatabasePageProperty{Type: notion.DBPropTypeCreatedTime, CreatedTime: notion.TimePtr(time.Now())},
+			desired:  notion.DatabasePageProperty{Type: notion.DBPropTypeCreatedTime},
+			expEqual: true,
+		},
+		{
+			name:     "type mismatch",
+			current:  notion.DatabasePageProperty{Type: notion.DBPropTypeURL, URL: notion.StringPtr("https://example.com")},
+			desired:  notion.DatabasePageProperty{Email: notion.StringPtr("https://example.com")},
+			expEqual: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := notion.PropertyValuesEqual(tt.current, tt.desired); got != tt.expEqual {
+				t.Fatalf("equal not equal (expected: %v, got: %v)", tt.expEqual, got)
+			}
+		})
+	}
+}